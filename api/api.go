@@ -1,48 +1,77 @@
 package api
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
 	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	util "github.com/YoshiTheExplorer/TipMNEE/util"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/YoshiTheExplorer/TipMNEE/api/handlers"
 	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
+	"github.com/YoshiTheExplorer/TipMNEE/platform"
 )
 
 type Server struct {
-	store     *db.Queries
-	router    *gin.Engine
-	jwtSecret string
-	//googleAudiences []string
+	store           *db.Store
+	router          *gin.Engine
+	jwtSecret       string
+	googleAudiences []string
+	adminUserIDs    []int64
+	reverify        *handlers.ReverificationJob
+	linkSweep       *handlers.UnverifiedLinkSweepJob
+	payoutChanges   *handlers.PayoutChangeJob
+	ensWatch        *handlers.ENSWatchJob
+	claimRelayer    *handlers.ClaimRelayer
 }
 
-// func parseCSVEnv(key string) []string {
-// 	raw := strings.TrimSpace(os.Getenv(key))
-// 	if raw == "" { return nil }
-// 	parts := strings.Split(raw, ",")
-// 	out := make([]string, 0, len(parts))
-// 	for _, p := range parts {
-// 		p = strings.TrimSpace(p)
-// 		if p != "" { out = append(out, p) }
-// 	}
-// 	return out
-//  }
-
-func NewServer(store *db.Queries) *Server {
+func parseCSVEnv(key string) []string {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func NewServer(store *db.Store) *Server {
 	s := &Server{
 		store:     store,
 		router:    gin.New(),
 		jwtSecret: os.Getenv("JWT_SECRET"),
-		// googleAudiences: func() []string {
-		// 	if auds := parseCSVEnv("GOOGLE_CLIENT_IDS"); len(auds) > 0 {
-		// 		return auds
-		// 	}
-		// 	return parseCSVEnv("GOOGLE_CLIENT_ID")
-		// }(),
+		googleAudiences: func() []string {
+			if auds := parseCSVEnv("GOOGLE_CLIENT_IDS"); len(auds) > 0 {
+				return auds
+			}
+			return parseCSVEnv("GOOGLE_CLIENT_ID")
+		}(),
+	}
+
+	for _, raw := range parseCSVEnv("ADMIN_USER_IDS") {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			log.Fatal("missing/invalid env: ADMIN_USER_IDS (must be comma-separated user ids)")
+		}
+		s.adminUserIDs = append(s.adminUserIDs, id)
+	}
+
+	// Only proxies listed in TRUSTED_PROXIES may set the client IP through
+	// X-Forwarded-For; with none listed the peer address is used.
+	if err := s.router.SetTrustedProxies(parseCSVEnv("TRUSTED_PROXIES")); err != nil {
+		log.Fatal("missing/invalid env: TRUSTED_PROXIES (must be comma-separated IPs or CIDRs)")
 	}
 
 	// Global middleware
@@ -53,31 +82,96 @@ func NewServer(store *db.Queries) *Server {
 	// Health
 	s.router.GET("/health", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })
 
+	platforms := platform.NewRegistryFromEnv()
+
 	// Instantiate handlers
-	usersH := handlers.NewUsersHandler(store)
-	identitiesH := handlers.NewIdentitiesHandler(store, s.jwtSecret/*, s.googleAudiences*/)
-	socialH := handlers.NewSocialLinksHandler(store)
-	payoutsH := handlers.NewPayoutsHandler(store)
-	ledgerH := handlers.NewLedgerEventsHandler(store)
-	ledgerIngestH, err := handlers.NewLedgerIngestHandler(store)
+	usersH := handlers.NewUsersHandler(store.Queries)
+	// GOOGLE_OIDC_ISSUER lets tests point Google sign-in at a local OIDC stand-in.
+	googleVerifier := util.NewOIDCVerifier(os.Getenv("GOOGLE_OIDC_ISSUER"), s.googleAudiences)
+	resolveCache, err := handlers.NewResolveCache()
 	if err != nil {
 		log.Fatal(err)
 	}
-	claimsH, err := handlers.NewClaimsHandler(store)
+	resolveAttestor, err := handlers.NewResolveAttestor()
+	if err != nil {
+		log.Fatal(err)
+	}
+	stepUpH, err := handlers.NewStepUpHandler(store.Queries, googleVerifier)
+	if err != nil {
+		log.Fatal(err)
+	}
+	identitiesH := handlers.NewIdentitiesHandler(store, s.jwtSecret, googleVerifier, stepUpH)
+	mergeH, err := handlers.NewAccountMergeHandler(store, resolveCache, stepUpH)
+	if err != nil {
+		log.Fatal(err)
+	}
+	recoveryH, err := handlers.NewAccountRecoveryHandler(store, platforms, resolveCache)
+	if err != nil {
+		log.Fatal(err)
+	}
+	notificationsH := handlers.NewNotificationsHandler(store.Queries)
+	youtubeMeta, err := handlers.NewYouTubeMetadata(store.Queries, platforms)
+	if err != nil {
+		log.Fatal(err)
+	}
+	socialH, err := handlers.NewSocialLinksHandler(store, platforms, youtubeMeta, resolveCache, stepUpH, recoveryH)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.reverify, err = handlers.NewReverificationJob(socialH)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.linkSweep, err = handlers.NewUnverifiedLinkSweepJob(socialH)
+	if err != nil {
+		log.Fatal(err)
+	}
+	transfersH := handlers.NewChannelTransfersHandler(store, resolveCache, stepUpH)
+	ens, err := handlers.ENSFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	payoutsH, err := handlers.NewPayoutsHandler(store, platforms, youtubeMeta, resolveCache, resolveAttestor, stepUpH, ens)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.payoutChanges, err = handlers.NewPayoutChangeJob(payoutsH)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.ensWatch, err = handlers.NewENSWatchJob(payoutsH)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ledgerH := handlers.NewLedgerEventsHandler(store.Queries)
+	ledgerIngestH, err := handlers.NewLedgerIngestHandler(store, platforms)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.claimRelayer, err = handlers.NewClaimRelayer(store)
+	if err != nil {
+		log.Fatal(err)
+	}
+	claimsH, err := handlers.NewClaimsHandler(store, platforms, stepUpH, s.claimRelayer)
 	if err != nil {
 		log.Fatal(err)
 	}
-
 
 	// Public routes
 	public := s.router.Group("/api")
 	{
 		// Discovery
-		configH := handlers.NewConfigHandler()
+		configH := handlers.NewConfigHandler(resolveAttestor, s.claimRelayer)
 		public.GET("/config", configH.GetConfig)
 
 		// Resolve (public) - used by extension
-		public.GET("/resolve/youtube/:channelId", payoutsH.ResolveYouTubeChannelPayout)
+		public.GET("/resolve/:platform/:id", payoutsH.ResolveChannelPayout)
+		public.GET("/resolve/youtube/video/:videoId", payoutsH.VideoRateLimit(), payoutsH.ResolveYouTubeVideoPayout)
+		public.POST("/resolve/batch", payoutsH.BatchResolve)
+		public.GET("/profile/:platform/:id", socialH.GetProfile)
+
+		// Google redirects the browser here at the end of the oauth code flow
+		public.GET("/oauth/google/callback", socialH.OAuthCallback)
 
 		// Transactions
 		public.POST("/ledger/deposit", ledgerIngestH.RecordDeposit)
@@ -88,8 +182,7 @@ func NewServer(store *db.Queries) *Server {
 	{
 		auth.POST("/wallet/message", identitiesH.GetWalletLoginMessage)
 		auth.POST("/wallet", identitiesH.LoginWithWallet)
-		//Will add back in future
-		//auth.POST("/google", identitiesH.LoginWithGoogle)
+		auth.POST("/google", identitiesH.LoginWithGoogle)
 	}
 
 	// Protected routes
@@ -99,27 +192,95 @@ func NewServer(store *db.Queries) *Server {
 		// User
 		protected.GET("/me", usersH.GetMe)
 
+		// Identities (extra wallets on the same account)
+		protected.GET("/me/identities", identitiesH.ListMyIdentities)
+		protected.POST("/me/identities/wallet/message", identitiesH.GetWalletLinkMessage)
+		protected.POST("/me/identities/wallet", identitiesH.LinkWallet)
+		protected.POST("/me/identities/google", identitiesH.LinkGoogle)
+		protected.DELETE("/me/identities/:id", identitiesH.UnlinkIdentity)
+
+		// Step-up wallet re-authentication for sensitive actions
+		protected.POST("/me/step-up/message", stepUpH.GetStepUpMessage)
+
+		// Account merge (fold a duplicate account into this one)
+		protected.POST("/me/merge/message", mergeH.GetMergeMessage)
+		protected.POST("/me/merge", mergeH.MergeAccount)
+		protected.GET("/me/merges", mergeH.ListMyMerges)
+
+		// Inbox
+		protected.GET("/me/notifications", notificationsH.ListMyNotifications)
+		protected.POST("/me/notifications/:id/read", notificationsH.MarkNotificationRead)
+
+		// Account recovery (lost wallet -> new wallet via channel re-verification)
+		protected.POST("/recovery/:platform", recoveryH.StartRecovery)
+		protected.POST("/recovery/:platform/oauth/start", recoveryH.StartRecoveryOAuth)
+		protected.GET("/me/recoveries", recoveryH.ListMyRecoveries)
+		protected.POST("/me/recoveries/:id/cancel", recoveryH.CancelRecovery)
+		protected.POST("/me/recoveries/:id/complete", recoveryH.CompleteRecovery)
+
+		// Channel ownership transfers
+		protected.GET("/me/transfers", transfersH.ListMyTransfers)
+		protected.POST("/me/transfers/:id/dispute", transfersH.DisputeTransfer)
+		protected.POST("/me/transfers/:id/complete", transfersH.CompleteTransfer)
+
 		// Link socials
-		protected.POST("/social/youtube/link", socialH.LinkYouTubeChannel)
+		protected.POST("/social/:platform/link", socialH.LinkRateLimit(), socialH.LinkChannel)
+		protected.DELETE("/social/:platform/:id", socialH.UnlinkChannel)
 
 		// Payouts
+		protected.POST("/payouts/message", payoutsH.GetPayoutMessage)
 		protected.POST("/payouts", payoutsH.UpsertPayout)
+		protected.PUT("/payouts/:platform/:id", payoutsH.SetChannelPayout)
+		protected.DELETE("/payouts/:platform/:id", payoutsH.DeleteChannelPayout)
+		protected.GET("/payouts/:platform/:id/split", payoutsH.GetChannelSplit)
+		protected.PUT("/payouts/:platform/:id/split", payoutsH.SetChannelSplit)
+		protected.DELETE("/payouts/:platform/:id/split", payoutsH.DeleteChannelSplit)
+		protected.GET("/me/payout-history", payoutsH.ListMyPayoutHistory)
+		protected.POST("/me/payout-changes/:id/cancel", payoutsH.CancelPayoutChange)
 
 		// Earnings
 		protected.GET("/me/earnings", ledgerH.GetEarningsSummary)
 		protected.GET("/me/tips", ledgerH.ListMyTips)
+		protected.GET("/me/ownership", ledgerH.ListMyOwnershipEpochs)
 
 		// Transactions
 		protected.POST("/ledger/withdrawal", ledgerIngestH.RecordWithdrawal)
 
 		// Claims
-		protected.POST("/social/youtube/verify", socialH.VerifyYouTubeChannel)
-		protected.POST("/claims/youtube", claimsH.SignYouTubeClaim)
+		protected.POST("/social/:platform/verify", socialH.VerifyChannel)
+		protected.POST("/social/:platform/verify/code", socialH.LinkRateLimit(), socialH.IssueDescriptionCode)
+		protected.POST("/social/:platform/verify/code/check", socialH.CheckDescriptionCode)
+		protected.POST("/social/:platform/oauth/start", socialH.LinkRateLimit(), socialH.StartOAuth)
+		protected.POST("/claims/:platform", claimsH.SignClaim)
+		protected.POST("/claims/:platform/tx", claimsH.BuildClaimTx)
+		protected.POST("/claims/:platform/relay", claimsH.RelayClaim)
+		protected.GET("/me/claims", claimsH.ListMyClaims)
+		protected.GET("/me/claim-relays", claimsH.ListMyClaimRelays)
+		protected.GET("/me/claim-relays/:id", claimsH.GetClaimRelay)
+	}
+
+	// Admin routes (ADMIN_USER_IDS)
+	admin := s.router.Group("/api/admin")
+	admin.Use(middleware.AuthMiddleware(s.jwtSecret), middleware.RequireAdmin(s.adminUserIDs))
+	{
+		admin.GET("/transfers", transfersH.ListTransfers)
+		admin.POST("/transfers/:id/resolve", transfersH.ResolveTransfer)
 	}
 
 	return s
 }
 
 func (s *Server) Start(addr string) error {
+	if s.reverify != nil {
+		go s.reverify.Run(context.Background())
+	}
+	go s.linkSweep.Run(context.Background())
+	go s.payoutChanges.Run(context.Background())
+	if s.ensWatch != nil {
+		go s.ensWatch.Run(context.Background())
+	}
+	if s.claimRelayer != nil {
+		go s.claimRelayer.Run(context.Background())
+	}
 	return s.router.Run(addr)
 }
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+type AccountMergeHandler struct {
+	store        *db.Store
+	resolveCache *ResolveCache
+	stepUp       *StepUpHandler
+
+	changeCooldown time.Duration // PAYOUT_CHANGE_COOLDOWN
+}
+
+// NewAccountMergeHandler reads PAYOUT_CHANGE_COOLDOWN: keeping the merged
+// account's payout replaces the caller's address like any other change.
+func NewAccountMergeHandler(store *db.Store, resolveCache *ResolveCache, stepUp *StepUpHandler) (*AccountMergeHandler, error) {
+	cooldown, err := durationEnv("PAYOUT_CHANGE_COOLDOWN", 48*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountMergeHandler{store: store, resolveCache: resolveCache, stepUp: stepUp, changeCooldown: cooldown}, nil
+}
+
+func accountMergeMessage(addr string, survivingUserID, mergedUserID int64, nonce string, expires time.Time) string {
+	return fmt.Sprintf(
+		"TipMNEE wants you to merge account #%d into account #%d.\n\nEverything owned by account #%d will move to account #%d and it will be deleted.\n\nAddress: %s\nNonce: %s\nExpires: %s",
+		mergedUserID,
+		survivingUserID,
+		mergedUserID,
+		survivingUserID,
+		addr,
+		nonce,
+		expires.UTC().Format(time.RFC3339),
+	)
+}
+
+// mergeSource finds the other account that owns the wallet addr.
+func (h *AccountMergeHandler) mergeSource(c *gin.Context, userID int64, addr string) (int64, bool) {
+	ident, err := h.store.GetIdentity(c.Request.Context(), db.GetIdentityParams{
+		Provider:       "wallet",
+		ProviderUserID: addr,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no account uses this wallet"})
+			return 0, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read identity"})
+		return 0, false
+	}
+	if ident.UserID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "wallet already belongs to this account"})
+		return 0, false
+	}
+	return ident.UserID, true
+}
+
+// Protected: message a wallet of the other account must sign. Together with
+// the caller's JWT this proves control of both accounts.
+func (h *AccountMergeHandler) GetMergeMessage(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	var req walletMessageReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	addr := normalizeAddress(req.Address)
+	if !common.IsHexAddress(addr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ethereum address format"})
+		return
+	}
+
+	mergedUserID, ok := h.mergeSource(c, userID, addr)
+	if !ok {
+		return
+	}
+
+	message, err := issueWalletNonce(c.Request.Context(), h.store.Queries, addr, 10*time.Minute, func(nonce string, expires time.Time) string {
+		return accountMergeMessage(addr, userID, mergedUserID, nonce, expires)
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"address":        addr,
+		"message":        message,
+		"merged_user_id": mergedUserID,
+	})
+}
+
+type mergeAccountReq struct {
+	Address   string `json:"address" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
+	// "survivor" (default) keeps the caller's payout when both accounts have
+	// one on the same chain; "merged" switches to the other account's after
+	// PAYOUT_CHANGE_COOLDOWN.
+	PayoutConflict string `json:"payout_conflict"`
+}
+
+// Protected: merge the account owning req.Address into the caller's account.
+func (h *AccountMergeHandler) MergeAccount(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	var req mergeAccountReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conflict := req.PayoutConflict
+	switch conflict {
+	case "":
+		conflict = db.KeepSurvivorPayout
+	case db.KeepSurvivorPayout, db.KeepMergedPayout:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "payout_conflict must be survivor or merged"})
+		return
+	}
+
+	addr := normalizeAddress(req.Address)
+	if !common.IsHexAddress(addr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ethereum address format"})
+		return
+	}
+
+	mergedUserID, ok := h.mergeSource(c, userID, addr)
+	if !ok {
+		return
+	}
+
+	// The other account's signature proves it is the caller's too; step-up
+	// proves the caller is who the JWT says.
+	if !h.stepUp.Require(c, userID, stepUpAccountMerge, map[string]string{"address": addr}) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if err := consumeWalletNonce(ctx, h.store.Queries, addr, req.Signature, "/api/me/merge/message", func(nonce string, expires time.Time) string {
+		return accountMergeMessage(addr, userID, mergedUserID, nonce, expires)
+	}); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	result, err := h.store.MergeUsersTx(ctx, db.MergeUsersTxParams{
+		SurvivingUserID: userID,
+		MergedUserID:    mergedUserID,
+		ProofAddress:    addr,
+		PayoutConflict:  conflict,
+		PayoutCooldown:  h.changeCooldown,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to merge accounts"})
+		return
+	}
+	// Channels and payouts moved between users.
+	h.resolveCache.Purge()
+
+	for _, ch := range result.PayoutChanges {
+		if ch.Status == "pending" {
+			notifyPayoutChangePending(ctx, h.store.Queries, ch)
+		} else {
+			notifyPayoutChanged(ctx, h.store.Queries, ch)
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *AccountMergeHandler) ListMyMerges(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	merges, err := h.store.ListUserMergesForUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list merges"})
+		return
+	}
+
+	c.JSON(http.StatusOK, merges)
+}
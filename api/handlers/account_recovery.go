@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/YoshiTheExplorer/TipMNEE/platform"
+	util "github.com/YoshiTheExplorer/TipMNEE/util"
+	"github.com/gin-gonic/gin"
+)
+
+type AccountRecoveryHandler struct {
+	store     *db.Store
+	platforms *platform.Registry
+	delay     time.Duration
+	mailer    util.Mailer  // nil: notices only reach the inbox
+	oauth     *googleOAuth // nil when the oauth code flow isn't configured
+	rawTokens bool         // accept client-supplied access tokens on StartRecovery
+
+	resolveCache *ResolveCache
+}
+
+// NewAccountRecoveryHandler reads ACCOUNT_RECOVERY_DELAY, the waiting period
+// between starting a recovery and being allowed to complete it, the SMTP_*
+// settings used to mail notices to the recovered account's identities, and
+// the Google OAuth code-flow settings. Like channel verification, recovery
+// with a client-supplied access token is only allowed when RAW_TOKEN_VERIFY
+// is set.
+func NewAccountRecoveryHandler(store *db.Store, platforms *platform.Registry, resolveCache *ResolveCache) (*AccountRecoveryHandler, error) {
+	delay, err := durationEnv("ACCOUNT_RECOVERY_DELAY", 72*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	mailer, err := mailerFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	oauth, err := googleOAuthFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	rawTokens, err := boolEnv("RAW_TOKEN_VERIFY", false)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountRecoveryHandler{
+		store:        store,
+		platforms:    platforms,
+		delay:        delay,
+		mailer:       mailer,
+		oauth:        oauth,
+		rawTokens:    rawTokens,
+		resolveCache: resolveCache,
+	}, nil
+}
+
+type startRecoveryReq struct {
+	ID          string `json:"id"`
+	ChannelID   string `json:"channel_id"` // YouTube clients still send channel_id
+	AccessToken string `json:"access_token" binding:"required"`
+}
+
+/*
+Account recovery:
+  - Creator signs in with a new wallet (fresh user).
+  - Proves ownership of a channel verified by their old account through the
+    server-side OAuth code flow (StartRecoveryOAuth), or with an access token
+    of their own when RAW_TOKEN_VERIFY is set.
+  - After the waiting period the new user is folded into the old one, so the new
+    wallet logs straight into the old account. The old account is notified in
+    its inbox and by mail to its verified Google identities, and can cancel in
+    the meantime.
+*/
+func (h *AccountRecoveryHandler) StartRecovery(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+	if !h.rawTokens {
+		c.JSON(http.StatusForbidden, gin.H{"error": "access_token recovery is disabled; use /recovery/:platform/oauth/start"})
+		return
+	}
+
+	p, ok := providerParam(c, h.platforms)
+	if !ok {
+		return
+	}
+
+	var req startRecoveryReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channelID, ok := normalizeID(c, p, platformUserID(req.ID, req.ChannelID))
+	if !ok {
+		return
+	}
+	accessToken := strings.TrimSpace(req.AccessToken)
+
+	ctx := c.Request.Context()
+
+	sl, err := h.recoverableLink(ctx, userID, p.Name(), channelID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ok, verifyErr := p.VerifyOwnership(ctx, accessToken, channelID)
+	if verifyErr != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": verifyErr.Error()})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "oauth token does not own this " + p.Name() + " account"})
+		return
+	}
+
+	r, err := h.startRecovery(ctx, userID, sl)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
+
+// StartRecoveryOAuth begins the Google code flow for a recovery; the callback
+// (SocialLinksHandler.OAuthCallback) starts the recovery once the Google
+// account is shown to own the channel.
+func (h *AccountRecoveryHandler) StartRecoveryOAuth(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	if h.oauth == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "oauth code flow not configured"})
+		return
+	}
+
+	p, ok := providerParam(c, h.platforms)
+	if !ok {
+		return
+	}
+	if p.Name() != "youtube" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": p.Name() + " does not support the oauth code flow"})
+		return
+	}
+
+	var req linkSocialReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channelID, ok := normalizeID(c, p, platformUserID(req.ID, req.ChannelID))
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if _, err := h.recoverableLink(ctx, userID, p.Name(), channelID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	body, err := h.oauth.begin(ctx, h.store, userID, p.Name(), channelID, "recovery")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// recoverWithOAuth finishes a recovery code flow: accessToken was obtained by
+// the server for st, so a successful ownership check starts the recovery.
+func (h *AccountRecoveryHandler) recoverWithOAuth(ctx context.Context, st db.OauthState, p platform.Provider, accessToken string) (db.AccountRecovery, error) {
+	sl, err := h.recoverableLink(ctx, st.UserID, p.Name(), st.PlatformUserID)
+	if err != nil {
+		return db.AccountRecovery{}, err
+	}
+
+	owns, err := p.VerifyOwnership(ctx, accessToken, st.PlatformUserID)
+	if err != nil {
+		return db.AccountRecovery{}, newHTTPError(http.StatusBadGateway, err.Error())
+	}
+	if !owns {
+		return db.AccountRecovery{}, newHTTPError(http.StatusForbidden, "google account does not own this "+p.Name()+" channel")
+	}
+
+	return h.startRecovery(ctx, st.UserID, sl)
+}
+
+// recoverableLink returns the channel's link when another account holds a
+// verification the caller could recover.
+func (h *AccountRecoveryHandler) recoverableLink(ctx context.Context, userID int64, platformName, channelID string) (db.SocialLink, error) {
+	sl, err := h.store.GetSocialLinkByPlatformUser(ctx, db.GetSocialLinkByPlatformUserParams{
+		Platform:       platformName,
+		PlatformUserID: channelID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.SocialLink{}, newHTTPError(http.StatusNotFound, "no account has verified this channel; verify it instead")
+		}
+		return db.SocialLink{}, newHTTPError(http.StatusInternalServerError, "failed to read existing link from DB")
+	}
+	if !sl.VerifiedAt.Valid {
+		return db.SocialLink{}, newHTTPError(http.StatusNotFound, "no account has verified this channel; verify it instead")
+	}
+	if sl.UserID == userID {
+		return db.SocialLink{}, newHTTPError(http.StatusBadRequest, "channel already belongs to this account")
+	}
+	return sl, nil
+}
+
+// startRecovery opens a recovery of sl's account for userID and alerts the
+// account.
+func (h *AccountRecoveryHandler) startRecovery(ctx context.Context, userID int64, sl db.SocialLink) (db.AccountRecovery, error) {
+	r, err := h.store.CreateAccountRecovery(ctx, db.CreateAccountRecoveryParams{
+		NewUserID:      userID,
+		TargetUserID:   sl.UserID,
+		Platform:       sl.Platform,
+		PlatformUserID: sl.PlatformUserID,
+		EligibleAt:     time.Now().UTC().Add(h.delay),
+	})
+	if err != nil {
+		// Partial unique index: one pending recovery per channel.
+		return db.AccountRecovery{}, newHTTPError(http.StatusConflict, "a recovery for this channel is already pending")
+	}
+
+	alertUser(ctx, h.store.Queries, h.mailer, sl.UserID, "account_recovery_started", "TipMNEE account recovery started", fmt.Sprintf(
+		"Someone proved ownership of %s channel %s and asked to recover this account with a new wallet. "+
+			"It will be attached to this account after %s unless you cancel recovery #%d.",
+		sl.Platform, sl.PlatformUserID, r.EligibleAt.UTC().Format(time.RFC3339), r.ID,
+	))
+	return r, nil
+}
+
+func (h *AccountRecoveryHandler) ListMyRecoveries(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	items, err := h.store.ListAccountRecoveriesForUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list recoveries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func recoveryIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recovery id"})
+		return 0, false
+	}
+	return id, true
+}
+
+// Protected: either side (the old account or the new wallet) can cancel while pending.
+func (h *AccountRecoveryHandler) CancelRecovery(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	id, ok := recoveryIDParam(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	r, err := h.store.CancelAccountRecovery(ctx, db.CancelAccountRecoveryParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no pending recovery with this id"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel recovery"})
+		return
+	}
+
+	other := r.TargetUserID
+	if userID == r.TargetUserID {
+		other = r.NewUserID
+	}
+	alertUser(ctx, h.store.Queries, h.mailer, other, "account_recovery_cancelled", "TipMNEE account recovery cancelled", fmt.Sprintf(
+		"Recovery #%d for %s channel %s was cancelled.", r.ID, r.Platform, r.PlatformUserID,
+	))
+
+	c.JSON(http.StatusOK, r)
+}
+
+// Protected: the new wallet completes the recovery once the waiting period has passed.
+func (h *AccountRecoveryHandler) CompleteRecovery(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	id, ok := recoveryIDParam(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	result, err := h.store.CompleteAccountRecoveryTx(ctx, id, userID)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows, errors.Is(err, db.ErrRecoveryNotOwned):
+			c.JSON(http.StatusNotFound, gin.H{"error": "no recovery with this id"})
+		case errors.Is(err, db.ErrRecoveryNotPending):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, db.ErrRecoveryNotEligible):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to complete recovery"})
+		}
+		return
+	}
+	h.resolveCache.Purge()
+
+	alertUser(ctx, h.store.Queries, h.mailer, result.Recovery.TargetUserID, "account_recovery_completed", "TipMNEE account recovery completed", fmt.Sprintf(
+		"Recovery #%d completed: a new wallet was attached to this account via %s channel %s.",
+		result.Recovery.ID, result.Recovery.Platform, result.Recovery.PlatformUserID,
+	))
+
+	// The caller's user no longer exists; signing in again with the same wallet
+	// now resolves to the recovered account.
+	c.JSON(http.StatusOK, gin.H{
+		"recovery":       result.Recovery,
+		"user_id":        result.Recovery.TargetUserID,
+		"reauthenticate": true,
+	})
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// ownedChannelParam reads :platform/:id and checks the channel is linked to userID.
+func (h *PayoutsHandler) ownedChannelParam(c *gin.Context, userID int64) (string, string, bool) {
+	p, ok := providerParam(c, h.platforms)
+	if !ok {
+		return "", "", false
+	}
+	channelID, ok := h.channelParam(c, p)
+	if !ok {
+		return "", "", false
+	}
+
+	sl, err := h.store.GetSocialLinkByPlatformUser(c.Request.Context(), db.GetSocialLinkByPlatformUserParams{
+		Platform:       p.Name(),
+		PlatformUserID: channelID,
+	})
+	if err == sql.ErrNoRows || (err == nil && sl.UserID != userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "channel is not linked to this account"})
+		return "", "", false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load channel"})
+		return "", "", false
+	}
+	return p.Name(), channelID, true
+}
+
+/*
+Protected: send one channel's direct tips to its own address instead of the
+user default. The address proves control like POST /api/payouts, and
+replacing the address the channel currently resolves to waits out
+PAYOUT_CHANGE_COOLDOWN. Overrides are dropped when the channel changes hands.
+*/
+func (h *PayoutsHandler) SetChannelPayout(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	platformName, channelID, ok := h.ownedChannelParam(c, userID)
+	if !ok {
+		return
+	}
+
+	var req upsertPayoutReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !h.stepUp.Require(c, userID, stepUpPayoutChange, map[string]string{
+		"chain":    req.Chain,
+		"address":  req.Address,
+		"platform": platformName,
+		"id":       channelID,
+	}) {
+		return
+	}
+
+	cleanAddr, ensName, err := h.payoutAddress(c.Request.Context(), req.Address)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	method, err := h.provePayoutAddress(c.Request.Context(), userID, req.Chain, cleanAddr, req.Signature)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.changePayout(c, db.ChangePayoutTxParams{
+		UserID:         userID,
+		Chain:          req.Chain,
+		Platform:       platformName,
+		PlatformUserID: channelID,
+		Address:        cleanAddr,
+		ProofMethod:    method,
+		ENSName:        ensName,
+	})
+}
+
+// Protected: remove a channel's override (and any pending change to it); the
+// channel falls back to the user default. ?chain= defaults to ethereum.
+func (h *PayoutsHandler) DeleteChannelPayout(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	platformName, channelID, ok := h.ownedChannelParam(c, userID)
+	if !ok {
+		return
+	}
+	chain := c.DefaultQuery("chain", "ethereum")
+
+	if !h.stepUp.Require(c, userID, stepUpPayoutDelete, map[string]string{
+		"chain":    chain,
+		"platform": platformName,
+		"id":       channelID,
+	}) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	res, err := h.store.DeleteChannelPayoutTx(ctx, db.DeleteChannelPayoutTxParams{
+		UserID:         userID,
+		Platform:       platformName,
+		PlatformUserID: channelID,
+		Chain:          chain,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove channel payout"})
+		return
+	}
+	if res.ChannelPayout == nil {
+		if res.Cancelled == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "channel has no payout override"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"removed": false, "cancelled_changes": res.Cancelled})
+		return
+	}
+	h.cache.Invalidate(platformName, channelID)
+
+	notifyUser(ctx, h.store.Queries, userID, "payout_changed", fmt.Sprintf(
+		"The %s payout address %s for %s channel %s was removed; its tips now go to your default payout address.",
+		chain, res.ChannelPayout.Address, platformName, channelID,
+	))
+
+	c.JSON(http.StatusOK, gin.H{"removed": true, "channel_payout": res.ChannelPayout, "change": res.Change, "cancelled_changes": res.Cancelled})
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+// maxSplitRecipients keeps split attestations and allocations small.
+const maxSplitRecipients = 20
+
+type splitReq struct {
+	Recipients []db.SplitRecipient `json:"recipients" binding:"required"`
+}
+
+// validSplit normalizes recipients and checks they add up to db.SplitBps.
+func validSplit(recipients []db.SplitRecipient) ([]db.SplitRecipient, error) {
+	if len(recipients) < 2 || len(recipients) > maxSplitRecipients {
+		return nil, newHTTPError(http.StatusBadRequest, fmt.Sprintf("a split needs 2 to %d recipients", maxSplitRecipients))
+	}
+
+	out := make([]db.SplitRecipient, len(recipients))
+	seen := make(map[string]bool, len(recipients))
+	total := 0
+	for i, r := range recipients {
+		addr := normalizeAddress(r.Address)
+		if !common.IsHexAddress(addr) {
+			return nil, newHTTPError(http.StatusBadRequest, "invalid recipient address: "+r.Address)
+		}
+		if seen[addr] {
+			return nil, newHTTPError(http.StatusBadRequest, "duplicate recipient address: "+addr)
+		}
+		seen[addr] = true
+		if r.Bps <= 0 {
+			return nil, newHTTPError(http.StatusBadRequest, "recipient bps must be > 0")
+		}
+		total += int(r.Bps)
+		out[i] = db.SplitRecipient{Address: addr, Bps: r.Bps, Label: strings.TrimSpace(r.Label)}
+	}
+	if total != db.SplitBps {
+		return nil, newHTTPError(http.StatusBadRequest, fmt.Sprintf("recipient bps must add up to %d, got %d", db.SplitBps, total))
+	}
+	return out, nil
+}
+
+func notifySplitChanged(ctx context.Context, store *db.Queries, split db.ChannelSplit) {
+	notifyUser(ctx, store, split.UserID, "payout_split_changed", fmt.Sprintf(
+		"Tips to %s channel %s are now split between %s.",
+		split.Platform, split.PlatformUserID, splitSummary(split),
+	))
+}
+
+// splitSummary lists a split's recipients, e.g. "0xabc… (60%), 0xdef… (40%)".
+func splitSummary(split db.ChannelSplit) string {
+	recipients, err := db.DecodeSplitRecipients(split.Recipients)
+	if err != nil {
+		return "its recipients"
+	}
+	parts := make([]string, len(recipients))
+	for i, r := range recipients {
+		parts[i] = fmt.Sprintf("%s (%g%%)", r.Address, float64(r.Bps)/100)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Protected: the channel's active split and any pending replacement.
+func (h *PayoutsHandler) GetChannelSplit(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	platformName, channelID, ok := h.ownedChannelParam(c, userID)
+	if !ok {
+		return
+	}
+
+	splits, err := h.store.ListOpenChannelSplits(c.Request.Context(), db.ListOpenChannelSplitsParams{
+		Platform:       platformName,
+		PlatformUserID: channelID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load split"})
+		return
+	}
+
+	c.JSON(http.StatusOK, splits)
+}
+
+/*
+Protected: split a channel's tips between collaborators by basis points
+(summing to 10000). Resolve then answers "split" with the recipients. Like a
+payout address change, a split for a channel whose tips already go somewhere
+waits out PAYOUT_CHANGE_COOLDOWN; a new split replaces any pending one.
+*/
+func (h *PayoutsHandler) SetChannelSplit(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	platformName, channelID, ok := h.ownedChannelParam(c, userID)
+	if !ok {
+		return
+	}
+
+	var req splitReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	recipients, err := validSplit(req.Recipients)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if !h.stepUp.Require(c, userID, stepUpSplitChange, map[string]string{
+		"platform":   platformName,
+		"id":         channelID,
+		"recipients": splitStepUpParam(recipients),
+	}) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	split, err := h.store.SetChannelSplitTx(ctx, db.SetChannelSplitTxParams{
+		UserID:         userID,
+		Platform:       platformName,
+		PlatformUserID: channelID,
+		Recipients:     recipients,
+		Cooldown:       h.changeCooldown,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set split"})
+		return
+	}
+
+	if split.Status == "pending" {
+		notifyUser(ctx, h.store.Queries, userID, "payout_split_pending", fmt.Sprintf(
+			"Tips to %s channel %s will be split between %s from %s. Until then they go where they go now. "+
+				"If you did not request this, remove the pending split and secure your account.",
+			platformName, channelID, splitSummary(split), split.EffectiveAt.UTC().Format(time.RFC3339),
+		))
+		c.JSON(http.StatusAccepted, split)
+		return
+	}
+
+	h.cache.Invalidate(platformName, channelID)
+	notifySplitChanged(ctx, h.store.Queries, split)
+	c.JSON(http.StatusOK, split)
+}
+
+// Protected: cancel the pending split, or when there is none remove the
+// active one; tips then go to the channel's payout address again.
+func (h *PayoutsHandler) DeleteChannelSplit(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	platformName, channelID, ok := h.ownedChannelParam(c, userID)
+	if !ok {
+		return
+	}
+
+	if !h.stepUp.Require(c, userID, stepUpSplitDelete, map[string]string{
+		"platform": platformName,
+		"id":       channelID,
+	}) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	cancelled, err := h.store.EndChannelSplits(ctx, db.EndChannelSplitsParams{
+		ToStatus:       "cancelled",
+		Platform:       platformName,
+		PlatformUserID: channelID,
+		FromStatus:     "pending",
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove split"})
+		return
+	}
+	if cancelled > 0 {
+		notifyUser(ctx, h.store.Queries, userID, "payout_split_cancelled", fmt.Sprintf(
+			"The pending split for %s channel %s was cancelled.", platformName, channelID,
+		))
+		c.JSON(http.StatusOK, gin.H{"removed": false, "cancelled": true})
+		return
+	}
+
+	removed, err := h.store.EndChannelSplits(ctx, db.EndChannelSplitsParams{
+		ToStatus:       "removed",
+		Platform:       platformName,
+		PlatformUserID: channelID,
+		FromStatus:     "active",
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove split"})
+		return
+	}
+	if removed == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "channel has no split"})
+		return
+	}
+	h.cache.Invalidate(platformName, channelID)
+
+	notifyUser(ctx, h.store.Queries, userID, "payout_split_changed", fmt.Sprintf(
+		"The split for %s channel %s was removed; its tips now go to its payout address.", platformName, channelID,
+	))
+
+	c.JSON(http.StatusOK, gin.H{"removed": true, "cancelled": false})
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type ChannelTransfersHandler struct {
+	store        *db.Store
+	resolveCache *ResolveCache
+	stepUp       *StepUpHandler
+}
+
+func NewChannelTransfersHandler(store *db.Store, resolveCache *ResolveCache, stepUp *StepUpHandler) *ChannelTransfersHandler {
+	return &ChannelTransfersHandler{store: store, resolveCache: resolveCache, stepUp: stepUp}
+}
+
+/*
+Channel ownership transfers:
+  - Proving ownership of a channel someone else has verified opens a pending
+    transfer instead of moving the link (see SocialLinksHandler.markVerified).
+  - The previous owner is notified and can dispute during the cooling-off
+    period; claims for the channel are held while a transfer is open.
+  - Undisputed transfers are completed by the new owner once eligible.
+    Disputed ones wait for an admin.
+*/
+func (h *ChannelTransfersHandler) ListMyTransfers(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	items, err := h.store.ListChannelTransfersForUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list transfers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func transferIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer id"})
+		return 0, false
+	}
+	return id, true
+}
+
+type disputeTransferReq struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// Protected: the previous owner disputes a pending transfer.
+func (h *ChannelTransfersHandler) DisputeTransfer(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	id, ok := transferIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req disputeTransferReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	t, err := h.store.DisputeChannelTransfer(ctx, db.DisputeChannelTransferParams{
+		Reason: strings.TrimSpace(req.Reason),
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no pending transfer of your channel with this id"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to dispute transfer"})
+		return
+	}
+
+	notifyUser(ctx, h.store.Queries, t.ToUserID, "channel_transfer_disputed", fmt.Sprintf(
+		"The current owner of %s channel %s disputed transfer #%d. An admin will review it.",
+		t.Platform, t.PlatformUserID, t.ID,
+	))
+
+	c.JSON(http.StatusOK, t)
+}
+
+// Protected: the new owner completes an undisputed transfer once the
+// cooling-off period has passed.
+func (h *ChannelTransfersHandler) CompleteTransfer(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	id, ok := transferIDParam(c)
+	if !ok {
+		return
+	}
+	if !h.stepUp.Require(c, userID, stepUpTransferComplete, map[string]string{
+		"transfer_id": strconv.FormatInt(id, 10),
+	}) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	t, err := h.store.CompleteChannelTransferTx(ctx, db.CompleteChannelTransferTxParams{
+		TransferID: id,
+		ToUserID:   userID,
+	})
+	if err != nil {
+		respondTransferError(c, err)
+		return
+	}
+	h.resolveCache.Invalidate(t.Platform, t.PlatformUserID)
+
+	h.notifyResolved(c, t)
+	c.JSON(http.StatusOK, t)
+}
+
+func respondTransferError(c *gin.Context, err error) {
+	switch {
+	case err == sql.ErrNoRows, errors.Is(err, db.ErrTransferNotOwned):
+		c.JSON(http.StatusNotFound, gin.H{"error": "no transfer with this id"})
+	case errors.Is(err, db.ErrTransferNotPending):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, db.ErrTransferNotEligible):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to complete transfer"})
+	}
+}
+
+func (h *ChannelTransfersHandler) notifyResolved(c *gin.Context, t db.ChannelTransfer) {
+	ctx := c.Request.Context()
+
+	outcome := "moved to the requesting account"
+	if t.Status == "rejected" {
+		outcome = "stays with its current owner"
+	}
+	body := fmt.Sprintf("Transfer #%d of %s channel %s was %s: the channel %s.",
+		t.ID, t.Platform, t.PlatformUserID, t.Status, outcome)
+	if t.ResolutionNote.Valid {
+		body += " Note: " + t.ResolutionNote.String
+	}
+
+	notifyUser(ctx, h.store.Queries, t.ToUserID, "channel_transfer_"+t.Status, body)
+	if t.FromUserID.Valid {
+		notifyUser(ctx, h.store.Queries, t.FromUserID.Int64, "channel_transfer_"+t.Status, body)
+	}
+}
+
+// Admin: list transfers by status (default: disputed).
+func (h *ChannelTransfersHandler) ListTransfers(c *gin.Context) {
+	status := c.DefaultQuery("status", "disputed")
+
+	items, err := h.store.ListChannelTransfersByStatus(c.Request.Context(), status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list transfers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+type resolveTransferReq struct {
+	Decision string `json:"decision" binding:"required"` // "approve" | "reject"
+	Note     string `json:"note"`
+}
+
+// Admin: approve (move the channel now) or reject an open transfer.
+func (h *ChannelTransfersHandler) ResolveTransfer(c *gin.Context) {
+	adminID := middleware.MustUserID(c)
+
+	id, ok := transferIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req resolveTransferReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	note := strings.TrimSpace(req.Note)
+
+	ctx := c.Request.Context()
+
+	var (
+		t   db.ChannelTransfer
+		err error
+	)
+	switch strings.ToLower(strings.TrimSpace(req.Decision)) {
+	case "approve":
+		t, err = h.store.CompleteChannelTransferTx(ctx, db.CompleteChannelTransferTxParams{
+			TransferID:  id,
+			AdminUserID: adminID,
+			Note:        note,
+		})
+	case "reject":
+		t, err = h.store.ResolveChannelTransfer(ctx, db.ResolveChannelTransferParams{
+			ID:             id,
+			Status:         "rejected",
+			ResolvedBy:     sql.NullInt64{Int64: adminID, Valid: true},
+			ResolutionNote: sql.NullString{String: note, Valid: note != ""},
+		})
+		if err == sql.ErrNoRows {
+			err = db.ErrTransferNotPending
+		}
+		if err == nil {
+			// The claimant's held refresh token is never used.
+			if _, derr := h.store.TakeChannelTransferCredential(ctx, t.ID); derr != nil && derr != sql.ErrNoRows {
+				log.Printf("drop transfer %d credential: %v", t.ID, derr)
+			}
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "decision must be approve or reject"})
+		return
+	}
+	if err != nil {
+		respondTransferError(c, err)
+		return
+	}
+	h.resolveCache.Invalidate(t.Platform, t.PlatformUserID)
+
+	h.notifyResolved(c, t)
+	c.JSON(http.StatusOK, t)
+}
+
+// claimHold responds and returns true while an open transfer holds claim
+// signing for the channel.
+func claimHold(c *gin.Context, store *db.Queries, platformName, channelID string) bool {
+	t, err := store.GetOpenChannelTransfer(c.Request.Context(), db.GetOpenChannelTransferParams{
+		Platform:       platformName,
+		PlatformUserID: channelID,
+	})
+	if err == sql.ErrNoRows {
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read channel transfers"})
+		return true
+	}
+
+	until := "an admin resolves the dispute"
+	if t.Status == "pending" && t.EligibleAt.Valid {
+		until = t.EligibleAt.Time.UTC().Format(time.RFC3339)
+	}
+	c.JSON(http.StatusConflict, gin.H{
+		"error":       "channel ownership is being transferred; claims are on hold until " + until,
+		"transfer_id": t.ID,
+	})
+	return true
+}
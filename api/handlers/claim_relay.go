@@ -0,0 +1,661 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/gin-gonic/gin"
+
+	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	util "github.com/YoshiTheExplorer/TipMNEE/util"
+)
+
+const (
+	claimRelayBatchSize = 50
+	// Claims expiring sooner than this are not sent: they would likely be
+	// mined after the escrow stops accepting them.
+	relayExpiryMargin = time.Minute
+
+	// Kinds of relay transactions.
+	relayTxClaim  = "claim"
+	relayTxCancel = "cancel"
+)
+
+/*
+ClaimRelayer pays the gas for creators who have escrowed MNEE but no ETH:
+  - POST /api/claims/:platform/relay queues a claim signed by the verifier,
+    within RELAYER_DAILY_LIMIT relays per user per 24h.
+  - Every RELAYER_EVERY the job sends queued claims from the hot wallet
+    (RELAYER_PRIVATE_KEY). Nonces come from the node and from relays still in
+    flight, so a restart does not reuse one. A Postgres advisory lock keyed
+    by the hot wallet keeps other API servers from sending at the same time.
+  - A transaction not mined within RELAYER_REPLACE_AFTER is replaced at the
+    same nonce with higher fees, up to RELAYER_MAX_FEE_GWEI per gas. Fees are
+    bumped from the latest attempt the node accepted; when none was, the
+    replacement is priced at the market.
+  - Once the claim signature expires the escrow would refuse the claim, so
+    an unmined transaction is replaced by a 0-value self-transfer at its
+    nonce; the relay fails when that cancellation is mined.
+  - A relay is confirmed (or failed, when the claim reverted) once its
+    transaction is RELAYER_CONFIRMATIONS blocks deep, and the creator is
+    notified. GET /api/me/claim-relays/:id shows every attempt.
+*/
+type ClaimRelayer struct {
+	store     *db.Store
+	relayer   *util.Relayer
+	address   string // hot wallet, lowercased
+	lockKey   int64  // advisory lock held while sending from address
+	escrow    common.Address
+	escrowABI abi.ABI
+
+	dailyLimit    int
+	replaceAfter  time.Duration
+	confirmations uint64
+	every         time.Duration
+}
+
+// ClaimRelayerConfig is how a ClaimRelayer sends and tracks relays.
+type ClaimRelayerConfig struct {
+	PrivateKey    string // hot wallet key, hex
+	ChainID       int64
+	Escrow        common.Address
+	MaxFeeCap     *big.Int      // wei per gas
+	DailyLimit    int           // relays per user per 24h; 0 = no quota
+	ReplaceAfter  time.Duration // before an unmined transaction gets higher fees
+	Confirmations uint64        // blocks deep before a relay is final
+	Every         time.Duration // between runs
+}
+
+// ClaimRelayerConfigFromEnv reads RELAYER_PRIVATE_KEY, CHAIN_ID,
+// ESCROW_CONTRACT and the RELAYER_* settings.
+func ClaimRelayerConfigFromEnv() (ClaimRelayerConfig, error) {
+	var cfg ClaimRelayerConfig
+	cfg.PrivateKey = strings.TrimSpace(os.Getenv("RELAYER_PRIVATE_KEY"))
+
+	chainIDStr := strings.TrimSpace(os.Getenv("CHAIN_ID"))
+	if chainIDStr == "" {
+		return cfg, errEnv("CHAIN_ID")
+	}
+	chainID, err := strconv.ParseInt(chainIDStr, 10, 64)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.ChainID = chainID
+
+	escrowStr := strings.TrimSpace(os.Getenv("ESCROW_CONTRACT"))
+	if !common.IsHexAddress(escrowStr) {
+		return cfg, errEnv("ESCROW_CONTRACT (must be 0x...)")
+	}
+	cfg.Escrow = common.HexToAddress(escrowStr)
+
+	maxFeeGwei, err := intEnv("RELAYER_MAX_FEE_GWEI", 100)
+	if err != nil {
+		return cfg, err
+	}
+	if maxFeeGwei == 0 {
+		return cfg, errEnv("RELAYER_MAX_FEE_GWEI (must be > 0)")
+	}
+	cfg.MaxFeeCap = new(big.Int).Mul(big.NewInt(int64(maxFeeGwei)), big.NewInt(1_000_000_000))
+
+	if cfg.DailyLimit, err = intEnv("RELAYER_DAILY_LIMIT", 3); err != nil {
+		return cfg, err
+	}
+	if cfg.ReplaceAfter, err = durationEnv("RELAYER_REPLACE_AFTER", 3*time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.ReplaceAfter == 0 {
+		return cfg, errEnv("RELAYER_REPLACE_AFTER (must be > 0)")
+	}
+	confirmations, err := intEnv("RELAYER_CONFIRMATIONS", 2)
+	if err != nil {
+		return cfg, err
+	}
+	if confirmations == 0 {
+		return cfg, errEnv("RELAYER_CONFIRMATIONS (must be > 0)")
+	}
+	cfg.Confirmations = uint64(confirmations)
+	if cfg.Every, err = durationEnv("RELAYER_EVERY", 15*time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.Every == 0 {
+		return cfg, errEnv("RELAYER_EVERY (must be > 0)")
+	}
+	return cfg, nil
+}
+
+// NewClaimRelayer returns nil when RELAYER_PRIVATE_KEY is unset (relaying
+// disabled). It sends through SEPOLIA_RPC_URL (or RPC_URL).
+func NewClaimRelayer(store *db.Store) (*ClaimRelayer, error) {
+	if strings.TrimSpace(os.Getenv("RELAYER_PRIVATE_KEY")) == "" {
+		return nil, nil
+	}
+	cfg, err := ClaimRelayerConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	rpcURL := rpcURLEnv()
+	if rpcURL == "" {
+		return nil, errEnv("SEPOLIA_RPC_URL (or RPC_URL) for RELAYER_PRIVATE_KEY")
+	}
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	return NewClaimRelayerWithChain(store, client, cfg)
+}
+
+// NewClaimRelayerWithChain is NewClaimRelayer over a given node and config,
+// such as the client of a simulated backend in tests.
+func NewClaimRelayerWithChain(store *db.Store, chain util.RelayChain, cfg ClaimRelayerConfig) (*ClaimRelayer, error) {
+	switch {
+	case cfg.MaxFeeCap == nil || cfg.MaxFeeCap.Sign() <= 0:
+		return nil, errors.New("claim relayer: MaxFeeCap must be > 0")
+	case cfg.DailyLimit < 0:
+		return nil, errors.New("claim relayer: DailyLimit must be >= 0")
+	case cfg.ReplaceAfter <= 0:
+		return nil, errors.New("claim relayer: ReplaceAfter must be > 0")
+	case cfg.Confirmations == 0:
+		return nil, errors.New("claim relayer: Confirmations must be > 0")
+	case cfg.Every <= 0:
+		return nil, errors.New("claim relayer: Every must be > 0")
+	}
+
+	relayer, err := util.NewRelayer(chain, cfg.PrivateKey, cfg.ChainID, cfg.MaxFeeCap)
+	if err != nil {
+		return nil, errors.New("claim relayer: PrivateKey must be a hex private key")
+	}
+
+	escrowABI, err := abi.JSON(strings.NewReader(tipEscrowABIJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClaimRelayer{
+		store:     store,
+		relayer:   relayer,
+		address:   normalizeAddress(relayer.Address.Hex()),
+		lockKey:   relayerLockKey(relayer.Address),
+		escrow:    cfg.Escrow,
+		escrowABI: escrowABI,
+
+		dailyLimit:    cfg.DailyLimit,
+		replaceAfter:  cfg.ReplaceAfter,
+		confirmations: cfg.Confirmations,
+		every:         cfg.Every,
+	}, nil
+}
+
+// Address is the hot wallet that sends relayed claims.
+func (j *ClaimRelayer) Address() string {
+	return j.address
+}
+
+// Run sends and tracks relays now and then every RELAYER_EVERY until ctx is done.
+func (j *ClaimRelayer) Run(ctx context.Context) {
+	t := time.NewTicker(j.every)
+	defer t.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("claim relayer: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// relayerLockKey derives the advisory lock key of a hot wallet from its
+// address.
+func relayerLockKey(addr common.Address) int64 {
+	return int64(binary.BigEndian.Uint64(crypto.Keccak256([]byte("claim-relayer"), addr.Bytes())))
+}
+
+// RunOnce settles transactions in flight before sending new ones, so nonces
+// freed by a requeue are reused right away. It does nothing while another
+// server holds the hot wallet's lock.
+func (j *ClaimRelayer) RunOnce(ctx context.Context) error {
+	unlock, ok, err := j.store.TryLockClaimRelayer(ctx, j.lockKey)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	defer unlock()
+
+	if err := j.track(ctx); err != nil {
+		return err
+	}
+
+	expired, err := j.store.ExpireQueuedClaimRelays(ctx, db.ExpireQueuedClaimRelaysParams{
+		RelayerAddress: j.address,
+		ExpiresBefore:  time.Now().Add(relayExpiryMargin),
+	})
+	if err != nil {
+		return err
+	}
+	for _, r := range expired {
+		notifyRelayFinished(ctx, j.store.Queries, r)
+	}
+
+	return j.send(ctx)
+}
+
+func (j *ClaimRelayer) calldata(r db.ClaimRelay) ([]byte, error) {
+	sig, err := hexutil.Decode(r.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return packClaim(j.escrowABI, util.PlatformChannelHash(r.Platform, r.PlatformUserID),
+		common.HexToAddress(r.PayoutAddress), r.Expiry.Unix(), common.HexToHash(r.ClaimNonce), sig)
+}
+
+// broadcast records tx as the relay's latest attempt, then sends it. When
+// sending fails the relay stays submitted: the node may have taken the
+// transaction anyway, and if not, the replacement after
+// RELAYER_REPLACE_AFTER sends it again (or it is requeued when another
+// transaction took its nonce).
+func (j *ClaimRelayer) broadcast(ctx context.Context, relayID int64, kind string, tx *types.Transaction) error {
+	_, err := j.store.RecordRelayAttemptTx(ctx, db.RecordRelayAttemptTxParams{
+		RelayID:   relayID,
+		TxHash:    tx.Hash().Hex(),
+		TxNonce:   int64(tx.Nonce()),
+		GasLimit:  int64(tx.Gas()),
+		GasTipCap: tx.GasTipCap().String(),
+		GasFeeCap: tx.GasFeeCap().String(),
+		Kind:      kind,
+	})
+	if err != nil {
+		return err
+	}
+	return j.push(ctx, tx)
+}
+
+// push sends a recorded attempt and marks it accepted once the node has it,
+// so only accepted attempts are outbid by replacements.
+func (j *ClaimRelayer) push(ctx context.Context, tx *types.Transaction) error {
+	if err := j.relayer.Send(ctx, tx); err != nil && !util.IsKnownTx(err) {
+		return fmt.Errorf("send %s: %w", tx.Hash().Hex(), err)
+	}
+	return j.store.MarkClaimRelayTxAccepted(ctx, tx.Hash().Hex())
+}
+
+func (j *ClaimRelayer) finish(ctx context.Context, id int64, status, reason string) {
+	r, err := j.store.FinishClaimRelay(ctx, db.FinishClaimRelayParams{
+		ID:     id,
+		Status: status,
+		Error:  sql.NullString{String: reason, Valid: reason != ""},
+	})
+	if err != nil {
+		log.Printf("finish claim relay %d: %v", id, err)
+		return
+	}
+	notifyRelayFinished(ctx, j.store.Queries, r)
+}
+
+// send signs and broadcasts queued relays in order. It stops at the first
+// one it cannot send, so nonces stay contiguous.
+func (j *ClaimRelayer) send(ctx context.Context) error {
+	queued, err := j.store.ListClaimRelaysByStatus(ctx, db.ListClaimRelaysByStatusParams{
+		RelayerAddress: j.address,
+		Status:         "queued",
+		MaxRelays:      claimRelayBatchSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range queued {
+		data, err := j.calldata(r)
+		if err != nil {
+			j.finish(ctx, r.ID, "failed", "invalid claim signature")
+			continue
+		}
+		floor, err := j.store.NextClaimRelayNonce(ctx, j.address)
+		if err != nil {
+			return err
+		}
+		nonce, err := j.relayer.NextNonce(ctx, uint64(floor))
+		if err != nil {
+			return err
+		}
+
+		tx, err := j.relayer.NewTx(ctx, nonce, j.escrow, data)
+		switch {
+		case util.IsRevert(err):
+			// Already claimed, nothing in escrow, or the signature was rotated out.
+			j.finish(ctx, r.ID, "failed", "claim would revert: "+err.Error())
+			continue
+		case errors.Is(err, util.ErrRelayFeeCap):
+			log.Printf("claim relayer: gas above RELAYER_MAX_FEE_GWEI; holding queued claims")
+			return nil
+		case err != nil:
+			return err
+		}
+
+		if err := j.broadcast(ctx, r.ID, relayTxClaim, tx); err != nil {
+			return fmt.Errorf("claim relay %d: %w", r.ID, err)
+		}
+	}
+	return nil
+}
+
+// track looks for receipts of relays in flight, confirming them once deep
+// enough and replacing transactions that are not getting mined.
+func (j *ClaimRelayer) track(ctx context.Context) error {
+	submitted, err := j.store.ListClaimRelaysByStatus(ctx, db.ListClaimRelaysByStatusParams{
+		RelayerAddress: j.address,
+		Status:         "submitted",
+		MaxRelays:      claimRelayBatchSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range submitted {
+		if err := j.trackOne(ctx, r); err != nil {
+			log.Printf("claim relay %d: %v", r.ID, err)
+		}
+	}
+	return nil
+}
+
+func (j *ClaimRelayer) trackOne(ctx context.Context, r db.ClaimRelay) error {
+	// Read the account nonce before the receipts: a transaction mined in
+	// between then shows up as a receipt rather than as a foreign nonce.
+	nonceUsed, err := j.relayer.NonceUsed(ctx, uint64(r.TxNonce.Int64))
+	if err != nil {
+		return err
+	}
+
+	attempts, err := j.store.ListClaimRelayTxs(ctx, r.ID)
+	if err != nil {
+		return err
+	}
+	if len(attempts) == 0 {
+		return errors.New("submitted without a recorded transaction")
+	}
+
+	for _, a := range attempts {
+		rc, depth, err := j.relayer.Receipt(ctx, common.HexToHash(a.TxHash))
+		if err != nil {
+			return err
+		}
+		if rc == nil {
+			continue
+		}
+
+		if err := j.store.SetClaimRelayMined(ctx, db.SetClaimRelayMinedParams{
+			ID:          r.ID,
+			TxHash:      sql.NullString{String: a.TxHash, Valid: true},
+			BlockNumber: sql.NullInt64{Int64: rc.BlockNumber.Int64(), Valid: true},
+			GasUsed:     sql.NullInt64{Int64: int64(rc.GasUsed), Valid: true},
+		}); err != nil {
+			return err
+		}
+		if depth < j.confirmations {
+			return nil
+		}
+		switch {
+		case a.Kind == relayTxCancel:
+			j.finish(ctx, r.ID, "failed", "claim signature expired before the transaction was mined; it was cancelled")
+		case rc.Status == types.ReceiptStatusSuccessful:
+			j.finish(ctx, r.ID, "confirmed", "")
+		default:
+			j.finish(ctx, r.ID, "failed", "claim transaction reverted")
+		}
+		return nil
+	}
+
+	// Every attempt after a cancellation is a cancellation too.
+	cancelling := attempts[len(attempts)-1].Kind == relayTxCancel
+
+	if nonceUsed {
+		if cancelling {
+			j.finish(ctx, r.ID, "failed", "claim signature expired before the transaction was mined")
+			return nil
+		}
+		// Another transaction from the hot wallet took the nonce.
+		return j.store.RequeueClaimRelay(ctx, db.RequeueClaimRelayParams{
+			ID:    r.ID,
+			Error: sql.NullString{String: fmt.Sprintf("nonce %d was used by another transaction; resending", r.TxNonce.Int64), Valid: true},
+		})
+	}
+
+	if !cancelling && !time.Now().Before(r.Expiry) {
+		// The escrow refuses the claim from now on; free the nonce.
+		return j.replace(ctx, r, relayTxCancel, attempts)
+	}
+	if time.Since(r.SubmittedAt.Time) < j.replaceAfter {
+		return nil
+	}
+	return j.replace(ctx, r, attempts[len(attempts)-1].Kind, attempts)
+}
+
+// acceptedFees are the fee caps of the latest attempt the node accepted, or
+// nil when it accepted none.
+func acceptedFees(attempts []db.ClaimRelayTx) (*big.Int, *big.Int, error) {
+	for i := len(attempts) - 1; i >= 0; i-- {
+		a := attempts[i]
+		if !a.Accepted {
+			continue
+		}
+		tipCap, ok := new(big.Int).SetString(a.GasTipCap, 10)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid gas_tip_cap %q", a.GasTipCap)
+		}
+		feeCap, ok := new(big.Int).SetString(a.GasFeeCap, 10)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid gas_fee_cap %q", a.GasFeeCap)
+		}
+		return tipCap, feeCap, nil
+	}
+	return nil, nil, nil
+}
+
+// replace sends a new attempt of kind at the relay's nonce, outbidding the
+// latest accepted one.
+func (j *ClaimRelayer) replace(ctx context.Context, r db.ClaimRelay, kind string, attempts []db.ClaimRelayTx) error {
+	tipCap, feeCap, err := acceptedFees(attempts)
+	if err != nil {
+		return err
+	}
+
+	var tx *types.Transaction
+	if kind == relayTxCancel {
+		tx, err = j.relayer.Cancellation(ctx, uint64(r.TxNonce.Int64), tipCap, feeCap)
+	} else {
+		var data []byte
+		data, err = j.calldata(r)
+		if err != nil {
+			return err
+		}
+		tx, err = j.relayer.Replacement(ctx, uint64(r.TxNonce.Int64), uint64(r.GasLimit.Int64), j.escrow, data, tipCap, feeCap)
+	}
+	if errors.Is(err, util.ErrRelayFeeCap) {
+		return nil // keep waiting at the current price
+	}
+	if err != nil {
+		return err
+	}
+
+	// Priced at the market again after refused attempts, it may be one of them.
+	for _, a := range attempts {
+		if a.TxHash == tx.Hash().Hex() {
+			return j.push(ctx, tx)
+		}
+	}
+	// A refused replacement stays recorded but unaccepted; the earlier
+	// attempts are still tracked and the next one bumps from theirs.
+	return j.broadcast(ctx, r.ID, kind, tx)
+}
+
+func notifyRelayFinished(ctx context.Context, store *db.Queries, r db.ClaimRelay) {
+	switch r.Status {
+	case "confirmed":
+		notifyUser(ctx, store, r.UserID, "claim_relay_confirmed", fmt.Sprintf(
+			"Your claim for %s channel %s was sent to %s in transaction %s; the gas was on us.",
+			r.Platform, r.PlatformUserID, r.PayoutAddress, r.TxHash.String,
+		))
+	default:
+		notifyUser(ctx, store, r.UserID, "claim_relay_"+r.Status, fmt.Sprintf(
+			"Your relayed claim for %s channel %s did not go through (%s). Request a new claim to try again.",
+			r.Platform, r.PlatformUserID, r.Error.String,
+		))
+	}
+}
+
+// Protected: queue a signed claim for the relayer to send and pay gas for.
+func (h *ClaimsHandler) RelayClaim(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+	if h.relayer == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "claim relaying is not enabled on this server"})
+		return
+	}
+
+	p, ok := providerParam(c, h.platforms)
+	if !ok {
+		return
+	}
+
+	var req signedClaimReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, ok := h.readSignedClaim(c, p, req)
+	if !ok {
+		return
+	}
+	if time.Until(time.Unix(claim.expiry, 0)) < 2*relayExpiryMargin {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "claim signature expires too soon to relay; request a new one"})
+		return
+	}
+	// The signature only proves the verifier signed the claim once; gas is
+	// only paid for the channel's current, live owner.
+	if !h.canClaim(c, userID, p.Name(), claim.channelID) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	j := h.relayer
+
+	// Refuse claims that would revert before they use up quota.
+	if _, err := j.relayer.Estimate(ctx, j.escrow, claim.data); err != nil {
+		if util.IsRevert(err) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "claim would revert: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to estimate gas"})
+		return
+	}
+
+	r, err := h.store.QueueClaimRelayTx(ctx, db.QueueClaimRelayTxParams{
+		CreateClaimRelayParams: db.CreateClaimRelayParams{
+			UserID:         userID,
+			Platform:       p.Name(),
+			PlatformUserID: claim.channelID,
+			PayoutAddress:  normalizeAddress(claim.payout.Hex()),
+			Expiry:         time.Unix(claim.expiry, 0).UTC(),
+			ClaimNonce:     claim.nonce.Hex(),
+			Signature:      hexutil.Encode(claim.signature),
+			RelayerAddress: j.address,
+		},
+		DailyLimit: j.dailyLimit,
+		Since:      time.Now().Add(-24 * time.Hour),
+	})
+	if errors.Is(err, db.ErrRelayQuota) {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error": fmt.Sprintf("relay quota reached (%d per 24h); send the claim yourself via /api/claims/%s/tx", j.dailyLimit, p.Name()),
+		})
+		return
+	}
+	if err != nil {
+		// Unique indexes: one relay per signature, one in flight per channel.
+		c.JSON(http.StatusConflict, gin.H{"error": "this claim, or another for the channel, is already being relayed"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, r)
+}
+
+// Protected: the user's relayed claims, newest first.
+func (h *ClaimsHandler) ListMyClaimRelays(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	relays, err := h.store.ListClaimRelaysForUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list claim relays"})
+		return
+	}
+
+	c.JSON(http.StatusOK, relays)
+}
+
+type claimRelayResp struct {
+	db.ClaimRelay
+	Transactions []db.ClaimRelayTx `json:"transactions"`
+}
+
+// Protected: status of one relayed claim with every transaction sent for it.
+func (h *ClaimsHandler) GetClaimRelay(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid claim relay id"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	r, err := h.store.GetClaimRelayForUser(ctx, db.GetClaimRelayForUserParams{ID: id, UserID: userID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "claim relay not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load claim relay"})
+		return
+	}
+	txs, err := h.store.ListClaimRelayTxs(ctx, r.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load claim relay transactions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, claimRelayResp{ClaimRelay: r, Transactions: txs})
+}
@@ -0,0 +1,203 @@
+//go:build simulated
+
+// These tests drive the claim relayer against go-ethereum's simulated backend
+// and a migrated database:
+//
+//	TEST_DATABASE_URL=postgres://... go test -tags simulated -run ClaimRelayer ./api/handlers
+
+package handlers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	_ "github.com/lib/pq"
+)
+
+// simEscrow has no code, so every claim call to it succeeds.
+var simEscrow = common.HexToAddress("0x00000000000000000000000000000000000E5C40")
+
+type simRelay struct {
+	j       *ClaimRelayer
+	backend *simulated.Backend
+	store   *db.Store
+	key     *ecdsa.PrivateKey
+}
+
+func newSimRelay(t *testing.T) *simRelay {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	conn, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	store := db.NewStore(conn)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		crypto.PubkeyToAddress(key.PublicKey): {Balance: big.NewInt(params.Ether)},
+	})
+	t.Cleanup(func() { backend.Close() })
+
+	j, err := NewClaimRelayerWithChain(store, backend.Client(), ClaimRelayerConfig{
+		PrivateKey:    hex.EncodeToString(crypto.FromECDSA(key)),
+		ChainID:       params.AllDevChainProtocolChanges.ChainID.Int64(),
+		Escrow:        simEscrow,
+		MaxFeeCap:     big.NewInt(1000 * params.GWei),
+		ReplaceAfter:  time.Nanosecond, // every run replaces what is unmined
+		Confirmations: 1,
+		Every:         time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &simRelay{j: j, backend: backend, store: store, key: key}
+}
+
+func randomHex(t *testing.T, n int) string {
+	t.Helper()
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// queue adds a relay for a new user and channel.
+func (s *simRelay) queue(t *testing.T) db.ClaimRelay {
+	t.Helper()
+	ctx := context.Background()
+	user, err := s.store.CreateUser(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := s.store.QueueClaimRelayTx(ctx, db.QueueClaimRelayTxParams{
+		CreateClaimRelayParams: db.CreateClaimRelayParams{
+			UserID:         user.ID,
+			Platform:       "youtube",
+			PlatformUserID: "UC" + randomHex(t, 11),
+			PayoutAddress:  normalizeAddress(aliceAddr.Hex()),
+			Expiry:         time.Now().Add(time.Hour).UTC(),
+			ClaimNonce:     "0x" + randomHex(t, 32),
+			Signature:      hexutil.Encode(make([]byte, 65)),
+			RelayerAddress: s.j.Address(),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func (s *simRelay) run(t *testing.T) {
+	t.Helper()
+	if err := s.j.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (s *simRelay) relay(t *testing.T, r db.ClaimRelay) (db.ClaimRelay, []db.ClaimRelayTx) {
+	t.Helper()
+	ctx := context.Background()
+	got, err := s.store.GetClaimRelayForUser(ctx, db.GetClaimRelayForUserParams{ID: r.ID, UserID: r.UserID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs, err := s.store.ListClaimRelayTxs(ctx, r.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got, txs
+}
+
+func TestClaimRelayerSendsReplacesAndConfirms(t *testing.T) {
+	s := newSimRelay(t)
+	r := s.queue(t)
+
+	s.run(t)
+	got, txs := s.relay(t, r)
+	if got.Status != "submitted" || len(txs) != 1 || !txs[0].Accepted {
+		t.Fatalf("after send: status %q, %d attempts; want submitted with 1 accepted attempt", got.Status, len(txs))
+	}
+
+	s.run(t)
+	got, txs = s.relay(t, r)
+	if len(txs) != 2 || !txs[1].Accepted || txs[1].TxNonce != txs[0].TxNonce {
+		t.Fatalf("after replace: %d attempts; want an accepted replacement at the same nonce", len(txs))
+	}
+	oldFee, _ := new(big.Int).SetString(txs[0].GasFeeCap, 10)
+	newFee, _ := new(big.Int).SetString(txs[1].GasFeeCap, 10)
+	if newFee.Cmp(oldFee) <= 0 {
+		t.Errorf("replacement fee cap %s, want above %s", newFee, oldFee)
+	}
+
+	s.backend.Commit()
+	s.run(t)
+	got, _ = s.relay(t, r)
+	if got.Status != "confirmed" || got.TxHash.String != txs[1].TxHash {
+		t.Errorf("after mining: status %q, tx %s; want confirmed with %s", got.Status, got.TxHash.String, txs[1].TxHash)
+	}
+}
+
+func TestClaimRelayerRequeuesOnForeignNonce(t *testing.T) {
+	s := newSimRelay(t)
+	r := s.queue(t)
+	ctx := context.Background()
+
+	s.run(t)
+	got, _ := s.relay(t, r)
+	nonce := uint64(got.TxNonce.Int64)
+
+	// Another sender using the hot wallet takes the nonce with higher fees.
+	to := crypto.PubkeyToAddress(s.key.PublicKey)
+	foreign, err := types.SignNewTx(s.key, types.LatestSignerForChainID(params.AllDevChainProtocolChanges.ChainID), &types.DynamicFeeTx{
+		ChainID:   params.AllDevChainProtocolChanges.ChainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(500 * params.GWei),
+		GasFeeCap: big.NewInt(900 * params.GWei),
+		Gas:       params.TxGas,
+		To:        &to,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.backend.Client().SendTransaction(ctx, foreign); err != nil {
+		t.Fatal(err)
+	}
+	s.backend.Commit()
+
+	// Requeued, then sent again at the next nonce in the same run.
+	s.run(t)
+	got, _ = s.relay(t, r)
+	if got.Status != "submitted" || uint64(got.TxNonce.Int64) != nonce+1 {
+		t.Fatalf("after foreign nonce: status %q at nonce %d; want submitted at %d", got.Status, got.TxNonce.Int64, nonce+1)
+	}
+
+	s.backend.Commit()
+	s.run(t)
+	got, _ = s.relay(t, r)
+	if got.Status != "confirmed" {
+		t.Errorf("after mining: status %q, want confirmed", got.Status)
+	}
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gin-gonic/gin"
+
+	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
+	"github.com/YoshiTheExplorer/TipMNEE/platform"
+	util "github.com/YoshiTheExplorer/TipMNEE/util"
+)
+
+// packClaim encodes the escrow call claim(channelIdHash, payoutAddress, expiry, nonce, signature).
+func packClaim(escrowABI abi.ABI, channelHash common.Hash, payout common.Address, expiry int64, nonce common.Hash, sig []byte) ([]byte, error) {
+	return escrowABI.Pack("claim", channelHash, payout, big.NewInt(expiry), nonce, sig)
+}
+
+// signedClaimReq is the answer of /api/claims/:platform plus the id and
+// payout address it was signed for.
+type signedClaimReq struct {
+	ID            string `json:"id"`
+	ChannelID     string `json:"channel_id"`
+	PayoutAddress string `json:"payout_address" binding:"required"`
+
+	ChannelIDHash string `json:"channel_id_hash"` // optional; checked against id
+	Expiry        int64  `json:"expiry" binding:"required"`
+	Nonce         string `json:"nonce" binding:"required"`
+	Signature     string `json:"signature" binding:"required"`
+}
+
+// signedClaim is an unexpired claim signed by this server's verifier.
+type signedClaim struct {
+	channelID string
+	payout    common.Address
+	expiry    int64
+	nonce     common.Hash
+	signature []byte
+	data      []byte // escrow claim(...) calldata
+}
+
+// readSignedClaim checks req against the verifier and answers 400 when it
+// is malformed, expired or not signed for this channel and payout address.
+func (h *ClaimsHandler) readSignedClaim(c *gin.Context, p platform.Provider, req signedClaimReq) (*signedClaim, bool) {
+	channelID, ok := normalizeID(c, p, platformUserID(req.ID, req.ChannelID))
+	if !ok {
+		return nil, false
+	}
+	channelHash := util.PlatformChannelHash(p.Name(), channelID)
+	if req.ChannelIDHash != "" && !strings.EqualFold(req.ChannelIDHash, channelHash.Hex()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "channel_id_hash does not match " + p.Name() + " id"})
+		return nil, false
+	}
+
+	if !common.IsHexAddress(req.PayoutAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payout_address"})
+		return nil, false
+	}
+	payout := common.HexToAddress(req.PayoutAddress)
+
+	if !isValidHexHash(req.Nonce) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid nonce"})
+		return nil, false
+	}
+	nonce := common.HexToHash(req.Nonce)
+
+	sig, err := hexutil.Decode(req.Signature)
+	if err != nil || len(sig) != 65 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature"})
+		return nil, false
+	}
+
+	if time.Now().Unix() >= req.Expiry {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "claim signature expired; request a new one"})
+		return nil, false
+	}
+
+	// Catch copy mistakes here rather than as a reverted transaction.
+	digest, err := util.ClaimDigest(h.chainID, h.escrowContract, channelHash, payout, req.Expiry, nonce)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to hash claim"})
+		return nil, false
+	}
+	signer, err := util.RecoverClaimSigner(digest, req.Signature)
+	if err != nil || signer != h.verifier {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "signature does not match this claim"})
+		return nil, false
+	}
+
+	data, err := packClaim(h.escrowABI, channelHash, payout, req.Expiry, nonce, sig)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encode claim"})
+		return nil, false
+	}
+
+	return &signedClaim{
+		channelID: channelID,
+		payout:    payout,
+		expiry:    req.Expiry,
+		nonce:     nonce,
+		signature: sig,
+		data:      data,
+	}, true
+}
+
+type claimTxReq struct {
+	signedClaimReq
+
+	// From is the sender the gas is estimated for; defaults to payout_address.
+	From string `json:"from"`
+}
+
+// claimTx uses the field names of eth_sendTransaction so wallets can send it as is.
+type claimTx struct {
+	From    common.Address  `json:"from"`
+	To      common.Address  `json:"to"`
+	Data    hexutil.Bytes   `json:"data"`
+	Value   *hexutil.Big    `json:"value"`
+	ChainID *hexutil.Big    `json:"chainId"`
+	Gas     *hexutil.Uint64 `json:"gas,omitempty"` // omitted when the server has no RPC
+}
+
+// Protected: turns a signed claim into a ready-to-send escrow claim(...)
+// transaction. The signature must be one this server issued for the channel
+// and payout address, and must not have expired.
+func (h *ClaimsHandler) BuildClaimTx(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	p, ok := providerParam(c, h.platforms)
+	if !ok {
+		return
+	}
+
+	var req claimTxReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, ok := h.readSignedClaim(c, p, req.signedClaimReq)
+	if !ok {
+		return
+	}
+
+	from := claim.payout
+	if req.From != "" {
+		if !common.IsHexAddress(req.From) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+			return
+		}
+		from = common.HexToAddress(req.From)
+	}
+
+	tx := claimTx{
+		From:    from,
+		To:      h.escrowContract,
+		Data:    claim.data,
+		Value:   (*hexutil.Big)(new(big.Int)),
+		ChainID: (*hexutil.Big)(big.NewInt(h.chainID)),
+	}
+	if h.gas != nil {
+		gas, err := h.gas.EstimateGas(c.Request.Context(), ethereum.CallMsg{
+			From: from,
+			To:   &h.escrowContract,
+			Data: claim.data,
+		})
+		if err != nil {
+			if util.IsRevert(err) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "claim would revert: " + err.Error()})
+				return
+			}
+			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to estimate gas"})
+			return
+		}
+		tx.Gas = (*hexutil.Uint64)(&gas)
+	}
+
+	c.JSON(http.StatusOK, tx)
+}
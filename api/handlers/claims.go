@@ -1,29 +1,46 @@
 package handlers
 
 import (
+	"database/sql"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/gin-gonic/gin"
 
 	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
 	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/YoshiTheExplorer/TipMNEE/platform"
 
 	util "github.com/YoshiTheExplorer/TipMNEE/util"
 )
 
 type ClaimsHandler struct {
-	store 		 	*db.Queries
+	store           *db.Store
+	platforms       *platform.Registry
 	chainID         int64
 	escrowContract  common.Address
 	verifierPrivKey string
+
+	requireProvenPayout bool
+	stepUp              *StepUpHandler
+
+	verifier  common.Address // signer of BuildClaimPayload claims
+	escrowABI abi.ABI
+	gas       ethereum.GasEstimator // nil: claim transactions carry no gas estimate
+	relayer   *ClaimRelayer         // nil: relaying disabled
 }
 
-func NewClaimsHandler(store *db.Queries) (*ClaimsHandler, error) {
+// NewClaimsHandler uses SEPOLIA_RPC_URL (or RPC_URL), when set, to estimate
+// gas for the claim transactions it builds.
+func NewClaimsHandler(store *db.Store, platforms *platform.Registry, stepUp *StepUpHandler, relayer *ClaimRelayer) (*ClaimsHandler, error) {
 	chainIDStr := strings.TrimSpace(os.Getenv("CHAIN_ID"))
 	if chainIDStr == "" {
 		return nil, errEnv("CHAIN_ID")
@@ -43,24 +60,59 @@ func NewClaimsHandler(store *db.Queries) (*ClaimsHandler, error) {
 		return nil, errEnv("VERIFIER_PRIVATE_KEY")
 	}
 
-	return &ClaimsHandler{
-		store: 			 store,
+	// CLAIM_REQUIRE_PROVEN_PAYOUT refuses claims to addresses that never
+	// signed a payout challenge (see /api/payouts/message).
+	requireProven, err := boolEnv("CLAIM_REQUIRE_PROVEN_PAYOUT", false)
+	if err != nil {
+		return nil, err
+	}
+
+	verifierKey, err := crypto.HexToECDSA(strings.TrimPrefix(verifierPK, "0x"))
+	if err != nil {
+		return nil, errEnv("VERIFIER_PRIVATE_KEY (must be a hex private key)")
+	}
+
+	escrowABI, err := abi.JSON(strings.NewReader(tipEscrowABIJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	h := &ClaimsHandler{
+		store:           store,
+		platforms:       platforms,
 		chainID:         chainID,
 		escrowContract:  common.HexToAddress(escrowStr),
 		verifierPrivKey: verifierPK,
-	}, nil
+
+		requireProvenPayout: requireProven,
+		stepUp:              stepUp,
+
+		verifier:  crypto.PubkeyToAddress(verifierKey.PublicKey),
+		escrowABI: escrowABI,
+		relayer:   relayer,
+	}
+	if rpcURL := rpcURLEnv(); rpcURL != "" {
+		client, err := ethclient.Dial(rpcURL)
+		if err != nil {
+			return nil, err
+		}
+		h.gas = client
+	}
+	return h, nil
 }
 
 type errEnv string
 
 func (e errEnv) Error() string { return "missing/invalid env: " + string(e) }
 
-type youtubeClaimReq struct {
-	ChannelID     string `json:"channel_id" binding:"required"`
-	PayoutAddress string `json:"payout_address" binding:"required"`
+type claimReq struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"` // YouTube clients still send channel_id
+	// PayoutAddress defaults to the channel's payout (override, else the user default).
+	PayoutAddress string `json:"payout_address"`
 }
 
-func (h *ClaimsHandler) SignYouTubeClaim(c *gin.Context) {
+func (h *ClaimsHandler) SignClaim(c *gin.Context) {
 	// RequireJWT middleware should set this
 	if _, ok := c.Get("user_id"); !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing auth"})
@@ -73,46 +125,79 @@ func (h *ClaimsHandler) SignYouTubeClaim(c *gin.Context) {
 		return
 	}
 
-	var req youtubeClaimReq
+	p, ok := providerParam(c, h.platforms)
+	if !ok {
+		return
+	}
+
+	var req claimReq
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	channelID := strings.TrimSpace(req.ChannelID)
-	if channelID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "channel_id required"})
+	channelID, ok := normalizeID(c, p, platformUserID(req.ID, req.ChannelID))
+	if !ok {
 		return
 	}
 
-	if !common.IsHexAddress(req.PayoutAddress) {
+	if req.PayoutAddress != "" && !common.IsHexAddress(req.PayoutAddress) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payout_address"})
 		return
 	}
-	payout := common.HexToAddress(req.PayoutAddress)
+
+	if !h.stepUp.Require(c, userID, stepUpClaim, map[string]string{
+		"platform":       p.Name(),
+		"id":             channelID,
+		"payout_address": req.PayoutAddress,
+	}) {
+		return
+	}
 
 	ctx := c.Request.Context()
-    sl, err := h.store.GetSocialLinkByPlatformUser(ctx, db.GetSocialLinkByPlatformUserParams{
-        Platform:       "youtube",
-        PlatformUserID: channelID,
-    })
-    if err != nil {
-        c.JSON(http.StatusForbidden, gin.H{"error": "channel not linked"})
-        return
-    }
-    if sl.UserID != userID {
-        c.JSON(http.StatusForbidden, gin.H{"error": "channel linked to another user"})
-        return
-    }
-    if !sl.VerifiedAt.Valid {
-        c.JSON(http.StatusForbidden, gin.H{"error": "channel not verified"})
-        return
-    }
+	if !h.canClaim(c, userID, p.Name(), channelID) {
+		return
+	}
+	if req.PayoutAddress == "" {
+		row, err := h.store.ResolvePayoutByChannelID(ctx, db.ResolvePayoutByChannelIDParams{
+			Platform:       p.Name(),
+			PlatformUserID: channelID,
+			Chain:          "ethereum",
+		})
+		// A split-only channel has no single address to default to.
+		if err == sql.ErrNoRows || (err == nil && row.Address == "") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "payout_address is required: no payout address is set for this channel"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to look up payout address"})
+			return
+		}
+		req.PayoutAddress = row.Address
+	}
+	if h.requireProvenPayout {
+		proven, err := h.store.IsProvenPayoutAddress(ctx, db.IsProvenPayoutAddressParams{
+			UserID:         userID,
+			Address:        normalizeAddress(req.PayoutAddress),
+			Platform:       p.Name(),
+			PlatformUserID: channelID,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check payout address"})
+			return
+		}
+		if !proven {
+			c.JSON(http.StatusForbidden, gin.H{"error": "payout_address is not a proven payout address; register it via /api/payouts first"})
+			return
+		}
+	}
+	payout := common.HexToAddress(req.PayoutAddress)
 
 	payload, err := util.BuildClaimPayload(
 		h.verifierPrivKey,
 		h.chainID,
 		h.escrowContract,
+		p.Name(),
 		channelID,
 		payout,
 		10*time.Minute,
@@ -122,5 +207,52 @@ func (h *ClaimsHandler) SignYouTubeClaim(c *gin.Context) {
 		return
 	}
 
+	// What the payout address owes split recipients is recorded once the
+	// claim executes (POST /api/ledger/withdrawal), not here: a signed claim
+	// may never be sent.
 	c.JSON(http.StatusOK, payload)
 }
+
+// canClaim responds and returns false unless userID holds a live verified
+// link to the channel and no transfer holds its claims.
+func (h *ClaimsHandler) canClaim(c *gin.Context, userID int64, platformName, channelID string) bool {
+	sl, err := h.store.GetSocialLinkByPlatformUser(c.Request.Context(), db.GetSocialLinkByPlatformUserParams{
+		Platform:       platformName,
+		PlatformUserID: channelID,
+	})
+	// Someone else's unverified link is not disclosed.
+	if err != nil || (sl.UserID != userID && !sl.VerifiedAt.Valid) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "channel not linked"})
+		return false
+	}
+	if sl.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "channel linked to another user"})
+		return false
+	}
+	if !sl.VerifiedAt.Valid {
+		c.JSON(http.StatusForbidden, gin.H{"error": "channel not verified"})
+		return false
+	}
+	if sl.StaleAt.Valid {
+		c.JSON(http.StatusForbidden, gin.H{"error": "channel verification lapsed; verify it again to claim"})
+		return false
+	}
+	return !claimHold(c, h.store.Queries, platformName, channelID)
+}
+
+// Protected: executed claims and how each divided among split recipients, newest first.
+func (h *ClaimsHandler) ListMyClaims(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	claims, err := h.store.ListClaimRecordsForUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list claims"})
+		return
+	}
+
+	c.JSON(http.StatusOK, claims)
+}
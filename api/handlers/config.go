@@ -5,25 +5,43 @@ import (
 	"os"
 	"strings"
 
+	util "github.com/YoshiTheExplorer/TipMNEE/util"
 	"github.com/gin-gonic/gin"
 )
 
-type ConfigHandler struct{}
+type ConfigHandler struct {
+	attestor *util.ResolveAttestor
+	relayer  *ClaimRelayer
+}
 
-func NewConfigHandler() *ConfigHandler {
-	return &ConfigHandler{}
+func NewConfigHandler(attestor *util.ResolveAttestor, relayer *ClaimRelayer) *ConfigHandler {
+	return &ConfigHandler{attestor: attestor, relayer: relayer}
 }
 
 func (h *ConfigHandler) GetConfig(c *gin.Context) {
 	chainID := os.Getenv("CHAIN_ID")
 	escrow := os.Getenv("ESCROW_CONTRACT")
-	
+
 	// Optional: Return the token address if useful for the extension
 	token := os.Getenv("TOKEN_CONTRACT")
 
+	// Extension checks resolve attestations against this address
+	resolverSigner := ""
+	if h.attestor != nil {
+		resolverSigner = strings.ToLower(h.attestor.Address.Hex())
+	}
+
+	// Empty when the server does not relay claims
+	claimRelayer := ""
+	if h.relayer != nil {
+		claimRelayer = h.relayer.Address()
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"chain_id":        chainID,
 		"escrow_contract": strings.ToLower(escrow),
 		"token_contract":  strings.ToLower(token),
+		"resolver_signer": resolverSigner,
+		"claim_relayer":   claimRelayer,
 	})
 }
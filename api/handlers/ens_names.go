@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	util "github.com/YoshiTheExplorer/TipMNEE/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const ensCheckBatchSize = 100
+
+// ENSFromEnv returns the on-chain ENS resolver over SEPOLIA_RPC_URL (or
+// RPC_URL) and the registry at ENS_REGISTRY (the ENS deployment by default),
+// or nil when no RPC URL is set.
+func ENSFromEnv() (util.ENSResolver, error) {
+	rpcURL := rpcURLEnv()
+	if rpcURL == "" {
+		return nil, nil
+	}
+	registry := util.ENSRegistryAddress
+	if raw := strings.TrimSpace(os.Getenv("ENS_REGISTRY")); raw != "" {
+		if !common.IsHexAddress(raw) {
+			return nil, errEnv("ENS_REGISTRY (must be 0x...)")
+		}
+		registry = common.HexToAddress(raw)
+	}
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	return util.NewENS(client, registry), nil
+}
+
+// payoutAddress reads a payout address given as 0x... or as an ENS name, and
+// returns the lowercased address and the normalized name ("" for an address).
+func (h *PayoutsHandler) payoutAddress(ctx context.Context, raw string) (string, string, error) {
+	raw = strings.TrimSpace(raw)
+	if !util.IsENSName(raw) {
+		addr := normalizeAddress(raw)
+		if !common.IsHexAddress(addr) {
+			return "", "", newHTTPError(http.StatusBadRequest, "invalid ethereum address format")
+		}
+		return addr, "", nil
+	}
+
+	if h.ens == nil {
+		return "", "", newHTTPError(http.StatusNotImplemented, "ENS names are not supported on this server")
+	}
+	name, err := util.NormalizeENSName(raw)
+	if err != nil {
+		return "", "", newHTTPError(http.StatusBadRequest, "invalid ENS name")
+	}
+	addr, err := h.ens.ResolveName(ctx, name)
+	if errors.Is(err, util.ErrENSNotFound) {
+		return "", "", newHTTPError(http.StatusBadRequest, name+" does not resolve to an address")
+	}
+	if err != nil {
+		return "", "", newHTTPError(http.StatusBadGateway, "failed to resolve ENS name")
+	}
+	return normalizeAddress(addr.Hex()), name, nil
+}
+
+// refreshReverseName stores addr's verified primary name for resolve and
+// /api/me, and drops cached resolve answers for channels paid to addr when the
+// name changed. Lookup errors are logged; the ENS watch retries later.
+func (h *PayoutsHandler) refreshReverseName(ctx context.Context, addr string) {
+	if h.ens == nil {
+		return
+	}
+	name, err := h.ens.LookupAddress(ctx, common.HexToAddress(addr))
+	if err != nil {
+		log.Printf("ens reverse lookup %s: %v", addr, err)
+		return
+	}
+	current := sql.NullString{String: name, Valid: name != ""}
+	prev, err := h.store.GetENSReverseName(ctx, addr)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("load ens reverse name %s: %v", addr, err)
+		return
+	}
+	if err := h.store.UpsertENSReverseName(ctx, db.UpsertENSReverseNameParams{
+		Address: addr,
+		Name:    current,
+	}); err != nil {
+		log.Printf("store ens reverse name %s: %v", addr, err)
+		return
+	}
+	if prev.Name == current {
+		return
+	}
+
+	channels, err := h.store.ListChannelsByPayoutAddress(ctx, db.ListChannelsByPayoutAddressParams{
+		Chain:   "ethereum",
+		Address: addr,
+	})
+	if err != nil {
+		// Can't tell which answers carry the old name; drop them all.
+		log.Printf("list channels paid to %s: %v", addr, err)
+		h.cache.Purge()
+		return
+	}
+	for _, ch := range channels {
+		h.cache.Invalidate(ch.Platform, ch.PlatformUserID)
+	}
+}
+
+/*
+ENSWatchJob re-checks ENS records every ENS_CHECK_EVERY:
+  - Names creators entered as payouts are resolved again once their last check
+    is older than ENS_CHECK_INTERVAL. When a name now points somewhere else
+    (or nowhere) every creator using it is alerted. Payouts keep the address
+    the name had when it was set; following the name means setting it again,
+    which goes through the payout proof and cooldown.
+  - Primary names shown by resolve and /api/me are refreshed on the same
+    schedule.
+*/
+type ENSWatchJob struct {
+	payouts  *PayoutsHandler
+	interval time.Duration
+	every    time.Duration
+}
+
+// NewENSWatchJob returns nil when ENS is unavailable (no RPC) or
+// ENS_CHECK_INTERVAL is 0 (disabled).
+func NewENSWatchJob(payouts *PayoutsHandler) (*ENSWatchJob, error) {
+	interval, err := durationEnv("ENS_CHECK_INTERVAL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	if interval == 0 || payouts.ens == nil {
+		return nil, nil
+	}
+	every, err := durationEnv("ENS_CHECK_EVERY", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	if every == 0 {
+		return nil, errEnv("ENS_CHECK_EVERY (must be > 0)")
+	}
+	return &ENSWatchJob{payouts: payouts, interval: interval, every: every}, nil
+}
+
+// Run checks due names now and then every ENS_CHECK_EVERY until ctx is done.
+func (j *ENSWatchJob) Run(ctx context.Context) {
+	t := time.NewTicker(j.every)
+	defer t.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("ens watch: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (j *ENSWatchJob) RunOnce(ctx context.Context) error {
+	store := j.payouts.store
+	checkedBefore := time.Now().UTC().Add(-j.interval)
+
+	names, err := store.ListENSNamesToCheck(ctx, db.ListENSNamesToCheckParams{
+		CheckedBefore: checkedBefore,
+		MaxNames:      ensCheckBatchSize,
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		j.checkName(ctx, name)
+	}
+
+	addrs, err := store.ListAddressesForReverseCheck(ctx, db.ListAddressesForReverseCheckParams{
+		CheckedBefore: checkedBefore,
+		MaxAddresses:  ensCheckBatchSize,
+	})
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		j.payouts.refreshReverseName(ctx, addr)
+	}
+	return nil
+}
+
+func (j *ENSWatchJob) checkName(ctx context.Context, name string) {
+	store := j.payouts.store
+
+	prev, err := store.GetENSName(ctx, name)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("ens watch %s: %v", name, err)
+		return
+	}
+	known := err == nil
+
+	current := sql.NullString{}
+	addr, err := j.payouts.ens.ResolveName(ctx, name)
+	switch {
+	case err == nil:
+		current = sql.NullString{String: normalizeAddress(addr.Hex()), Valid: true}
+	case errors.Is(err, util.ErrENSNotFound):
+	default:
+		// RPC trouble: try again on the next run.
+		log.Printf("ens watch %s: %v", name, err)
+		return
+	}
+
+	if err := store.UpsertENSName(ctx, db.UpsertENSNameParams{Name: name, Address: current}); err != nil {
+		log.Printf("ens watch %s: %v", name, err)
+		return
+	}
+	if !known || prev.Address == current {
+		return
+	}
+
+	payouts, err := store.ListPayoutsByENSName(ctx, sql.NullString{String: name, Valid: true})
+	if err != nil {
+		log.Printf("ens watch %s: %v", name, err)
+		return
+	}
+	for _, p := range payouts {
+		if current.Valid && p.Address == current.String {
+			continue
+		}
+		now := "no longer resolves to an address"
+		if current.Valid {
+			now = "now points to " + current.String
+		}
+		notifyUser(ctx, store.Queries, p.UserID, "ens_record_changed", fmt.Sprintf(
+			"The ENS name %s %s. Your %s stays %s; if you made this change, set %s as your payout again to follow it. "+
+				"If you did not, secure the name.",
+			name, now, payoutTarget(p.Chain, p.Platform, p.PlatformUserID), p.Address, name,
+		))
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/YoshiTheExplorer/TipMNEE/platform"
+	util "github.com/YoshiTheExplorer/TipMNEE/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// mockENS answers from fixed maps; err, when set, is returned for every call.
+type mockENS struct {
+	names   map[string]common.Address
+	reverse map[common.Address]string
+	err     error
+	calls   int
+}
+
+func (m *mockENS) ResolveName(_ context.Context, name string) (common.Address, error) {
+	m.calls++
+	if m.err != nil {
+		return common.Address{}, m.err
+	}
+	addr, ok := m.names[name]
+	if !ok {
+		return common.Address{}, util.ErrENSNotFound
+	}
+	return addr, nil
+}
+
+func (m *mockENS) LookupAddress(_ context.Context, addr common.Address) (string, error) {
+	m.calls++
+	if m.err != nil {
+		return "", m.err
+	}
+	return m.reverse[addr], nil
+}
+
+func newTestPayoutsHandler(t *testing.T, ens util.ENSResolver) *PayoutsHandler {
+	t.Helper()
+	t.Setenv("SEPOLIA_RPC_URL", "")
+	t.Setenv("RPC_URL", "")
+
+	h, err := NewPayoutsHandler(db.NewStore(emptyDB(t)), platform.NewRegistryFromEnv(), nil, nil, nil, nil, ens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+var aliceAddr = common.HexToAddress("0x00000000000000000000000000000000000A11CE")
+
+func TestPayoutAddressResolvesENSName(t *testing.T) {
+	ens := &mockENS{names: map[string]common.Address{"alice.eth": aliceAddr}}
+	h := newTestPayoutsHandler(t, ens)
+
+	addr, name, err := h.payoutAddress(context.Background(), " Alice.eth ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != normalizeAddress(aliceAddr.Hex()) || name != "alice.eth" {
+		t.Errorf("payoutAddress = %q, %q; want %q, %q", addr, name, normalizeAddress(aliceAddr.Hex()), "alice.eth")
+	}
+}
+
+func TestPayoutAddressSkipsResolverForAddresses(t *testing.T) {
+	ens := &mockENS{}
+	h := newTestPayoutsHandler(t, ens)
+
+	addr, name, err := h.payoutAddress(context.Background(), aliceAddr.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != normalizeAddress(aliceAddr.Hex()) || name != "" {
+		t.Errorf("payoutAddress = %q, %q; want %q, \"\"", addr, name, normalizeAddress(aliceAddr.Hex()))
+	}
+	if ens.calls != 0 {
+		t.Errorf("resolver calls = %d, want 0", ens.calls)
+	}
+}
+
+func TestPayoutAddressErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		ens    util.ENSResolver
+		raw    string
+		status int
+	}{
+		{"unknown name", &mockENS{}, "bob.eth", http.StatusBadRequest},
+		{"rpc failure", &mockENS{err: errors.New("connection refused")}, "alice.eth", http.StatusBadGateway},
+		{"no resolver", nil, "alice.eth", http.StatusNotImplemented},
+		{"bad address", &mockENS{}, "0x1234", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestPayoutsHandler(t, tt.ens)
+			_, _, err := h.payoutAddress(context.Background(), tt.raw)
+			var he *httpError
+			if !errors.As(err, &he) {
+				t.Fatalf("error = %v, want an httpError", err)
+			}
+			if he.status != tt.status {
+				t.Errorf("status = %d, want %d", he.status, tt.status)
+			}
+		})
+	}
+}
+
+func TestENSWatchJobNeedsResolver(t *testing.T) {
+	job, err := NewENSWatchJob(newTestPayoutsHandler(t, nil))
+	if err != nil || job != nil {
+		t.Errorf("without a resolver: job = %v, err = %v; want nil, nil", job, err)
+	}
+	job, err = NewENSWatchJob(newTestPayoutsHandler(t, &mockENS{}))
+	if err != nil || job == nil {
+		t.Errorf("with a resolver: job = %v, err = %v; want a job", job, err)
+	}
+}
+
+func TestRefreshReverseNameAsksResolver(t *testing.T) {
+	ens := &mockENS{reverse: map[common.Address]string{aliceAddr: "alice.eth"}}
+	h := newTestPayoutsHandler(t, ens)
+
+	h.refreshReverseName(context.Background(), normalizeAddress(aliceAddr.Hex()))
+	if ens.calls != 1 {
+		t.Errorf("resolver calls = %d, want 1", ens.calls)
+	}
+}
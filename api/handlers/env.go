@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durationEnv reads a Go duration (e.g. "72h") from key, falling back to def when unset.
+func durationEnv(key string, def time.Duration) (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, errEnv(key + " (must be a duration like 72h)")
+	}
+	return d, nil
+}
+
+// intEnv reads a non-negative integer from key, falling back to def when unset.
+func intEnv(key string, def int) (int, error) {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, errEnv(key + " (must be a non-negative integer)")
+	}
+	return n, nil
+}
+
+// boolEnv reads true/false (or 1/0) from key, falling back to def when unset.
+func boolEnv(key string, def bool) (bool, error) {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, errEnv(key + " (must be true or false)")
+	}
+	return b, nil
+}
+
+// rpcURLEnv is the Ethereum JSON-RPC endpoint (SEPOLIA_RPC_URL, or RPC_URL).
+func rpcURLEnv() string {
+	if u := strings.TrimSpace(os.Getenv("SEPOLIA_RPC_URL")); u != "" {
+		return u
+	}
+	return strings.TrimSpace(os.Getenv("RPC_URL"))
+}
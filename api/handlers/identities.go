@@ -1,50 +1,38 @@
 package handlers
 
 import (
+	"context"
 	"crypto/rand"
 	"database/sql"
 	"encoding/base64"
 	"fmt"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
 	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
 	util "github.com/YoshiTheExplorer/TipMNEE/util"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-
 type IdentitiesHandler struct {
-	store     *db.Queries
+	store     *db.Store
 	jwtSecret string
-	//Audiences []string
+	google    util.GoogleVerifier
+	stepUp    *StepUpHandler
 }
 
-// func NewIdentitiesHandler(store *db.Queries, jwtSecret string, googleAudiences []string) *IdentitiesHandler {
-// 	raw := strings.TrimSpace(os.Getenv("GOOGLE_CLIENT_IDS"))
-// 	if raw == "" {
-// 		raw = strings.TrimSpace(os.Getenv("GOOGLE_CLIENT_ID"))
-// 	}
-// 	var auds []string
-// 	for _, a := range strings.Split(raw, ",") {
-// 		a = strings.TrimSpace(a)
-// 		if a != "" {
-// 			auds = append(auds, a)
-// 		}
-// 	}
-// 	return &IdentitiesHandler{
-// 		store:           store,
-// 		jwtSecret:       jwtSecret,
-// 		googleAudiences: auds,
-// 	}
-// }
-
-func NewIdentitiesHandler(store *db.Queries, jwtSecret string) *IdentitiesHandler {
+func NewIdentitiesHandler(store *db.Store, jwtSecret string, google util.GoogleVerifier, stepUp *StepUpHandler) *IdentitiesHandler {
 	return &IdentitiesHandler{
 		store:     store,
 		jwtSecret: jwtSecret,
+		google:    google,
+		stepUp:    stepUp,
 	}
 }
 
@@ -106,30 +94,17 @@ func (h *IdentitiesHandler) GetWalletLoginMessage(c *gin.Context) {
 		return
 	}
 
-	addr := strings.ToLower(strings.TrimSpace(req.Address))
+	addr := normalizeAddress(req.Address)
 	if addr == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "address required"})
 		return
 	}
 
-	nonce, err := generateNonce()
+	message, err := issueWalletNonce(c.Request.Context(), h.store.Queries, addr, 10*time.Minute, func(nonce string, expires time.Time) string {
+		return walletLoginMessage(addr, nonce, expires)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate nonce"})
-		return
-	}
-
-	expires := time.Now().UTC().Add(10 * time.Minute).Truncate(time.Second)
-
-	message := walletLoginMessage(addr, nonce, expires)
-
-	ctx := c.Request.Context()
-	if err := h.store.UpsertLoginNonce(ctx, db.UpsertLoginNonceParams{
-		Address:   addr,
-		Nonce:     nonce,
-		ExpiresAt: expires,
-		Message:   message,
-	}); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store nonce"})
+		respondError(c, err)
 		return
 	}
 
@@ -137,8 +112,8 @@ func (h *IdentitiesHandler) GetWalletLoginMessage(c *gin.Context) {
 }
 
 type walletLoginReq struct {
-  Address   string `json:"address" binding:"required"`
-  Signature string `json:"signature" binding:"required"`
+	Address   string `json:"address" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
 }
 
 func (h *IdentitiesHandler) LoginWithWallet(c *gin.Context) {
@@ -148,7 +123,7 @@ func (h *IdentitiesHandler) LoginWithWallet(c *gin.Context) {
 		return
 	}
 
-	addr := strings.ToLower(strings.TrimSpace(req.Address))
+	addr := normalizeAddress(req.Address)
 	if addr == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "address required"})
 		return
@@ -156,87 +131,327 @@ func (h *IdentitiesHandler) LoginWithWallet(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
-	// 1) Must have a nonce issued for this address (forces /auth/wallet/message first)
-	ln, err := h.store.GetLoginNonce(ctx, addr)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing nonce: call /api/auth/wallet/message first"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read nonce"})
+	// Nonce must exist, be unexpired, and be signed by addr (forces /auth/wallet/message first).
+	// The message must be the login one: the nonce row is shared with the
+	// link, merge and payout-proof flows.
+	if err := consumeWalletNonce(ctx, h.store.Queries, addr, req.Signature, "/api/auth/wallet/message", func(nonce string, expires time.Time) string {
+		return walletLoginMessage(addr, nonce, expires)
+	}); err != nil {
+		respondError(c, err)
 		return
 	}
 
-	// 2) Check expiry (UTC + seconds)
-	now := time.Now().UTC().Truncate(time.Second)
-	if now.After(ln.ExpiresAt.UTC().Truncate(time.Second)) {
-		_ = h.store.DeleteLoginNonce(ctx, addr)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "nonce expired: call /api/auth/wallet/message again"})
+	// Any wallet attached to an account resolves to that account's user.
+	userID, err := h.findOrCreateUserForIdentity(ctx, "wallet", addr)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	// 3) Verify signature against the exact stored message
-	recovered, err := util.RecoverAddressFromPersonalSign(ln.Message, req.Signature)
+	token, err := h.mintJWT(userID)
 	if err != nil {
-		_ = h.store.DeleteLoginNonce(ctx, addr) // optional but good
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid signature"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to mint token"})
 		return
 	}
 
-	expected := strings.ToLower(strings.TrimSpace(ln.Address))
-	if strings.ToLower(strings.TrimSpace(recovered)) != expected {
-		_ = h.store.DeleteLoginNonce(ctx, addr) // optional but good
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error":     "signature does not match address",
-			"recovered": recovered,
-			"expected":  expected,
-			"canonical": ln.Message, // debug only; remove later
+	c.JSON(http.StatusOK, loginResp{AccessToken: token, UserID: userID})
+}
+
+// findOrCreateUserForIdentity returns the user owning (provider, providerUserID),
+// creating a fresh user and identity when none exists yet.
+func (h *IdentitiesHandler) findOrCreateUserForIdentity(ctx context.Context, provider, providerUserID string) (int64, error) {
+	ident, err := h.store.GetIdentity(ctx, db.GetIdentityParams{
+		Provider:       provider,
+		ProviderUserID: providerUserID,
+	})
+	if err == nil {
+		return ident.UserID, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, newHTTPError(http.StatusInternalServerError, "failed to read identity")
+	}
+
+	// Race protection: Try to create user and identity
+	u, err := h.store.CreateUser(ctx)
+	if err != nil {
+		return 0, newHTTPError(http.StatusInternalServerError, "failed to create user")
+	}
+
+	if _, err := h.store.CreateIdentity(ctx, db.CreateIdentityParams{
+		UserID:         u.ID,
+		Provider:       provider,
+		ProviderUserID: providerUserID,
+	}); err != nil {
+		// If creation failed, it's likely a race: someone else created it.
+		// Try fetching one last time.
+		ident2, err2 := h.store.GetIdentity(ctx, db.GetIdentityParams{
+			Provider:       provider,
+			ProviderUserID: providerUserID,
 		})
+		if err2 != nil {
+			return 0, newHTTPError(http.StatusInternalServerError, "failed to create identity")
+		}
+		return ident2.UserID, nil
+	}
+	return u.ID, nil
+}
+
+func walletLinkMessage(addr string, userID int64, nonce string, expires time.Time) string {
+	return fmt.Sprintf(
+		"TipMNEE wants you to link this Ethereum account to account #%d.\n\nAddress: %s\nNonce: %s\nExpires: %s",
+		userID,
+		addr,
+		nonce,
+		expires.UTC().Format(time.RFC3339),
+	)
+}
+
+// Protected: message the extra wallet must sign to be attached to the caller.
+func (h *IdentitiesHandler) GetWalletLinkMessage(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
 		return
 	}
 
-	// 4) One-time use nonce (prevents replay)
-	_ = h.store.DeleteLoginNonce(ctx, addr)
+	var req walletMessageReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	// ---- existing logic unchanged below ----
+	addr := normalizeAddress(req.Address)
+	if !common.IsHexAddress(addr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ethereum address format"})
+		return
+	}
 
-	ident, err := h.store.GetIdentity(ctx, db.GetIdentityParams{
-		Provider:       "wallet",
-		ProviderUserID: addr,
+	message, err := issueWalletNonce(c.Request.Context(), h.store.Queries, addr, 10*time.Minute, func(nonce string, expires time.Time) string {
+		return walletLinkMessage(addr, userID, nonce, expires)
 	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, walletMessageResp{Address: addr, Message: message})
+}
+
+// Protected: attach another wallet to the current user, proven by a signature
+// over the message from GetWalletLinkMessage.
+func (h *IdentitiesHandler) LinkWallet(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	var req walletLoginReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	addr := normalizeAddress(req.Address)
+	if !common.IsHexAddress(addr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ethereum address format"})
+		return
+	}
 
-	var userID int64
+	if !h.stepUp.Require(c, userID, stepUpWalletLink, map[string]string{"address": addr}) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if err := consumeWalletNonce(ctx, h.store.Queries, addr, req.Signature, "/api/me/identities/wallet/message", func(nonce string, expires time.Time) string {
+		return walletLinkMessage(addr, userID, nonce, expires)
+	}); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ident, err := h.attachIdentity(ctx, userID, "wallet", addr)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ident)
+}
+
+// attachIdentity adds (provider, providerUserID) to userID. It is a no-op when
+// the identity is already attached to userID and a conflict when another user
+// owns it.
+func (h *IdentitiesHandler) attachIdentity(ctx context.Context, userID int64, provider, providerUserID string) (db.Identity, error) {
+	existing, err := h.store.GetIdentity(ctx, db.GetIdentityParams{
+		Provider:       provider,
+		ProviderUserID: providerUserID,
+	})
 	if err == nil {
-		userID = ident.UserID
-	} else {
-		// Race protection: Try to create user and identity
-		u, err2 := h.store.CreateUser(ctx)
-		if err2 != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create user"})
-			return
+		if existing.UserID == userID {
+			return existing, nil
 		}
+		return db.Identity{}, newHTTPError(http.StatusConflict, "identity already belongs to another account; use account merge instead")
+	}
+	if err != sql.ErrNoRows {
+		return db.Identity{}, newHTTPError(http.StatusInternalServerError, "failed to read identity")
+	}
 
-		ident, err2 = h.store.CreateIdentity(ctx, db.CreateIdentityParams{
-			UserID:         u.ID,
-			Provider:       "wallet",
-			ProviderUserID: addr,
-		})
-		if err2 != nil {
-			// If creation failed, it's likely a race: someone else created it.
-			// Try fetching one last time.
-			ident2, err3 := h.store.GetIdentity(ctx, db.GetIdentityParams{
-				Provider:       "wallet",
-				ProviderUserID: addr,
-			})
-			if err3 != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create identity"})
-				return
-			}
-			userID = ident2.UserID
-		} else {
-			userID = u.ID
+	ident, err := h.store.CreateIdentity(ctx, db.CreateIdentityParams{
+		UserID:         userID,
+		Provider:       provider,
+		ProviderUserID: providerUserID,
+	})
+	if err != nil {
+		// Lost a race with another login/link for the same identity.
+		return db.Identity{}, newHTTPError(http.StatusConflict, "identity already belongs to another account")
+	}
+	return ident, nil
+}
+
+func (h *IdentitiesHandler) ListMyIdentities(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	idents, err := h.store.ListIdentitiesByUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list identities"})
+		return
+	}
+
+	c.JSON(http.StatusOK, idents)
+}
+
+// Protected: detach an identity. The last remaining identity cannot be removed,
+// otherwise the account would become unreachable.
+func (h *IdentitiesHandler) UnlinkIdentity(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	identityID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || identityID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid identity id"})
+		return
+	}
+
+	if !h.stepUp.Require(c, userID, stepUpIdentityUnlink, map[string]string{
+		"identity_id": strconv.FormatInt(identityID, 10),
+	}) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	err = h.store.UnlinkIdentityTx(ctx, userID, identityID)
+	switch {
+	case err == sql.ErrNoRows:
+		c.JSON(http.StatusNotFound, gin.H{"error": "identity not found"})
+		return
+	case err == db.ErrLastIdentity:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unlink identity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unlinked": true})
+}
+
+type googleLoginReq struct {
+	IDToken     string `json:"id_token"`
+	AccessToken string `json:"access_token"`
+}
+
+// googleIdentity verifies whichever Google credential the client sent. The
+// returned Subject is the stable "sub", never empty.
+func (h *IdentitiesHandler) googleIdentity(ctx context.Context, req googleLoginReq) (*util.OIDCIdentity, error) {
+	if h.google == nil {
+		return nil, newHTTPError(http.StatusServiceUnavailable, "google sign-in is not configured")
+	}
+
+	idToken := strings.TrimSpace(req.IDToken)
+	accessToken := strings.TrimSpace(req.AccessToken)
+
+	var ident *util.OIDCIdentity
+	var err error
+	switch {
+	case idToken != "":
+		ident, err = h.google.VerifyIDToken(ctx, idToken)
+		if err != nil {
+			return nil, newHTTPError(http.StatusUnauthorized, "invalid google id_token")
+		}
+
+	case accessToken != "":
+		// Only tokens issued to our own client ids; then userinfo gives "sub"
+		ident, err = h.google.VerifyAccessToken(ctx, accessToken)
+		if err != nil {
+			return nil, newHTTPError(http.StatusUnauthorized, "invalid google access_token")
 		}
+
+	default:
+		return nil, newHTTPError(http.StatusBadRequest, "provide id_token or access_token")
+	}
+
+	ident.Subject = strings.TrimSpace(ident.Subject)
+	if ident.Subject == "" {
+		return nil, newHTTPError(http.StatusUnauthorized, "google token missing subject")
+	}
+	return ident, nil
+}
+
+// verifiedEmail is NULL unless Google vouches for the address.
+func verifiedEmail(ident *util.OIDCIdentity) sql.NullString {
+	email := strings.TrimSpace(ident.Email)
+	return sql.NullString{String: email, Valid: ident.EmailVerified && email != ""}
+}
+
+// rememberGoogleEmail keeps the identity's verified email current, so
+// security notices (see alertUser) can reach the owner outside the app.
+func (h *IdentitiesHandler) rememberGoogleEmail(ctx context.Context, ident *util.OIDCIdentity) {
+	if err := h.store.SetIdentityEmail(ctx, db.SetIdentityEmailParams{
+		Provider:       "google",
+		ProviderUserID: ident.Subject,
+		Email:          verifiedEmail(ident),
+	}); err != nil {
+		log.Printf("store email of google identity %s: %v", ident.Subject, err)
+	}
+}
+
+/*
+Google login:
+  - Verify ID token (or access token via tokeninfo + userinfo), extract "sub".
+  - Then same logic as wallet:
+    identities(provider='google', provider_user_id=sub)
+*/
+func (h *IdentitiesHandler) LoginWithGoogle(c *gin.Context) {
+	var req googleLoginReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	gi, err := h.googleIdentity(ctx, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	userID, err := h.findOrCreateUserForIdentity(ctx, "google", gi.Subject)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
+	h.rememberGoogleEmail(ctx, gi)
 
 	token, err := h.mintJWT(userID)
 	if err != nil {
@@ -247,148 +462,40 @@ func (h *IdentitiesHandler) LoginWithWallet(c *gin.Context) {
 	c.JSON(http.StatusOK, loginResp{AccessToken: token, UserID: userID})
 }
 
-// type googleLoginReq struct {
-//   IDToken     string `json:"id_token"`
-//   AccessToken string `json:"access_token"`
-// }
-
-// func (h *IdentitiesHandler) validateGoogleIDToken(c *gin.Context, raw string) (*idtoken.Payload, error) {
-// 	// idtoken.Validate requires an "audience" string. If you have multiple client IDs
-// 	// (web + extension), we try each.
-// 	if len(h.googleAudiences) == 0 {
-// 		return nil, fmt.Errorf("missing GOOGLE_CLIENT_ID(S)")
-// 	}
-
-// 	ctx := c.Request.Context()
-// 	var lastErr error
-// 	for _, aud := range h.googleAudiences {
-// 		p, err := idtoken.Validate(ctx, raw, aud)
-// 		if err == nil {
-// 			return p, nil
-// 		}
-// 		lastErr = err
-// 	}
-// 	return nil, lastErr
-// }
-
-// /*
-// Google login:
-// - Verify ID token, extract "sub".
-// - Then same logic as wallet:
-//   identities(provider='google', provider_user_id=sub)
-// */
-// func (h *IdentitiesHandler) LoginWithGoogle(c *gin.Context) {
-// 	var req googleLoginReq
-// 	if err := c.ShouldBindJSON(&req); err != nil {
-// 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-// 		return
-// 	}
-
-// 	req.IDToken = strings.TrimSpace(req.IDToken)
-// 	req.AccessToken = strings.TrimSpace(req.AccessToken)
-
-// 	var sub string
-
-// 	switch {
-// 	case req.IDToken != "":
-// 		payload, err := h.validateGoogleIDToken(c, req.IDToken)
-// 		if err != nil {
-// 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid google id_token"})
-// 		return
-// 		}
-// 		sub = strings.TrimSpace(payload.Subject)
-
-// 	case req.AccessToken != "":
-// 		// OIDC userinfo: get stable subject ("sub") from access token
-// 		ui, err := fetchGoogleUserInfo(c.Request.Context(), req.AccessToken)
-// 		if err != nil {
-// 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid google access_token"})
-// 		return
-// 		}
-// 		sub = strings.TrimSpace(ui.Sub)
-
-// 	default:
-// 		c.JSON(http.StatusBadRequest, gin.H{"error": "provide id_token or access_token"})
-// 		return
-// 	}
-
-// 	if sub == "" {
-// 		c.JSON(http.StatusUnauthorized, gin.H{"error": "google token missing subject"})
-// 		return
-// 	}
-
-// 	ctx := c.Request.Context()
-
-// 	ident, err := h.store.GetIdentity(ctx, db.GetIdentityParams{
-// 		Provider:       "google",
-// 		ProviderUserID: sub,
-// 	})
-
-// 	var userID int64
-// 	if err == nil {
-// 		userID = ident.UserID
-// 	} else {
-// 		u, err2 := h.store.CreateUser(ctx)
-// 		if err2 != nil {
-// 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create user"})
-// 			return
-// 		}
-
-// 		_, err2 = h.store.CreateIdentity(ctx, db.CreateIdentityParams{
-// 			UserID:         u.ID,
-// 			Provider:       "google",
-// 			ProviderUserID: sub,
-// 		})
-// 		if err2 != nil {
-// 			// Handle race: if identity was created concurrently, fetch it again.
-// 			ident2, err3 := h.store.GetIdentity(ctx, db.GetIdentityParams{
-// 				Provider:       "google",
-// 				ProviderUserID: sub,
-// 			})
-// 			if err3 != nil {
-// 				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create identity"})
-// 				return
-// 			}
-// 			userID = ident2.UserID
-// 		} else {
-// 			userID = u.ID
-// 		}
-// 	}
-
-// 	token, err := h.mintJWT(userID)
-// 	if err != nil {
-// 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to mint token"})
-// 		return
-// 	}
-
-// 	c.JSON(http.StatusOK, loginResp{AccessToken: token, UserID: userID})
-// }
-
-// type googleUserInfo struct {
-//   Sub   string `json:"sub"`
-//   Email string `json:"email,omitempty"`
-//   Name  string `json:"name,omitempty"`
-// }
-
-// func fetchGoogleUserInfo(ctx context.Context, accessToken string) (*googleUserInfo, error) {
-//   req, _ := http.NewRequestWithContext(ctx, "GET",
-//     "https://openidconnect.googleapis.com/v1/userinfo", nil,
-//   )
-//   req.Header.Set("Authorization", "Bearer "+accessToken)
-
-//   resp, err := http.DefaultClient.Do(req)
-//   if err != nil {
-//     return nil, err
-//   }
-//   defer resp.Body.Close()
-
-//   if resp.StatusCode != 200 {
-//     return nil, fmt.Errorf("userinfo status %d", resp.StatusCode)
-//   }
-
-//   var out googleUserInfo
-//   if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-//     return nil, err
-//   }
-//   return &out, nil
-// }
+// Protected: attach a Google identity to the current (e.g. wallet) account so
+// either sign-in method reaches the same user.
+func (h *IdentitiesHandler) LinkGoogle(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	var req googleLoginReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !h.stepUp.Require(c, userID, stepUpGoogleLink, nil) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	gi, err := h.googleIdentity(ctx, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ident, err := h.attachIdentity(ctx, userID, "google", gi.Subject)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.rememberGoogleEmail(ctx, gi)
+	ident.Email = verifiedEmail(gi)
+
+	c.JSON(http.StatusOK, ident)
+}
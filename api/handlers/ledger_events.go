@@ -51,9 +51,9 @@ func (h *LedgerEventsHandler) ListMyTips(c *gin.Context) {
 
 	ctx := c.Request.Context()
 	events, err := h.store.ListTipsForUser(ctx, db.ListTipsForUserParams{
-		UserID:  sql.NullInt64{Int64: userID, Valid: true},
-		Limit:   limit,
-		Offset:  offset,
+		UserID: sql.NullInt64{Int64: userID, Valid: true},
+		Limit:  limit,
+		Offset: offset,
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list tips"})
@@ -61,4 +61,18 @@ func (h *LedgerEventsHandler) ListMyTips(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, events)
-}
\ No newline at end of file
+}
+
+// ListMyOwnershipEpochs shows the periods this user owned each channel; tips
+// and earnings are attributed from these.
+func (h *LedgerEventsHandler) ListMyOwnershipEpochs(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+
+	epochs, err := h.store.ListOwnershipEpochsForUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list ownership history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, epochs)
+}
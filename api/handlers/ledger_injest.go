@@ -11,6 +11,7 @@ import (
 
 	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
 	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/YoshiTheExplorer/TipMNEE/platform"
 	util "github.com/YoshiTheExplorer/TipMNEE/util"
 
 	"github.com/ethereum/go-ethereum"
@@ -31,11 +32,19 @@ const tipEscrowABIJSON = `[
     {"indexed":true,"internalType":"bytes32","name":"channelIdHash","type":"bytes32"},
     {"indexed":true,"internalType":"address","name":"payoutAddress","type":"address"},
     {"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"}
-  ],"name":"Withdrawn","type":"event"}
+  ],"name":"Withdrawn","type":"event"},
+  {"inputs":[
+    {"internalType":"bytes32","name":"channelIdHash","type":"bytes32"},
+    {"internalType":"address","name":"payoutAddress","type":"address"},
+    {"internalType":"uint256","name":"expiry","type":"uint256"},
+    {"internalType":"bytes32","name":"nonce","type":"bytes32"},
+    {"internalType":"bytes","name":"signature","type":"bytes"}
+  ],"name":"claim","outputs":[],"stateMutability":"nonpayable","type":"function"}
 ]`
 
 type LedgerIngestHandler struct {
-	store       *db.Queries
+	store       *db.Store
+	platforms   *platform.Registry
 	client      *ethclient.Client
 	chainID     int64
 	escrow      common.Address
@@ -49,7 +58,7 @@ func isValidHexHash(hash string) bool {
 	return len(hash) == 66 && strings.HasPrefix(hash, "0x")
 }
 
-func NewLedgerIngestHandler(store *db.Queries) (*LedgerIngestHandler, error) {
+func NewLedgerIngestHandler(store *db.Store, platforms *platform.Registry) (*LedgerIngestHandler, error) {
 	chainIDStr := strings.TrimSpace(os.Getenv("CHAIN_ID"))
 	if chainIDStr == "" {
 		return nil, errEnv("CHAIN_ID")
@@ -65,10 +74,7 @@ func NewLedgerIngestHandler(store *db.Queries) (*LedgerIngestHandler, error) {
 	}
 	escrow := common.HexToAddress(escrowStr)
 
-	rpcURL := strings.TrimSpace(os.Getenv("SEPOLIA_RPC_URL"))
-	if rpcURL == "" {
-		rpcURL = strings.TrimSpace(os.Getenv("RPC_URL"))
-	}
+	rpcURL := rpcURLEnv()
 	if rpcURL == "" {
 		return nil, errEnv("SEPOLIA_RPC_URL (or RPC_URL)")
 	}
@@ -85,6 +91,7 @@ func NewLedgerIngestHandler(store *db.Queries) (*LedgerIngestHandler, error) {
 
 	return &LedgerIngestHandler{
 		store:       store,
+		platforms:   platforms,
 		client:      client,
 		chainID:     chainID,
 		escrow:      escrow,
@@ -95,9 +102,28 @@ func NewLedgerIngestHandler(store *db.Queries) (*LedgerIngestHandler, error) {
 }
 
 type ingestReq struct {
-	TxHash   string `json:"tx_hash" binding:"required"`
+	TxHash    string `json:"tx_hash" binding:"required"`
+	Platform  string `json:"platform"` // defaults to "youtube"
 	ChannelID string `json:"channel_id" binding:"required"`
-	ChainID  *int64  `json:"chain_id,omitempty"`
+	ChainID   *int64 `json:"chain_id,omitempty"`
+}
+
+// channel validates the request's platform and channel id.
+func (h *LedgerIngestHandler) channel(c *gin.Context, req ingestReq) (string, string, bool) {
+	name := req.Platform
+	if name == "" {
+		name = "youtube"
+	}
+	p, ok := h.platforms.Get(name)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported platform"})
+		return "", "", false
+	}
+	channelID, ok := normalizeID(c, p, req.ChannelID)
+	if !ok {
+		return "", "", false
+	}
+	return p.Name(), channelID, true
 }
 
 // PUBLIC: anyone can tip (no JWT)
@@ -113,12 +139,11 @@ func (h *LedgerIngestHandler) RecordDeposit(c *gin.Context) {
 		return
 	}
 
-	channelID := strings.TrimSpace(req.ChannelID)
-	if channelID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "channel_id required"})
+	platformName, channelID, ok := h.channel(c, req)
+	if !ok {
 		return
 	}
-	expectedHash := util.ChannelHash(channelID)
+	expectedHash := util.PlatformChannelHash(platformName, channelID)
 
 	txHashStr := strings.TrimSpace(req.TxHash)
 	if !isValidHexHash(txHashStr) {
@@ -167,14 +192,7 @@ func (h *LedgerIngestHandler) RecordDeposit(c *gin.Context) {
 
 	inserted := 0
 	duplicates := 0
-
-	// Determine user_id if channel is already verified/linked (optional)
-	var userID sql.NullInt64
-	if sl, err := h.store.GetSocialLinkByPlatformUser(ctx, db.GetSocialLinkByPlatformUserParams{
-		Platform: "youtube", PlatformUserID: channelID,
-	}); err == nil && sl.VerifiedAt.Valid {
-		userID = sql.NullInt64{Int64: sl.UserID, Valid: true}
-	}
+	allocations := []db.LedgerAllocation{}
 
 	for _, lg := range receipt.Logs {
 		if lg.Address != h.escrow || len(lg.Topics) == 0 {
@@ -203,10 +221,10 @@ func (h *LedgerIngestHandler) RecordDeposit(c *gin.Context) {
 			msg = sql.NullString{String: decoded.Message, Valid: true}
 		}
 
-		_, err := h.store.InsertLedgerEvent(ctx, db.InsertLedgerEventParams{
-			Platform:       "youtube",
+		// Tips made while a split was active are allocated to its recipients.
+		_, allocated, err := h.store.RecordTipTx(ctx, db.InsertLedgerEventParams{
+			Platform:       platformName,
 			PlatformUserID: channelID,
-			UserID:         userID,
 			EventType:      "TIP_ESCROW",
 			AmountRaw:      decoded.Amount.String(),
 			Message:        msg,
@@ -227,6 +245,7 @@ func (h *LedgerIngestHandler) RecordDeposit(c *gin.Context) {
 			return
 		}
 		inserted++
+		allocations = append(allocations, allocated...)
 	}
 
 	if inserted == 0 && duplicates == 0 {
@@ -234,10 +253,20 @@ func (h *LedgerIngestHandler) RecordDeposit(c *gin.Context) {
 		return
 	}
 
+	// user_id comes from the ownership epoch at block time, not the current link.
+	if _, err := h.store.AttributeLedgerEventsForChannel(ctx, db.AttributeLedgerEventsForChannelParams{
+		Platform:       platformName,
+		PlatformUserID: channelID,
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to attribute ledger events"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"ok":         true,
-		"inserted":   inserted,
-		"duplicates": duplicates,
+		"ok":          true,
+		"inserted":    inserted,
+		"duplicates":  duplicates,
+		"allocations": allocations,
 	})
 }
 
@@ -260,12 +289,11 @@ func (h *LedgerIngestHandler) RecordWithdrawal(c *gin.Context) {
 		return
 	}
 
-	channelID := strings.TrimSpace(req.ChannelID)
-	if channelID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "channel_id required"})
+	platformName, channelID, ok := h.channel(c, req)
+	if !ok {
 		return
 	}
-	expectedHash := util.ChannelHash(channelID)
+	expectedHash := util.PlatformChannelHash(platformName, channelID)
 
 	txHashStr := strings.TrimSpace(req.TxHash)
 	if !isValidHexHash(txHashStr) {
@@ -278,9 +306,10 @@ func (h *LedgerIngestHandler) RecordWithdrawal(c *gin.Context) {
 
 	// Must own + be verified for this channel
 	sl, err := h.store.GetSocialLinkByPlatformUser(ctx, db.GetSocialLinkByPlatformUserParams{
-		Platform: "youtube", PlatformUserID: channelID,
+		Platform: platformName, PlatformUserID: channelID,
 	})
-	if err != nil {
+	// Someone else's unverified link is not disclosed.
+	if err != nil || (sl.UserID != user && !sl.VerifiedAt.Valid) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "channel not linked"})
 		return
 	}
@@ -331,6 +360,7 @@ func (h *LedgerIngestHandler) RecordWithdrawal(c *gin.Context) {
 
 	inserted := 0
 	duplicates := 0
+	claims := []db.RecordClaimTxResult{}
 
 	for _, lg := range receipt.Logs {
 		if lg.Address != h.escrow || len(lg.Topics) == 0 {
@@ -339,8 +369,8 @@ func (h *LedgerIngestHandler) RecordWithdrawal(c *gin.Context) {
 		if lg.Topics[0] != h.withdrawnID {
 			continue
 		}
-		// topics[1] = channelIdHash
-		if len(lg.Topics) < 2 || lg.Topics[1] != expectedHash {
+		// topics[1] = channelIdHash, topics[2] = payoutAddress
+		if len(lg.Topics) < 3 || lg.Topics[1] != expectedHash {
 			continue
 		}
 
@@ -352,16 +382,21 @@ func (h *LedgerIngestHandler) RecordWithdrawal(c *gin.Context) {
 			return
 		}
 
-		_, err := h.store.InsertLedgerEvent(ctx, db.InsertLedgerEventParams{
-			Platform:       "youtube",
-			PlatformUserID: channelID,
-			UserID:         sql.NullInt64{Int64: user, Valid: true},
-			EventType:      "WITHDRAW",
-			AmountRaw:      decoded.Amount.String(),
-			Message:        sql.NullString{Valid: false},
-			TxHash:         lg.TxHash.Hex(),
-			LogIndex:       int32(lg.Index),
-			BlockTime:      blockTime,
+		// The executed claim records what the payout address owes split
+		// recipients out of this withdrawal.
+		claim, err := h.store.RecordClaimTx(ctx, db.RecordClaimTxParams{
+			UserID:        user,
+			PayoutAddress: normalizeAddress(common.BytesToAddress(lg.Topics[2].Bytes()).Hex()),
+			Withdrawal: db.InsertLedgerEventParams{
+				Platform:       platformName,
+				PlatformUserID: channelID,
+				EventType:      "WITHDRAW",
+				AmountRaw:      decoded.Amount.String(),
+				Message:        sql.NullString{Valid: false},
+				TxHash:         lg.TxHash.Hex(),
+				LogIndex:       int32(lg.Index),
+				BlockTime:      blockTime,
+			},
 		})
 		if err != nil {
 			if err == sql.ErrNoRows {
@@ -372,6 +407,7 @@ func (h *LedgerIngestHandler) RecordWithdrawal(c *gin.Context) {
 			return
 		}
 		inserted++
+		claims = append(claims, claim)
 	}
 
 	if inserted == 0 && duplicates == 0 {
@@ -379,9 +415,19 @@ func (h *LedgerIngestHandler) RecordWithdrawal(c *gin.Context) {
 		return
 	}
 
+	// Like tips, the withdrawal belongs to the owner at block time, not the caller.
+	if _, err := h.store.AttributeLedgerEventsForChannel(ctx, db.AttributeLedgerEventsForChannelParams{
+		Platform:       platformName,
+		PlatformUserID: channelID,
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to attribute ledger events"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"ok":         true,
 		"inserted":   inserted,
 		"duplicates": duplicates,
+		"claims":     claims,
 	})
 }
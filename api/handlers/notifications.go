@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	util "github.com/YoshiTheExplorer/TipMNEE/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationsHandler struct {
+	store *db.Queries
+}
+
+func NewNotificationsHandler(store *db.Queries) *NotificationsHandler {
+	return &NotificationsHandler{store: store}
+}
+
+// notifyUser drops a message in the user's inbox. Failures are logged rather
+// than returned: the action that triggered the notice has already happened.
+func notifyUser(ctx context.Context, store *db.Queries, userID int64, kind, body string) {
+	if _, err := store.CreateNotification(ctx, db.CreateNotificationParams{
+		UserID: userID,
+		Kind:   kind,
+		Body:   body,
+	}); err != nil {
+		log.Printf("notify user %d (%s): %v", userID, kind, err)
+	}
+}
+
+// mailerFromEnv returns nil when SMTP_ADDR is unset; security notices then
+// only reach the in-app inbox.
+func mailerFromEnv() (util.Mailer, error) {
+	addr := strings.TrimSpace(os.Getenv("SMTP_ADDR"))
+	if addr == "" {
+		return nil, nil
+	}
+	from := strings.TrimSpace(os.Getenv("SMTP_FROM"))
+	if from == "" {
+		return nil, errEnv("SMTP_FROM (required with SMTP_ADDR)")
+	}
+	return util.NewSMTPMailer(addr, from, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD")), nil
+}
+
+// alertUser is notifyUser for notices the owner must see even when they
+// cannot sign in: the message is also mailed to every verified email on the
+// account's identities.
+func alertUser(ctx context.Context, store *db.Queries, mailer util.Mailer, userID int64, kind, subject, body string) {
+	notifyUser(ctx, store, userID, kind, body)
+	if mailer == nil {
+		return
+	}
+
+	emails, err := store.ListIdentityEmailsByUser(ctx, userID)
+	if err != nil {
+		log.Printf("alert user %d (%s): list emails: %v", userID, kind, err)
+		return
+	}
+	for _, to := range emails {
+		if err := mailer.Send(ctx, to, subject, body); err != nil {
+			log.Printf("alert user %d (%s): mail: %v", userID, kind, err)
+		}
+	}
+}
+
+func (h *NotificationsHandler) ListMyNotifications(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	limit := int32(50)
+	offset := int32(0)
+
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 200 {
+			limit = int32(n)
+		}
+	}
+	if v := c.Query("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = int32(n)
+		}
+	}
+
+	items, err := h.store.ListNotificationsForUser(c.Request.Context(), db.ListNotificationsForUserParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *NotificationsHandler) MarkNotificationRead(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification id"})
+		return
+	}
+
+	if _, err := h.store.MarkNotificationRead(c.Request.Context(), db.MarkNotificationReadParams{
+		ID:     id,
+		UserID: userID,
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to mark notification read"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"read": true})
+}
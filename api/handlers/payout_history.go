@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+const payoutChangeBatchSize = 100
+
+// payoutTarget names what a payout applies to, e.g. "ethereum payout address"
+// or "ethereum payout address for youtube channel UC...".
+func payoutTarget(chain string, platform, platformUserID sql.NullString) string {
+	if platform.Valid {
+		return fmt.Sprintf("%s payout address for %s channel %s", chain, platform.String, platformUserID.String)
+	}
+	return chain + " payout address"
+}
+
+func changeTarget(ch db.PayoutHistory) string {
+	return payoutTarget(ch.Chain, ch.Platform, ch.PlatformUserID)
+}
+
+func notifyPayoutChangePending(ctx context.Context, store *db.Queries, ch db.PayoutHistory) {
+	notifyUser(ctx, store, ch.UserID, "payout_change_pending", fmt.Sprintf(
+		"Your %s is changing from %s to %s on %s. Until then tips keep going to the old address. "+
+			"If you did not request this, cancel it (change #%d) and secure your account.",
+		changeTarget(ch), ch.OldAddress.String, ch.NewAddress, ch.EffectiveAt.UTC().Format(time.RFC3339), ch.ID,
+	))
+}
+
+func notifyPayoutChanged(ctx context.Context, store *db.Queries, ch db.PayoutHistory) {
+	body := fmt.Sprintf("Your %s is now %s.", changeTarget(ch), ch.NewAddress)
+	if ch.OldAddress.Valid {
+		body = fmt.Sprintf("Your %s changed from %s to %s.", changeTarget(ch), ch.OldAddress.String, ch.NewAddress)
+	}
+	notifyUser(ctx, store, ch.UserID, "payout_changed", body)
+}
+
+// Protected: every payout address change, newest first.
+func (h *PayoutsHandler) ListMyPayoutHistory(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	history, err := h.store.ListPayoutHistoryByUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list payout history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// Protected: cancel a pending address change; the current address stays.
+func (h *PayoutsHandler) CancelPayoutChange(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payout change id"})
+		return
+	}
+
+	if !h.stepUp.Require(c, userID, stepUpPayoutChangeCancel, map[string]string{
+		"change_id": strconv.FormatInt(id, 10),
+	}) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ch, err := h.store.CancelPayoutChange(ctx, db.CancelPayoutChangeParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no pending payout change with this id"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel payout change"})
+		return
+	}
+
+	notifyUser(ctx, h.store.Queries, userID, "payout_change_cancelled", fmt.Sprintf(
+		"The change of your %s to %s was cancelled; tips keep going to %s.",
+		changeTarget(ch), ch.NewAddress, ch.OldAddress.String,
+	))
+
+	c.JSON(http.StatusOK, ch)
+}
+
+// PayoutChangeJob applies pending payout address changes and revenue splits
+// once their cooldown has passed, checking every PAYOUT_CHANGE_EVERY.
+type PayoutChangeJob struct {
+	payouts *PayoutsHandler
+	every   time.Duration
+}
+
+func NewPayoutChangeJob(payouts *PayoutsHandler) (*PayoutChangeJob, error) {
+	every, err := durationEnv("PAYOUT_CHANGE_EVERY", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	if every == 0 {
+		return nil, errEnv("PAYOUT_CHANGE_EVERY (must be > 0)")
+	}
+	return &PayoutChangeJob{payouts: payouts, every: every}, nil
+}
+
+// Run applies due changes now and then every PAYOUT_CHANGE_EVERY until ctx is done.
+func (j *PayoutChangeJob) Run(ctx context.Context) {
+	t := time.NewTicker(j.every)
+	defer t.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("payout changes: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (j *PayoutChangeJob) RunOnce(ctx context.Context) error {
+	store := j.payouts.store
+
+	due, err := store.ListDuePayoutChanges(ctx, db.ListDuePayoutChangesParams{
+		DueBefore:  time.Now(),
+		MaxChanges: payoutChangeBatchSize,
+	})
+	if err != nil {
+		return err
+	}
+	for _, ch := range due {
+		applied, err := store.ApplyPayoutChangeTx(ctx, ch.ID)
+		if err == sql.ErrNoRows {
+			continue // cancelled meanwhile
+		}
+		if err != nil {
+			log.Printf("apply payout change %d: %v", ch.ID, err)
+			continue
+		}
+		j.payouts.invalidatePayout(applied)
+		notifyPayoutChanged(ctx, store.Queries, applied)
+	}
+
+	splits, err := store.ListDueChannelSplits(ctx, db.ListDueChannelSplitsParams{
+		DueBefore: time.Now(),
+		MaxSplits: payoutChangeBatchSize,
+	})
+	if err != nil {
+		return err
+	}
+	for _, split := range splits {
+		active, err := store.ActivateChannelSplitTx(ctx, split)
+		if err == sql.ErrNoRows {
+			continue // cancelled meanwhile
+		}
+		if err != nil {
+			log.Printf("activate channel split %d: %v", split.ID, err)
+			continue
+		}
+		j.payouts.cache.Invalidate(active.Platform, active.PlatformUserID)
+		notifySplitChanged(ctx, store.Queries, active)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
+	util "github.com/YoshiTheExplorer/TipMNEE/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	proofPersonalSign = "personal_sign"
+	proofEIP1271      = "eip1271"
+)
+
+func payoutProofMessage(addr string, userID int64, chain, nonce string, expires time.Time) string {
+	return fmt.Sprintf(
+		"TipMNEE wants you to confirm this address receives payouts for account #%d.\n\nAddress: %s\nChain: %s\nNonce: %s\nExpires: %s",
+		userID,
+		addr,
+		chain,
+		nonce,
+		expires.UTC().Format(time.RFC3339),
+	)
+}
+
+type payoutMessageReq struct {
+	Chain   string `json:"chain" binding:"required"`
+	Address string `json:"address" binding:"required"` // 0x... or an ENS name
+}
+
+// Protected: challenge the payout address must sign (personal_sign, or
+// EIP-1271 for contract wallets) before POST /api/payouts accepts it.
+func (h *PayoutsHandler) GetPayoutMessage(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	var req payoutMessageReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	addr, ensName, err := h.payoutAddress(c.Request.Context(), req.Address)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	chain := strings.TrimSpace(req.Chain)
+
+	message, err := issueWalletNonce(c.Request.Context(), h.store.Queries, addr, 10*time.Minute, func(nonce string, expires time.Time) string {
+		return payoutProofMessage(addr, userID, chain, nonce, expires)
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// For a name, the address it resolves to signs.
+	resp := gin.H{"address": addr, "message": message}
+	if ensName != "" {
+		resp["ens_name"] = ensName
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// provePayoutAddress checks signature over the issued challenge and returns
+// how the address proved control.
+func (h *PayoutsHandler) provePayoutAddress(ctx context.Context, userID int64, chain, addr, signature string) (string, error) {
+	if _, err := util.DecodeSignature(signature); err != nil {
+		return "", newHTTPError(http.StatusBadRequest, "signature must be hex-encoded")
+	}
+	chain = strings.TrimSpace(chain)
+	ln, err := takeWalletNonce(ctx, h.store.Queries, addr, "/api/payouts/message", func(nonce string, expires time.Time) string {
+		return payoutProofMessage(addr, userID, chain, nonce, expires)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// EOA
+	if recovered, err := util.RecoverAddressFromPersonalSign(ln.Message, signature); err == nil && normalizeAddress(recovered) == addr {
+		return proofPersonalSign, nil
+	}
+
+	// Contract wallet
+	if h.chain == nil {
+		return "", newHTTPError(http.StatusUnauthorized, "signature does not match address")
+	}
+	wallet := common.HexToAddress(addr)
+	isContract, err := util.IsContract(ctx, h.chain, wallet)
+	if err != nil {
+		return "", newHTTPError(http.StatusBadGateway, "failed to check address code")
+	}
+	if !isContract {
+		return "", newHTTPError(http.StatusUnauthorized, "signature does not match address")
+	}
+	ok, err := util.VerifyEIP1271(ctx, h.chain, wallet, ln.Message, signature)
+	if errors.Is(err, util.ErrInvalidSignature) {
+		return "", newHTTPError(http.StatusUnauthorized, "signature does not match address")
+	}
+	if err != nil {
+		// Only RPC failures get here.
+		return "", newHTTPError(http.StatusBadGateway, "failed to check contract wallet signature")
+	}
+	if !ok {
+		return "", newHTTPError(http.StatusUnauthorized, "contract wallet rejected the signature")
+	}
+	return proofEIP1271, nil
+}
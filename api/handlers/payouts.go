@@ -1,27 +1,143 @@
 package handlers
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
-	"strings"
+	"time"
 
 	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
 	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/YoshiTheExplorer/TipMNEE/platform"
+	util "github.com/YoshiTheExplorer/TipMNEE/util"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/gin-gonic/gin"
 )
 
 type PayoutsHandler struct {
-	store *db.Queries
+	store     *db.Store
+	platforms *platform.Registry
+	youtube   *YouTubeMetadata
+	cache     *ResolveCache
+	attestor  *util.ResolveAttestor // nil: answers are unsigned
+	chain     util.ChainReader      // nil: contract-wallet (EIP-1271) proofs unavailable
+	ens       util.ENSResolver      // nil: ENS names unavailable
+	stepUp    *StepUpHandler
+
+	changeCooldown time.Duration // PAYOUT_CHANGE_COOLDOWN
+	videoRateLimit gin.HandlerFunc
+}
+
+// NewPayoutsHandler uses SEPOLIA_RPC_URL (or RPC_URL), when set, to check
+// EIP-1271 signatures from contract wallets. ENS names are looked up through
+// ens (see ENSFromEnv); nil turns them off.
+// VIDEO_RESOLVE_RATE_LIMIT requests per VIDEO_RESOLVE_RATE_WINDOW are allowed
+// per caller on the video resolve route, whose misses cost YouTube quota.
+func NewPayoutsHandler(store *db.Store, platforms *platform.Registry, youtube *YouTubeMetadata, cache *ResolveCache, attestor *util.ResolveAttestor, stepUp *StepUpHandler, ens util.ENSResolver) (*PayoutsHandler, error) {
+	cooldown, err := durationEnv("PAYOUT_CHANGE_COOLDOWN", 48*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	videoRateLimit, err := intEnv("VIDEO_RESOLVE_RATE_LIMIT", 120)
+	if err != nil {
+		return nil, err
+	}
+	videoRateWindow, err := durationEnv("VIDEO_RESOLVE_RATE_WINDOW", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	h := &PayoutsHandler{
+		store:          store,
+		platforms:      platforms,
+		youtube:        youtube,
+		cache:          cache,
+		attestor:       attestor,
+		stepUp:         stepUp,
+		ens:            ens,
+		changeCooldown: cooldown,
+		videoRateLimit: middleware.RateLimit(videoRateLimit, videoRateWindow),
+	}
+	if rpcURL := rpcURLEnv(); rpcURL != "" {
+		client, err := ethclient.Dial(rpcURL)
+		if err != nil {
+			return nil, err
+		}
+		h.chain = client
+	}
+	return h, nil
+}
+
+// writeAnswer fills body with the answer: "split" with its recipients,
+// "direct" with the address, or "unclaimed" (tip into escrow). A signed
+// attestation is added when configured.
+func (h *PayoutsHandler) writeAnswer(body gin.H, channelHash common.Hash, ans resolveAnswer) error {
+	body["status"] = "unclaimed"
+	switch {
+	case len(ans.split) > 0:
+		body["status"] = "split"
+		body["recipients"] = ans.split
+	case ans.address != "":
+		body["status"] = "direct"
+		body["address"] = ans.address
+		// Display only: the name is not part of the attestation.
+		if ans.name != "" {
+			body["name"] = ans.name
+		}
+	}
+	if h.attestor == nil {
+		return nil
+	}
+
+	if len(ans.split) > 0 {
+		shares := make([]util.SplitShare, len(ans.split))
+		for i, r := range ans.split {
+			shares[i] = util.SplitShare{Account: common.HexToAddress(r.Address), Bps: int64(r.Bps)}
+		}
+		att, err := h.attestor.AttestSplit(channelHash, shares)
+		if err != nil {
+			return err
+		}
+		body["attestation"] = att
+		return nil
+	}
+
+	payout := common.Address{}
+	if ans.address != "" {
+		payout = common.HexToAddress(ans.address)
+	}
+	att, err := h.attestor.Attest(channelHash, payout)
+	if err != nil {
+		return err
+	}
+	body["attestation"] = att
+	return nil
+}
+
+// newResolveAnswer decodes a resolve row; a split that can't be decoded is
+// ignored so tips still reach the direct address.
+func newResolveAnswer(address, name string, splitJSON []byte) resolveAnswer {
+	split, err := db.DecodeSplitRecipients(splitJSON)
+	if err != nil {
+		split = nil
+	}
+	return resolveAnswer{address: address, name: name, split: split}
 }
 
-func NewPayoutsHandler(store *db.Queries) *PayoutsHandler {
-	return &PayoutsHandler{store: store}
+// channelParam reads :id; YouTube also accepts an @handle or channel URL.
+func (h *PayoutsHandler) channelParam(c *gin.Context, p platform.Provider) (string, bool) {
+	if p.Name() == "youtube" {
+		return youtubeChannelParam(c, h.youtube, c.Param("id"))
+	}
+	return normalizeID(c, p, c.Param("id"))
 }
 
 type upsertPayoutReq struct {
-	Chain   string `json:"chain" binding:"required"`   // "ethereum"
-	Address string `json:"address" binding:"required"` // 0x...
+	Chain     string `json:"chain" binding:"required"`     // "ethereum"
+	Address   string `json:"address" binding:"required"`   // 0x... or an ENS name
+	Signature string `json:"signature" binding:"required"` // over the message from /api/payouts/message
 }
 
 func (h *PayoutsHandler) UpsertPayout(c *gin.Context) {
@@ -33,43 +149,235 @@ func (h *PayoutsHandler) UpsertPayout(c *gin.Context) {
 		return
 	}
 
-	cleanAddr := strings.ToLower(strings.TrimSpace(req.Address))
-	if !common.IsHexAddress(cleanAddr) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ethereum address format"})
+	if !h.stepUp.Require(c, userID, stepUpPayoutChange, map[string]string{
+		"chain":   req.Chain,
+		"address": req.Address,
+	}) {
+		return
+	}
+
+	cleanAddr, ensName, err := h.payoutAddress(c.Request.Context(), req.Address)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// The address must sign our challenge, so a typo can't receive the escrow.
+	method, err := h.provePayoutAddress(c.Request.Context(), userID, req.Chain, cleanAddr, req.Signature)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	p, err := h.store.UpsertPayout(c.Request.Context(), db.UpsertPayoutParams{
-		UserID:  userID,
-		Chain:   req.Chain,
-		Address: cleanAddr,
+	h.changePayout(c, db.ChangePayoutTxParams{
+		UserID:      userID,
+		Chain:       req.Chain,
+		Address:     cleanAddr,
+		ProofMethod: method,
+		ENSName:     ensName,
 	})
+}
+
+// changePayout records a proven address for the user default or a channel
+// override. Replacing an address waits out PAYOUT_CHANGE_COOLDOWN, so a
+// hijacked session can't redirect tips before the creator sees the notice.
+func (h *PayoutsHandler) changePayout(c *gin.Context, arg db.ChangePayoutTxParams) {
+	ctx := c.Request.Context()
+
+	arg.Cooldown = h.changeCooldown
+	res, err := h.store.ChangePayoutTx(ctx, arg)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to set payout"})
 		return
 	}
 
-	c.JSON(http.StatusOK, p)
+	h.refreshReverseName(ctx, arg.Address)
+
+	status := http.StatusOK
+	if ch := res.Change; ch != nil {
+		if ch.Status == "pending" {
+			status = http.StatusAccepted
+			notifyPayoutChangePending(ctx, h.store.Queries, *ch)
+		} else {
+			h.invalidatePayout(*ch)
+			notifyPayoutChanged(ctx, h.store.Queries, *ch)
+		}
+	}
+
+	c.JSON(status, res)
+}
+
+// invalidatePayout drops cached resolve answers a payout change affects.
+func (h *PayoutsHandler) invalidatePayout(ch db.PayoutHistory) {
+	if ch.Platform.Valid {
+		h.cache.Invalidate(ch.Platform.String, ch.PlatformUserID.String)
+		return
+	}
+	// The default address is shared by all of this user's channels.
+	h.cache.Purge()
 }
 
 // Public: resolve channel payout for extension.
-func (h *PayoutsHandler) ResolveYouTubeChannelPayout(c *gin.Context) {
-	channelID := strings.TrimSpace(c.Param("channelId"))
-	if channelID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "channelId param required"})
+// channel_id_hash is the escrow key to tip into when the creator is unclaimed.
+func (h *PayoutsHandler) ResolveChannelPayout(c *gin.Context) {
+	p, ok := providerParam(c, h.platforms)
+	if !ok {
 		return
 	}
 
-	addr, err := h.store.ResolvePayoutByChannelID(c.Request.Context(), db.ResolvePayoutByChannelIDParams{
-		Platform:       "youtube",
-		PlatformUserID: channelID,
-		Chain:          "ethereum",
-	})
+	channelID, ok := h.channelParam(c, p)
+	if !ok {
+		return
+	}
+	h.resolve(c, p.Name(), channelID, nil)
+}
+
+// VideoRateLimit is the per-caller limit for the video resolve route.
+func (h *PayoutsHandler) VideoRateLimit() gin.HandlerFunc {
+	return h.videoRateLimit
+}
+
+// Public: the extension only knows the video id on watch pages.
+func (h *PayoutsHandler) ResolveYouTubeVideoPayout(c *gin.Context) {
+	if h.youtube == nil || h.youtube.yt == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": platform.ErrProfileUnavailable.Error()})
+		return
+	}
+	videoID, err := h.youtube.yt.NormalizeVideoID(c.Param("videoId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid youtube video id"})
+		return
+	}
+
+	channelID, err := h.youtube.VideoChannelID(c.Request.Context(), videoID)
 	if err != nil {
-		// Not claimed or no payout set -> tell client to use escrow
-		c.JSON(http.StatusOK, gin.H{"status": "unclaimed"})
+		if errors.Is(err, platform.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "video not found"})
+			return
+		}
+		respondProfileError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "direct", "address": addr})
+	h.resolve(c, "youtube", channelID, gin.H{"video_id": videoID})
+}
+
+// maxBatchResolve caps one batch request; a YouTube search page shows well under this.
+const maxBatchResolve = 300
+
+type batchResolveReq struct {
+	Platform string   `json:"platform"` // defaults to youtube
+	IDs      []string `json:"ids" binding:"required"`
+}
+
+// Public: resolves many channels in one query (feed and search pages).
+// Results are keyed by the id as sent; invalid ids get status "invalid".
+func (h *PayoutsHandler) BatchResolve(c *gin.Context) {
+	var req batchResolveReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Platform == "" {
+		req.Platform = "youtube"
+	}
+	p, ok := h.platforms.Get(req.Platform)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported platform"})
+		return
+	}
+	if len(req.IDs) > maxBatchResolve {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d ids per request", maxBatchResolve)})
+		return
+	}
+
+	results := make(map[string]gin.H, len(req.IDs))
+	byChannel := make(map[string][]string, len(req.IDs)) // channel id -> ids as sent
+	channelIDs := make([]string, 0, len(req.IDs))
+	for _, raw := range req.IDs {
+		id, err := platform.ResolveID(c.Request.Context(), p, raw)
+		if err != nil {
+			status := "invalid"
+			if !errors.Is(err, platform.ErrInvalidID) {
+				status = "unresolved" // handle unknown or lookup failed
+			}
+			results[raw] = gin.H{"status": status}
+			continue
+		}
+		if _, seen := byChannel[id]; !seen {
+			channelIDs = append(channelIDs, id)
+		}
+		byChannel[id] = append(byChannel[id], raw)
+		results[raw] = gin.H{"channel_id": id}
+	}
+
+	answers := make(map[string]resolveAnswer, len(channelIDs))
+
+	if len(channelIDs) > 0 {
+		rows, err := h.store.ResolvePayoutsByChannelIDs(c.Request.Context(), db.ResolvePayoutsByChannelIDsParams{
+			Platform:        p.Name(),
+			PlatformUserIds: channelIDs,
+			Chain:           "ethereum",
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve channels"})
+			return
+		}
+		for _, row := range rows {
+			answers[row.PlatformUserID] = newResolveAnswer(row.Address, row.Name, row.SplitRecipients)
+		}
+	}
+
+	for id, raws := range byChannel {
+		channelHash := util.PlatformChannelHash(p.Name(), id)
+		for _, raw := range raws {
+			results[raw]["channel_id_hash"] = channelHash.Hex()
+			if err := h.writeAnswer(results[raw], channelHash, answers[id]); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to sign resolve attestation"})
+				return
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"platform": p.Name(), "results": results})
+}
+
+// resolve answers with the channel's revenue split, its direct payout
+// address, or "unclaimed" when tips should go to escrow. extra is merged into
+// the response.
+func (h *PayoutsHandler) resolve(c *gin.Context, platformName, channelID string, extra gin.H) {
+	ans, ok := h.cache.get(platformName, channelID)
+	if !ok {
+		gen := h.cache.generation()
+		row, err := h.store.ResolvePayoutByChannelID(c.Request.Context(), db.ResolvePayoutByChannelIDParams{
+			Platform:       platformName,
+			PlatformUserID: channelID,
+			Chain:          "ethereum",
+		})
+		// Not claimed or no payout set -> tell client to use escrow. Only
+		// these definite answers are cached and signed; on a DB error the
+		// client gets no answer rather than a signed "unclaimed".
+		switch {
+		case err == nil:
+			ans = newResolveAnswer(row.Address, row.Name, row.SplitRecipients)
+		case err != sql.ErrNoRows:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve channel"})
+			return
+		}
+		h.cache.put(platformName, channelID, gen, ans)
+	}
+
+	channelHash := util.PlatformChannelHash(platformName, channelID)
+	body := gin.H{
+		"channel_id":      channelID,
+		"channel_id_hash": channelHash.Hex(),
+	}
+	for k, v := range extra {
+		body[k] = v
+	}
+	if err := h.writeAnswer(body, channelHash, ans); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to sign resolve attestation"})
+		return
+	}
+	h.cache.respondCacheable(c, body)
 }
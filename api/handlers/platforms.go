@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/YoshiTheExplorer/TipMNEE/platform"
+	"github.com/gin-gonic/gin"
+)
+
+// providerParam resolves the :platform route param to a registered provider.
+func providerParam(c *gin.Context, platforms *platform.Registry) (platform.Provider, bool) {
+	p, ok := platforms.Get(c.Param("platform"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unsupported platform", "supported": platforms.Names()})
+		return nil, false
+	}
+	return p, true
+}
+
+// normalizeID validates a platform user id and answers 400 when it is
+// malformed. On platforms keyed by a numeric id, an @handle is looked up and
+// replaced by the id of the account that holds it now.
+func normalizeID(c *gin.Context, p platform.Provider, raw string) (string, bool) {
+	id, err := platform.ResolveID(c.Request.Context(), p, raw)
+	switch {
+	case err == nil:
+		return id, true
+	case errors.Is(err, platform.ErrInvalidID):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + p.Name() + " id"})
+	case errors.Is(err, platform.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "no " + p.Name() + " account with this handle"})
+	case errors.Is(err, platform.ErrProfileUnavailable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": p.Name() + " handle lookup is not configured; send the numeric id"})
+	default:
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	}
+	return "", false
+}
+
+// platformUserID picks the generic "id" field, falling back to the older
+// "channel_id" the YouTube-only endpoints used.
+func platformUserID(id, channelID string) string {
+	if id != "" {
+		return id
+	}
+	return channelID
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	util "github.com/YoshiTheExplorer/TipMNEE/util"
+)
+
+// NewResolveAttestor returns nil when RESOLVER_PRIVATE_KEY is unset, in which
+// case resolve answers carry no attestation. The key should not be the claim
+// VERIFIER_PRIVATE_KEY: it only vouches for mappings, not for claims.
+// RESOLVE_ATTESTATION_TTL is how long a signed answer stays valid.
+func NewResolveAttestor() (*util.ResolveAttestor, error) {
+	keyHex := strings.TrimSpace(os.Getenv("RESOLVER_PRIVATE_KEY"))
+	if keyHex == "" {
+		return nil, nil
+	}
+	chainID, err := strconv.ParseInt(strings.TrimSpace(os.Getenv("CHAIN_ID")), 10, 64)
+	if err != nil {
+		return nil, errEnv("CHAIN_ID")
+	}
+	ttl, err := durationEnv("RESOLVE_ATTESTATION_TTL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	if ttl == 0 {
+		return nil, errEnv("RESOLVE_ATTESTATION_TTL (must be > 0)")
+	}
+	a, err := util.NewResolveAttestor(keyHex, chainID, ttl)
+	if err != nil {
+		return nil, errEnv("RESOLVER_PRIVATE_KEY (must be a hex secp256k1 key)")
+	}
+	return a, nil
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+ResolveCache keeps channel -> payout answers in memory for RESOLVE_CACHE_TTL
+(0 disables it). Handlers that change the mapping (payout upsert, split,
+verify, transfer, unlink, merge, recovery, lapsed re-verification) invalidate
+it at once. It is per process: other instances can serve the old answer until
+it expires. Browsers get Cache-Control: no-cache and revalidate with the ETag.
+*/
+type ResolveCache struct {
+	ttl time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]resolveEntry
+	// gen counts invalidations. An answer read before one is not cached
+	// after it, so a slow lookup can't put back what was just invalidated.
+	gen uint64
+}
+
+type resolveEntry struct {
+	answer  resolveAnswer
+	expires time.Time
+}
+
+// resolveAnswer is where a channel's tips go: a split, an address, or escrow
+// when both are empty. name is the address's verified ENS primary name.
+type resolveAnswer struct {
+	address string
+	name    string
+	split   []db.SplitRecipient
+}
+
+func NewResolveCache() (*ResolveCache, error) {
+	ttl, err := durationEnv("RESOLVE_CACHE_TTL", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	return &ResolveCache{ttl: ttl, entries: map[string]resolveEntry{}}, nil
+}
+
+func resolveKey(platformName, channelID string) string {
+	return platformName + "/" + channelID
+}
+
+func (rc *ResolveCache) get(platformName, channelID string) (resolveAnswer, bool) {
+	if rc == nil || rc.ttl == 0 {
+		return resolveAnswer{}, false
+	}
+	rc.mu.RLock()
+	e, ok := rc.entries[resolveKey(platformName, channelID)]
+	rc.mu.RUnlock()
+	if !ok || time.Now().After(e.expires) {
+		return resolveAnswer{}, false
+	}
+	return e.answer, true
+}
+
+// generation is taken before reading an answer from the database and passed
+// to put.
+func (rc *ResolveCache) generation() uint64 {
+	if rc == nil {
+		return 0
+	}
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	return rc.gen
+}
+
+// put caches answer unless the cache was invalidated since gen was taken.
+func (rc *ResolveCache) put(platformName, channelID string, gen uint64, answer resolveAnswer) {
+	if rc == nil || rc.ttl == 0 {
+		return
+	}
+	now := time.Now()
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if gen != rc.gen {
+		return
+	}
+	// Drop expired entries now and then so the map can't grow without bound.
+	if len(rc.entries) >= 100_000 {
+		for k, e := range rc.entries {
+			if now.After(e.expires) {
+				delete(rc.entries, k)
+			}
+		}
+	}
+	rc.entries[resolveKey(platformName, channelID)] = resolveEntry{answer: answer, expires: now.Add(rc.ttl)}
+}
+
+// Invalidate drops one channel's answer.
+func (rc *ResolveCache) Invalidate(platformName, channelID string) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	delete(rc.entries, resolveKey(platformName, channelID))
+	rc.gen++
+	rc.mu.Unlock()
+}
+
+// Purge drops everything; used when a change touches all of a user's channels.
+func (rc *ResolveCache) Purge() {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	rc.entries = map[string]resolveEntry{}
+	rc.gen++
+	rc.mu.Unlock()
+}
+
+// respondCacheable writes body with an ETag, answering 304 when the client
+// already has it. no-cache makes clients revalidate every time, so a changed
+// payout is seen at once.
+func (rc *ResolveCache) respondCacheable(c *gin.Context, body gin.H) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encode response"})
+		return
+	}
+	sum := sha256.Sum256(raw)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+
+	if etagMatch(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", raw)
+}
+
+func etagMatch(header, etag string) bool {
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
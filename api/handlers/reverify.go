@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/YoshiTheExplorer/TipMNEE/platform"
+)
+
+const reverifyBatchSize = 100
+
+/*
+ReverificationJob re-checks verified channels in the background:
+  - Each verified link is re-checked once it is older than REVERIFY_INTERVAL,
+    using the stored OAuth refresh token or a description code.
+  - The first failed check starts a REVERIFY_GRACE period and tells the creator
+    how to re-verify; failing links are retried on every run.
+  - When the grace period runs out the link is marked stale and resolve falls
+    back to escrow until the creator verifies again.
+
+API errors are treated as "try again later" and never count as a failure;
+an OAuth check that errors falls back to the description code, and the
+attempt is recorded either way so erroring links don't hold up the batch.
+Links nothing can check (no stored OAuth token and no readable description)
+are skipped rather than failed.
+*/
+type ReverificationJob struct {
+	social   *SocialLinksHandler
+	interval time.Duration
+	grace    time.Duration
+	every    time.Duration
+}
+
+// NewReverificationJob returns nil when REVERIFY_INTERVAL is 0 (disabled).
+func NewReverificationJob(social *SocialLinksHandler) (*ReverificationJob, error) {
+	interval, err := durationEnv("REVERIFY_INTERVAL", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	if interval == 0 {
+		return nil, nil
+	}
+	grace, err := durationEnv("REVERIFY_GRACE", 7*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	every, err := durationEnv("REVERIFY_EVERY", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	if every == 0 {
+		return nil, errEnv("REVERIFY_EVERY (must be > 0)")
+	}
+	return &ReverificationJob{social: social, interval: interval, grace: grace, every: every}, nil
+}
+
+// Run re-checks due links now and then every REVERIFY_EVERY until ctx is done.
+func (j *ReverificationJob) Run(ctx context.Context) {
+	t := time.NewTicker(j.every)
+	defer t.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("reverify: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (j *ReverificationJob) RunOnce(ctx context.Context) error {
+	links, err := j.social.store.ListSocialLinksDueForRecheck(ctx, db.ListSocialLinksDueForRecheckParams{
+		CheckedBefore: time.Now().UTC().Add(-j.interval),
+		MaxLinks:      reverifyBatchSize,
+	})
+	if err != nil {
+		return err
+	}
+	for _, sl := range links {
+		j.recheck(ctx, sl)
+	}
+	return nil
+}
+
+// ownership is the outcome of one re-check.
+type ownership int
+
+const (
+	// ownershipUnknown: no method could check the link (no stored OAuth
+	// token, no readable description). The link is skipped, not failed.
+	ownershipUnknown ownership = iota
+	ownershipConfirmed
+	// ownershipUnconfirmed: a method that could have confirmed ownership ran
+	// and did not, or a description code has to be issued first.
+	ownershipUnconfirmed
+)
+
+func (j *ReverificationJob) recheck(ctx context.Context, sl db.SocialLink) {
+	store := j.social.store
+
+	p, ok := j.social.platforms.Get(sl.Platform)
+	if !ok {
+		return
+	}
+
+	result, err := j.checkOwnership(ctx, p, sl)
+	if err != nil {
+		log.Printf("reverify %s/%s: %v", sl.Platform, sl.PlatformUserID, err)
+	}
+
+	switch {
+	case err != nil, result == ownershipUnknown:
+		if err := store.MarkSocialLinkCheckSkipped(ctx, sl.ID); err != nil {
+			log.Printf("reverify %s/%s: %v", sl.Platform, sl.PlatformUserID, err)
+		}
+	case result == ownershipConfirmed:
+		if err := store.MarkSocialLinkRechecked(ctx, sl.ID); err != nil {
+			log.Printf("reverify %s/%s: %v", sl.Platform, sl.PlatformUserID, err)
+			return
+		}
+		if sl.CheckFailedAt.Valid {
+			notifyUser(ctx, store.Queries, sl.UserID, "link_reverified", fmt.Sprintf(
+				"Ownership of %s channel %s was confirmed again; no action needed.", sl.Platform, sl.PlatformUserID,
+			))
+		}
+	default:
+		j.failed(ctx, p, sl)
+	}
+}
+
+// checkOwnership returns an error when no method could run to completion (API
+// down, DB error) and ownershipUnknown when no method was available for the
+// link.
+func (j *ReverificationJob) checkOwnership(ctx context.Context, p platform.Provider, sl db.SocialLink) (ownership, error) {
+	store := j.social.store
+
+	// 1. Stored OAuth refresh token. A revoked token or a failed check falls
+	// through to the description code so the creator still has a way to
+	// re-verify.
+	result, oauthErr := j.checkOAuth(ctx, p, sl)
+	if result == ownershipConfirmed {
+		return result, nil
+	}
+
+	// 2. Description code (issued by failed() when no code is outstanding).
+	dr, ok := p.(platform.DescriptionReader)
+	if !ok {
+		return result, oauthErr
+	}
+	desc, err := dr.Description(ctx, sl.PlatformUserID)
+	if errors.Is(err, platform.ErrProfileUnavailable) {
+		return result, oauthErr
+	}
+	if err != nil {
+		return ownershipUnknown, err
+	}
+	vc, err := store.GetVerificationCode(ctx, db.GetVerificationCodeParams{
+		UserID:         sl.UserID,
+		Platform:       sl.Platform,
+		PlatformUserID: sl.PlatformUserID,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return ownershipUnknown, err
+	}
+	if err != nil || vc.UsedAt.Valid || time.Now().After(vc.ExpiresAt) {
+		// No code to look for: an OAuth check that could not run is not a
+		// failure.
+		if oauthErr != nil {
+			return ownershipUnknown, oauthErr
+		}
+		return ownershipUnconfirmed, nil
+	}
+	if !strings.Contains(desc, vc.Code) {
+		return ownershipUnconfirmed, nil
+	}
+	_ = store.MarkVerificationCodeUsed(ctx, vc.ID)
+	return ownershipConfirmed, nil
+}
+
+// checkOAuth re-checks sl with its stored refresh token. It returns
+// ownershipUnknown when there is no usable token or the check failed to run.
+func (j *ReverificationJob) checkOAuth(ctx context.Context, p platform.Provider, sl db.SocialLink) (ownership, error) {
+	oauth := j.social.oauth
+	if oauth == nil {
+		return ownershipUnknown, nil
+	}
+	cred, err := j.social.store.GetOAuthCredentialForChannel(ctx, db.GetOAuthCredentialForChannelParams{
+		Platform:       sl.Platform,
+		PlatformUserID: sl.PlatformUserID,
+	})
+	if err == sql.ErrNoRows || (err == nil && cred.UserID != sl.UserID) {
+		return ownershipUnknown, nil
+	}
+	if err != nil {
+		return ownershipUnknown, err
+	}
+	refreshToken, err := oauth.secrets.Open(cred.RefreshTokenEnc, oauthCredentialKey(cred.Platform, cred.PlatformUserID))
+	if err != nil {
+		return ownershipUnknown, fmt.Errorf("decrypt refresh token: %w", err)
+	}
+	tok, err := oauth.client.Refresh(ctx, refreshToken)
+	if err != nil {
+		// Revoked or expired: only the description code is left.
+		return ownershipUnknown, nil
+	}
+	owns, err := p.VerifyOwnership(ctx, tok.AccessToken, sl.PlatformUserID)
+	if err != nil {
+		return ownershipUnknown, err
+	}
+	if owns {
+		return ownershipConfirmed, nil
+	}
+	return ownershipUnconfirmed, nil
+}
+
+func (j *ReverificationJob) failed(ctx context.Context, p platform.Provider, sl db.SocialLink) {
+	store := j.social.store
+	now := time.Now().UTC()
+
+	if !sl.CheckFailedAt.Valid {
+		if err := store.MarkSocialLinkCheckFailed(ctx, sl.ID); err != nil {
+			log.Printf("reverify %s/%s: %v", sl.Platform, sl.PlatformUserID, err)
+			return
+		}
+
+		deadline := now.Add(j.grace).Truncate(time.Second)
+		msg := fmt.Sprintf(
+			"We could not confirm that you still own %s channel %s. Verify it again before %s, "+
+				"or new tips will be held in escrow instead of going to your wallet.",
+			sl.Platform, sl.PlatformUserID, deadline.Format(time.RFC3339),
+		)
+		if _, ok := p.(platform.DescriptionReader); ok {
+			if code, err := generateVerificationCode(); err == nil {
+				if _, err := store.UpsertVerificationCode(ctx, db.UpsertVerificationCodeParams{
+					UserID:         sl.UserID,
+					Platform:       sl.Platform,
+					PlatformUserID: sl.PlatformUserID,
+					Code:           code,
+					ExpiresAt:      deadline,
+				}); err == nil {
+					msg += fmt.Sprintf(" You can also add the code %s to the channel description.", code)
+				}
+			}
+		}
+		notifyUser(ctx, store.Queries, sl.UserID, "link_reverification_failed", msg)
+		return
+	}
+
+	if now.Before(sl.CheckFailedAt.Time.Add(j.grace)) {
+		if err := store.MarkSocialLinkCheckFailed(ctx, sl.ID); err != nil {
+			log.Printf("reverify %s/%s: %v", sl.Platform, sl.PlatformUserID, err)
+		}
+		return
+	}
+
+	if err := store.MarkSocialLinkStale(ctx, sl.ID); err != nil {
+		log.Printf("reverify %s/%s: %v", sl.Platform, sl.PlatformUserID, err)
+		return
+	}
+	j.social.resolveCache.Invalidate(sl.Platform, sl.PlatformUserID)
+	notifyUser(ctx, store.Queries, sl.UserID, "link_verification_lapsed", fmt.Sprintf(
+		"Verification of %s channel %s lapsed. Tips are held in escrow until you verify the channel again.",
+		sl.Platform, sl.PlatformUserID,
+	))
+}
@@ -2,95 +2,188 @@ package handlers
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
-	"encoding/json"
+	"encoding/base32"
 	"errors"
 	"fmt"
-	"io"
+	"log"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
 	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/YoshiTheExplorer/TipMNEE/platform"
 	"github.com/gin-gonic/gin"
 )
 
 type SocialLinksHandler struct {
-	store *db.Queries
+	store     *db.Store
+	platforms *platform.Registry
+	codeTTL   time.Duration
+	oauth     *googleOAuth // nil when the oauth code flow isn't configured
+	rawTokens bool         // accept client-supplied access tokens on /verify
+	youtube   *YouTubeMetadata
+
+	resolveCache *ResolveCache
+	stepUp       *StepUpHandler
+	recovery     *AccountRecoveryHandler // oauth callbacks started by recovery
+
+	transferCooloff time.Duration
+
+	unverifiedTTL   time.Duration
+	maxPendingLinks int
+	linkRateLimit   gin.HandlerFunc
+}
+
+// NewSocialLinksHandler reads VERIFICATION_CODE_TTL, how long a description
+// code stays valid, CHANNEL_TRANSFER_COOLOFF, how long a previous owner has
+// to dispute a transfer, and the optional Google OAuth code-flow settings.
+// RAW_TOKEN_VERIFY (default false) re-enables POST /social/:platform/verify,
+// which trusts an access token the client obtained itself.
+//
+// Squatting limits: UNVERIFIED_LINK_TTL is how long an unverified link holds a
+// channel, MAX_PENDING_LINKS caps unverified links per user (0 = no cap) and
+// LINK_RATE_LIMIT requests per LINK_RATE_WINDOW are allowed per user on the
+// link/verify-start endpoints (0 = no limit).
+func NewSocialLinksHandler(store *db.Store, platforms *platform.Registry, youtube *YouTubeMetadata, resolveCache *ResolveCache, stepUp *StepUpHandler, recovery *AccountRecoveryHandler) (*SocialLinksHandler, error) {
+	codeTTL, err := durationEnv("VERIFICATION_CODE_TTL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	transferCooloff, err := durationEnv("CHANNEL_TRANSFER_COOLOFF", 72*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	oauth, err := googleOAuthFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	rawTokens, err := boolEnv("RAW_TOKEN_VERIFY", false)
+	if err != nil {
+		return nil, err
+	}
+	unverifiedTTL, err := durationEnv("UNVERIFIED_LINK_TTL", 72*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	if unverifiedTTL == 0 {
+		return nil, errEnv("UNVERIFIED_LINK_TTL (must be > 0)")
+	}
+	maxPendingLinks, err := intEnv("MAX_PENDING_LINKS", 5)
+	if err != nil {
+		return nil, err
+	}
+	linkRateLimit, err := intEnv("LINK_RATE_LIMIT", 20)
+	if err != nil {
+		return nil, err
+	}
+	linkRateWindow, err := durationEnv("LINK_RATE_WINDOW", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	return &SocialLinksHandler{
+		store:           store,
+		platforms:       platforms,
+		codeTTL:         codeTTL,
+		oauth:           oauth,
+		rawTokens:       rawTokens,
+		youtube:         youtube,
+		resolveCache:    resolveCache,
+		stepUp:          stepUp,
+		recovery:        recovery,
+		transferCooloff: transferCooloff,
+		unverifiedTTL:   unverifiedTTL,
+		maxPendingLinks: maxPendingLinks,
+		linkRateLimit:   middleware.RateLimit(linkRateLimit, linkRateWindow),
+	}, nil
 }
 
-func NewSocialLinksHandler(store *db.Queries) *SocialLinksHandler {
-	return &SocialLinksHandler{store: store}
+// LinkRateLimit is the shared per-user limit for routes that start a link or
+// verification.
+func (h *SocialLinksHandler) LinkRateLimit() gin.HandlerFunc {
+	return h.linkRateLimit
 }
 
-type linkYouTubeReq struct {
-	ChannelID string `json:"channel_id" binding:"required"`
+type linkSocialReq struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"` // YouTube clients still send channel_id
 }
 
-func (h *SocialLinksHandler) LinkYouTubeChannel(c *gin.Context) {
+func (h *SocialLinksHandler) LinkChannel(c *gin.Context) {
 	userID := middleware.MustUserID(c)
 	if userID == 0 {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
 		return
 	}
 
-	var req linkYouTubeReq
+	p, ok := providerParam(c, h.platforms)
+	if !ok {
+		return
+	}
+
+	var req linkSocialReq
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	ctx := c.Request.Context()
-	channelID := strings.TrimSpace(req.ChannelID)
-	if channelID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "channel_id required"})
+	channelID, ok := normalizeID(c, p, platformUserID(req.ID, req.ChannelID))
+	if !ok {
 		return
 	}
 
+	// An expired unverified link no longer holds the channel. The rest are
+	// swept by UnverifiedLinkSweepJob.
+	expiredBefore := time.Now().Add(-h.unverifiedTTL)
+	_, _ = h.store.DeleteExpiredUnverifiedSocialLink(ctx, db.DeleteExpiredUnverifiedSocialLinkParams{
+		Platform:       p.Name(),
+		PlatformUserID: channelID,
+		ExpiredBefore:  expiredBefore,
+	})
+
 	// 1. Check if ANY link exists for this channel
 	existing, err := h.store.GetSocialLinkByPlatformUser(ctx, db.GetSocialLinkByPlatformUserParams{
-		Platform:       "youtube",
+		Platform:       p.Name(),
 		PlatformUserID: channelID,
 	})
+	if err != nil && err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check database"})
+		return
+	}
 
+	if err == nil && existing.UserID == userID {
+		// User already owns it
+		c.JSON(http.StatusOK, gin.H{"linked": true, "verified": existing.VerifiedAt.Valid, "expires_at": h.linkExpiry(existing)})
+		return
+	}
 	if err == nil {
-		// ALREADY EXISTS
-		if existing.UserID == userID {
-			// User already owns it
-			c.JSON(http.StatusOK, gin.H{"linked": true, "verified": existing.VerifiedAt.Valid})
-			return
-		}
-
-		if existing.VerifiedAt.Valid {
-			// Someone else has verified it - protect their ownership
-			c.JSON(http.StatusConflict, gin.H{"error": "channel already verified by another user"})
-			return
-		}
-
-		// STOLEN/UNVERIFIED: Move the existing unverified record to the current user
-		_, err = h.store.TransferSocialLinkToUser(ctx, db.TransferSocialLinkToUserParams{
-			ID:         existing.ID,
-			UserID:     userID,
-			VerifiedAt: sql.NullTime{Valid: false},
-		})
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update existing link"})
-			return
-		}
-		c.JSON(http.StatusOK, gin.H{"linked": true, "verified": false, "status": "taken_over"})
+		// Someone else holds it (verified, lapsed, or an unexpired unverified
+		// link). Only a fresh proof of ownership can take it from them; a
+		// lapsed owner may still be the real one.
+		c.JSON(http.StatusConflict, gin.H{"error": "channel is already linked to another user; verify ownership to claim it"})
 		return
 	}
 
-	// 2. Doesn't exist: Create new
-	if err != sql.ErrNoRows {
+	pending, err := h.store.CountPendingSocialLinksForUser(ctx, db.CountPendingSocialLinksForUserParams{
+		UserID:        userID,
+		ExpiredBefore: expiredBefore,
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check database"})
 		return
 	}
+	if h.maxPendingLinks > 0 && pending >= int64(h.maxPendingLinks) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": fmt.Sprintf("too many unverified links (max %d); verify or wait for them to expire", h.maxPendingLinks)})
+		return
+	}
 
-	_, err = h.store.CreateSocialLink(ctx, db.CreateSocialLinkParams{
+	// 2. Doesn't exist: Create new
+	sl, err := h.store.CreateSocialLink(ctx, db.CreateSocialLinkParams{
 		UserID:         userID,
-		Platform:       "youtube",
+		Platform:       p.Name(),
 		PlatformUserID: channelID,
 		VerifiedAt:     sql.NullTime{Valid: false},
 	})
@@ -99,157 +192,448 @@ func (h *SocialLinksHandler) LinkYouTubeChannel(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"linked": true, "verified": false})
+	c.JSON(http.StatusOK, gin.H{"linked": true, "verified": false, "expires_at": h.linkExpiry(sl)})
+}
+
+// linkExpiry is when an unverified link stops holding its channel; nil once
+// verified.
+func (h *SocialLinksHandler) linkExpiry(sl db.SocialLink) *time.Time {
+	if sl.VerifiedAt.Valid {
+		return nil
+	}
+	t := sl.UpdatedAt.Add(h.unverifiedTTL)
+	return &t
 }
 
-type verifyYouTubeReq struct {
-	ChannelID   string `json:"channel_id" binding:"required"`
+type verifySocialReq struct {
+	ID          string `json:"id"`
+	ChannelID   string `json:"channel_id"` // YouTube clients still send channel_id
 	AccessToken string `json:"access_token" binding:"required"`
 }
 
-func (h *SocialLinksHandler) VerifyYouTubeChannel(c *gin.Context) {
+// VerifyChannel checks an access token the client obtained itself. Such a
+// token may have been issued to any app, so this path is off unless
+// RAW_TOKEN_VERIFY is set; use the server-side OAuth flow or a description
+// code instead.
+func (h *SocialLinksHandler) VerifyChannel(c *gin.Context) {
 	userID := middleware.MustUserID(c)
 	if userID == 0 {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
 		return
 	}
+	if !h.rawTokens {
+		c.JSON(http.StatusForbidden, gin.H{"error": "access_token verification is disabled; use /oauth/start or /verify/code"})
+		return
+	}
 
-	var req verifyYouTubeReq
+	p, ok := providerParam(c, h.platforms)
+	if !ok {
+		return
+	}
+
+	var req verifySocialReq
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	channelID := strings.TrimSpace(req.ChannelID)
-	accessToken := strings.TrimSpace(req.AccessToken)
-	
-	ctx := c.Request.Context()
-
-	// 1. Check DB for existing link (stores result in 'err')
-	existing, err := h.store.GetSocialLinkByPlatformUser(ctx, db.GetSocialLinkByPlatformUserParams{
-		Platform:       "youtube",
-		PlatformUserID: channelID,
-	})
-    
-    // SAFETY: Catch real DB errors (not just 'no rows')
-	if err != nil && err != sql.ErrNoRows {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read existing link from DB"})
+	channelID, ok := normalizeID(c, p, platformUserID(req.ID, req.ChannelID))
+	if !ok {
 		return
 	}
+	accessToken := strings.TrimSpace(req.AccessToken)
+
+	ctx := c.Request.Context()
 
-    // 2. Takeover protection
-	if err == nil && existing.UserID != userID && existing.VerifiedAt.Valid {
-		c.JSON(http.StatusConflict, gin.H{"error": "channel already verified by another user"})
+	// 1. Existing link + takeover protection
+	existing, err := h.existingLink(ctx, userID, p.Name(), channelID)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	// 3. OAuth ownership check (uses 'verifyErr' so we don't overwrite 'err')
-	ok, verifyErr := verifyYouTubeOwnership(ctx, accessToken, channelID)
+	// 2. OAuth ownership check
+	ok, verifyErr := p.VerifyOwnership(ctx, accessToken, channelID)
 	if verifyErr != nil {
 		c.JSON(http.StatusBadGateway, gin.H{"error": verifyErr.Error()})
 		return
 	}
 	if !ok {
-		c.JSON(http.StatusForbidden, gin.H{"error": "oauth token does not own this channel_id"})
+		c.JSON(http.StatusForbidden, gin.H{"error": "oauth token does not own this " + p.Name() + " account"})
+		return
+	}
+
+	// 3. Create, verify or take over the link and backfill ledger attribution
+	t, err := h.markVerified(ctx, userID, p.Name(), channelID, existing, "oauth")
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	now := time.Now()
+	c.JSON(verifiedBody("oauth", t))
+}
+
+// markVerified records a successful ownership proof. existing is the current
+// link for the channel, or the zero value when there is none.
+//
+// When another user still holds a live verification the link does not move:
+// a pending transfer is opened instead and returned, and the previous owner
+// gets the cooling-off period to dispute it. The returned transfer has ID 0
+// when the caller was verified directly.
+func (h *SocialLinksHandler) markVerified(ctx context.Context, userID int64, platformName, channelID string, existing db.SocialLink, method string) (db.ChannelTransfer, error) {
+	takeover := existing.ID != 0 && existing.UserID != userID
+	if takeover && existing.VerifiedAt.Valid && !existing.StaleAt.Valid {
+		// Verified by someone else: open a transfer with a cooling-off period
+		return h.requestTransfer(ctx, userID, platformName, channelID, existing, method)
+	}
 
-	// 4. Use the ORIGINAL 'err' from step 1 to decide what to do
+	// Create, re-verify or take over (previous link was unverified or lapsed)
+	// the link and start this user's ownership epoch together. Ledger
+	// attribution is re-derived from the epochs, so tips sent while unlinked
+	// or linked to a squatter go to the real owner now.
+	if err := h.store.VerifySocialLinkTx(ctx, db.VerifySocialLinkTxParams{
+		UserID:         userID,
+		Platform:       platformName,
+		PlatformUserID: channelID,
+		Method:         method,
+		Existing:       existing,
+	}); err != nil {
+		if existing.ID == 0 {
+			return db.ChannelTransfer{}, newHTTPError(http.StatusBadRequest, "failed to create social link")
+		}
+		return db.ChannelTransfer{}, newHTTPError(http.StatusInternalServerError, "failed to mark verified")
+	}
+	if takeover {
+		h.recordTakeover(ctx, userID, platformName, channelID, existing, method)
+	}
+
+	h.resolveCache.Invalidate(platformName, channelID)
+	h.flagReleasedEscrow(ctx, userID, platformName, channelID)
+	return db.ChannelTransfer{}, nil
+}
+
+// requestTransfer opens a pending transfer of a channel verified by another
+// user. Claim signing for the channel is held until it is resolved.
+func (h *SocialLinksHandler) requestTransfer(ctx context.Context, userID int64, platformName, channelID string, existing db.SocialLink, method string) (db.ChannelTransfer, error) {
+	t, err := h.store.CreateChannelTransfer(ctx, db.CreateChannelTransferParams{
+		Platform:       platformName,
+		PlatformUserID: channelID,
+		FromUserID:     sql.NullInt64{Int64: existing.UserID, Valid: true},
+		ToUserID:       userID,
+		Method:         method,
+		Status:         "pending",
+		EligibleAt:     sql.NullTime{Time: time.Now().UTC().Add(h.transferCooloff), Valid: true},
+	})
+	if err != nil {
+		// Partial unique index: one open transfer per channel.
+		return db.ChannelTransfer{}, newHTTPError(http.StatusConflict, "an ownership transfer for this channel is already open")
+	}
+
+	notifyUser(ctx, h.store.Queries, existing.UserID, "channel_transfer_requested", fmt.Sprintf(
+		"Another account proved ownership of %s channel %s. It will move to that account after %s "+
+			"unless you dispute transfer #%d. Claims for the channel are on hold until then.",
+		platformName, channelID, t.EligibleAt.Time.UTC().Format(time.RFC3339), t.ID,
+	))
+	return t, nil
+}
+
+// recordTakeover logs an immediate ownership change (the previous link was
+// unverified or lapsed) and tells the previous owner.
+func (h *SocialLinksHandler) recordTakeover(ctx context.Context, userID int64, platformName, channelID string, existing db.SocialLink, method string) {
+	if _, err := h.store.CreateChannelTransfer(ctx, db.CreateChannelTransferParams{
+		Platform:       platformName,
+		PlatformUserID: channelID,
+		FromUserID:     sql.NullInt64{Int64: existing.UserID, Valid: true},
+		ToUserID:       userID,
+		Method:         method,
+		Status:         "completed",
+	}); err != nil {
+		log.Printf("record transfer %s/%s: %v", platformName, channelID, err)
+	}
+	if err := h.store.DropChannelPayoutsTx(ctx, platformName, channelID); err != nil {
+		log.Printf("drop channel payouts %s/%s: %v", platformName, channelID, err)
+	}
+
+	state := "unverified"
+	if existing.StaleAt.Valid {
+		state = "lapsed"
+	}
+	notifyUser(ctx, h.store.Queries, existing.UserID, "channel_transferred", fmt.Sprintf(
+		"Your %s link to %s channel %s was taken over by another account.", state, platformName, channelID,
+	))
+}
+
+// verifiedBody is the response for a successful ownership proof: either
+// verified now, or a transfer waiting out its cooling-off period.
+func verifiedBody(method string, t db.ChannelTransfer) (int, gin.H) {
+	if t.ID == 0 {
+		return http.StatusOK, gin.H{"verified": true, "method": method}
+	}
+	return http.StatusAccepted, gin.H{
+		"verified":    false,
+		"method":      method,
+		"status":      "transfer_pending",
+		"transfer_id": t.ID,
+		"eligible_at": t.EligibleAt.Time,
+	}
+}
+
+// existingLink loads the current link for a channel and refuses to continue
+// while someone else's ownership transfer for it is open.
+func (h *SocialLinksHandler) existingLink(ctx context.Context, userID int64, platformName, channelID string) (db.SocialLink, error) {
+	existing, err := h.store.GetSocialLinkByPlatformUser(ctx, db.GetSocialLinkByPlatformUserParams{
+		Platform:       platformName,
+		PlatformUserID: channelID,
+	})
 	if err == sql.ErrNoRows {
-		// NO existing link: Create and mark verified
-		if _, err := h.store.CreateSocialLink(ctx, db.CreateSocialLinkParams{
-			UserID:         userID,
-			Platform:       "youtube",
+		return db.SocialLink{}, nil
+	}
+	if err != nil {
+		return db.SocialLink{}, newHTTPError(http.StatusInternalServerError, "failed to read existing link from DB")
+	}
+	if existing.UserID != userID && existing.VerifiedAt.Valid && !existing.StaleAt.Valid {
+		t, err := h.store.GetOpenChannelTransfer(ctx, db.GetOpenChannelTransferParams{
+			Platform:       platformName,
 			PlatformUserID: channelID,
-			VerifiedAt:     sql.NullTime{Time: now, Valid: true},
-		}); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to create social link"})
-			return
+		})
+		if err == nil {
+			return db.SocialLink{}, newHTTPError(http.StatusConflict, fmt.Sprintf("ownership transfer #%d for this channel is already open", t.ID))
 		}
-	} else {
-		// Existing link found (err was nil)
-		if existing.UserID == userID {
-			if !existing.VerifiedAt.Valid {
-				if _, err := h.store.UpdateSocialLinkVerifiedAt(ctx, db.UpdateSocialLinkVerifiedAtParams{
-					ID:         existing.ID,
-					VerifiedAt: sql.NullTime{Time: now, Valid: true},
-				}); err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to mark verified"})
-					return
-				}
-			}
-		} else {
-			// Takeover + verify
-			if _, err := h.store.TransferSocialLinkToUser(ctx, db.TransferSocialLinkToUserParams{
-				ID:         existing.ID,
-				UserID:     userID,
-				VerifiedAt: sql.NullTime{Time: now, Valid: true},
-			}); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to takeover social link"})
-				return
-			}
+		if err != sql.ErrNoRows {
+			return db.SocialLink{}, newHTTPError(http.StatusInternalServerError, "failed to read channel transfers")
 		}
 	}
+	return existing, nil
+}
+
+func generateVerificationCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "tipmnee-" + strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)), nil
+}
+
+type descriptionCodeReq struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"` // YouTube clients still send channel_id
+}
+
+// descriptionReader resolves the route's provider and channel id and checks
+// that the platform exposes a public description.
+func (h *SocialLinksHandler) descriptionReader(c *gin.Context) (platform.Provider, platform.DescriptionReader, string, bool) {
+	p, ok := providerParam(c, h.platforms)
+	if !ok {
+		return nil, nil, "", false
+	}
+	dr, ok := p.(platform.DescriptionReader)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": p.Name() + " does not support description-code verification"})
+		return nil, nil, "", false
+	}
+
+	var req descriptionCodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, nil, "", false
+	}
+
+	channelID, ok := normalizeID(c, p, platformUserID(req.ID, req.ChannelID))
+	if !ok {
+		return nil, nil, "", false
+	}
+	return p, dr, channelID, true
+}
 
-	// 5. Backfill ALL ledger events for this channel to this user.
-	// This ensures that if tips were sent while unlinked or linked to a squatter, 
-	// the real owner gets them now.
-	_ = h.store.BackfillLedgerEventsUserIDForChannel(ctx, db.BackfillLedgerEventsUserIDForChannelParams{
-		UserID:         sql.NullInt64{Int64: userID, Valid: true},
-		Platform:       "youtube",
+/*
+Description-code verification (no OAuth scopes needed):
+- Creator asks for a one-time code for a channel.
+- Creator pastes it anywhere in the channel description or profile bio.
+- Creator calls check; we read the public description with our API key.
+*/
+func (h *SocialLinksHandler) IssueDescriptionCode(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	p, _, channelID, ok := h.descriptionReader(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if _, err := h.existingLink(ctx, userID, p.Name(), channelID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate code"})
+		return
+	}
+
+	vc, err := h.store.UpsertVerificationCode(ctx, db.UpsertVerificationCodeParams{
+		UserID:         userID,
+		Platform:       p.Name(),
 		PlatformUserID: channelID,
+		Code:           code,
+		ExpiresAt:      time.Now().UTC().Add(h.codeTTL).Truncate(time.Second),
 	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store code"})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"verified": true})
+	c.JSON(http.StatusOK, gin.H{
+		"code":         vc.Code,
+		"expires_at":   vc.ExpiresAt,
+		"instructions": "Add the code anywhere in your " + p.Name() + " channel description or bio, then call the check endpoint. You can remove it once verified.",
+	})
 }
 
-// Calls YouTube Data API: GET /youtube/v3/channels?part=id&mine=true
-func verifyYouTubeOwnership(ctx context.Context, accessToken, channelID string) (bool, error) {
-	url := "https://www.googleapis.com/youtube/v3/channels?part=id&mine=true"
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+func (h *SocialLinksHandler) CheckDescriptionCode(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	p, dr, channelID, ok := h.descriptionReader(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	vc, err := h.store.GetVerificationCode(ctx, db.GetVerificationCodeParams{
+		UserID:         userID,
+		Platform:       p.Name(),
+		PlatformUserID: channelID,
+	})
 	if err != nil {
-		return false, err
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no code issued for this channel"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read code"})
+		return
+	}
+	if vc.UsedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "code already used; request a new one"})
+		return
+	}
+	if time.Now().After(vc.ExpiresAt) {
+		c.JSON(http.StatusGone, gin.H{"error": "code expired; request a new one"})
+		return
 	}
-	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	existing, err := h.existingLink(ctx, userID, p.Name(), channelID)
 	if err != nil {
-		return false, fmt.Errorf("youtube api request failed: %w", err)
+		respondError(c, err)
+		return
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
-	if resp.StatusCode != http.StatusOK {
-		msg := strings.TrimSpace(string(body))
-		if len(msg) > 400 {
-			msg = msg[:400] + "..."
+	desc, err := dr.Description(ctx, channelID)
+	if err != nil {
+		if errors.Is(err, platform.ErrProfileUnavailable) {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "description lookup not configured"})
+			return
 		}
-		if msg == "" {
-			msg = resp.Status
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	if !strings.Contains(desc, vc.Code) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "code not found in channel description"})
+		return
+	}
+
+	t, err := h.markVerified(ctx, userID, p.Name(), channelID, existing, "description_code")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	_ = h.store.MarkVerificationCodeUsed(ctx, vc.ID)
+
+	c.JSON(verifiedBody("description_code", t))
+}
+
+// Public: display metadata for a creator account, straight from the platform.
+func (h *SocialLinksHandler) GetProfile(c *gin.Context) {
+	p, ok := providerParam(c, h.platforms)
+	if !ok {
+		return
+	}
+
+	// YouTube profiles also take a handle or channel URL and are cached.
+	if p.Name() == "youtube" {
+		id, ok := youtubeChannelParam(c, h.youtube, c.Param("id"))
+		if !ok {
+			return
+		}
+		prof, err := h.youtube.Profile(c.Request.Context(), id)
+		if err != nil {
+			respondProfileError(c, err)
+			return
 		}
-		return false, fmt.Errorf("youtube api error: %s", msg)
+		c.JSON(http.StatusOK, prof)
+		return
 	}
 
-	type ytResp struct {
-		Items []struct {
-			ID string `json:"id"`
-		} `json:"items"`
+	id, ok := normalizeID(c, p, c.Param("id"))
+	if !ok {
+		return
 	}
-	var out ytResp
-	if err := json.Unmarshal(body, &out); err != nil {
-		return false, fmt.Errorf("failed to parse youtube response: %w", err)
+
+	prof, err := p.Profile(c.Request.Context(), id)
+	if err != nil {
+		respondProfileError(c, err)
+		return
 	}
-	if len(out.Items) == 0 {
-		return false, errors.New("no channels returned from youtube; token may lack youtube scopes")
+
+	c.JSON(http.StatusOK, prof)
+}
+
+// UnverifiedLinkSweepJob deletes unverified links older than
+// UNVERIFIED_LINK_TTL every UNVERIFIED_LINK_SWEEP_EVERY, so the table-wide
+// delete stays off the request path.
+type UnverifiedLinkSweepJob struct {
+	social *SocialLinksHandler
+	every  time.Duration
+}
+
+func NewUnverifiedLinkSweepJob(social *SocialLinksHandler) (*UnverifiedLinkSweepJob, error) {
+	every, err := durationEnv("UNVERIFIED_LINK_SWEEP_EVERY", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	if every == 0 {
+		return nil, errEnv("UNVERIFIED_LINK_SWEEP_EVERY (must be > 0)")
 	}
+	return &UnverifiedLinkSweepJob{social: social, every: every}, nil
+}
+
+// Run sweeps now and then every UNVERIFIED_LINK_SWEEP_EVERY until ctx is done.
+func (j *UnverifiedLinkSweepJob) Run(ctx context.Context) {
+	t := time.NewTicker(j.every)
+	defer t.Stop()
 
-	for _, it := range out.Items {
-		if strings.TrimSpace(it.ID) == channelID {
-			return true, nil
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("unverified link sweep: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
 		}
 	}
-	return false, nil
+}
+
+func (j *UnverifiedLinkSweepJob) RunOnce(ctx context.Context) error {
+	_, err := j.social.store.DeleteExpiredUnverifiedSocialLinks(ctx, time.Now().Add(-j.social.unverifiedTTL))
+	return err
 }
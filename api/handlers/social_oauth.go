@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/YoshiTheExplorer/TipMNEE/util"
+	"github.com/gin-gonic/gin"
+)
+
+const youtubeReadonlyScope = "https://www.googleapis.com/auth/youtube.readonly"
+
+// googleOAuth is the server-side authorization-code flow used to verify
+// YouTube channels without the client ever holding a Google token.
+type googleOAuth struct {
+	client    *util.OAuthClient
+	secrets   *util.SecretBox
+	stateTTL  time.Duration
+	resultURL string
+}
+
+// googleOAuthFromEnv returns nil when GOOGLE_OAUTH_CLIENT_ID,
+// GOOGLE_OAUTH_CLIENT_SECRET and GOOGLE_OAUTH_REDIRECT_URL are all unset.
+// GOOGLE_OAUTH_AUTH_URL / GOOGLE_OAUTH_TOKEN_URL point the flow at a local
+// stand-in; GOOGLE_OAUTH_RESULT_URL is where the callback redirects the
+// browser (JSON is returned when unset).
+func googleOAuthFromEnv() (*googleOAuth, error) {
+	clientID := strings.TrimSpace(os.Getenv("GOOGLE_OAUTH_CLIENT_ID"))
+	clientSecret := strings.TrimSpace(os.Getenv("GOOGLE_OAUTH_CLIENT_SECRET"))
+	redirectURL := strings.TrimSpace(os.Getenv("GOOGLE_OAUTH_REDIRECT_URL"))
+	if clientID == "" && clientSecret == "" && redirectURL == "" {
+		return nil, nil
+	}
+	if clientID == "" || clientSecret == "" || redirectURL == "" {
+		return nil, errEnv("GOOGLE_OAUTH_CLIENT_ID, GOOGLE_OAUTH_CLIENT_SECRET and GOOGLE_OAUTH_REDIRECT_URL must be set together")
+	}
+
+	secrets, err := util.NewSecretBox(os.Getenv("OAUTH_TOKEN_ENCRYPTION_KEY"))
+	if err != nil {
+		return nil, errEnv("OAUTH_TOKEN_ENCRYPTION_KEY (must be 32 bytes hex)")
+	}
+
+	stateTTL, err := durationEnv("OAUTH_STATE_TTL", 10*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	return &googleOAuth{
+		client: util.NewOAuthClient(
+			clientID,
+			clientSecret,
+			os.Getenv("GOOGLE_OAUTH_AUTH_URL"),
+			os.Getenv("GOOGLE_OAUTH_TOKEN_URL"),
+			redirectURL,
+			[]string{youtubeReadonlyScope},
+		),
+		secrets:   secrets,
+		stateTTL:  stateTTL,
+		resultURL: strings.TrimSpace(os.Getenv("GOOGLE_OAUTH_RESULT_URL")),
+	}, nil
+}
+
+// oauthCredentialKey is the unique key of an oauth_credentials row, bound to
+// the sealed refresh token it holds.
+func oauthCredentialKey(platformName, platformUserID string) []byte {
+	return []byte("oauth_credentials:" + platformName + ":" + platformUserID)
+}
+
+func newOAuthState() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// begin stores the state and PKCE verifier of a new code flow for the channel
+// and returns the consent URL to send the browser to. purpose is what the
+// callback does once ownership is proven ("verify" or "recovery").
+func (o *googleOAuth) begin(ctx context.Context, store *db.Store, userID int64, platformName, channelID, purpose string) (gin.H, error) {
+	state, err := newOAuthState()
+	if err != nil {
+		return nil, newHTTPError(http.StatusInternalServerError, "failed to generate state")
+	}
+	verifier, err := util.NewPKCEVerifier()
+	if err != nil {
+		return nil, newHTTPError(http.StatusInternalServerError, "failed to generate code verifier")
+	}
+
+	_ = store.DeleteExpiredOAuthStates(ctx)
+
+	expiresAt := time.Now().UTC().Add(o.stateTTL).Truncate(time.Second)
+	if err := store.CreateOAuthState(ctx, db.CreateOAuthStateParams{
+		State:          state,
+		UserID:         userID,
+		Platform:       platformName,
+		PlatformUserID: channelID,
+		CodeVerifier:   verifier,
+		Purpose:        purpose,
+		ExpiresAt:      expiresAt,
+	}); err != nil {
+		return nil, newHTTPError(http.StatusInternalServerError, "failed to store oauth state")
+	}
+
+	return gin.H{
+		"auth_url":   o.client.AuthCodeURL(state, verifier),
+		"expires_at": expiresAt,
+	}, nil
+}
+
+/*
+OAuth code-flow verification:
+  - Creator asks to start the flow for a channel; we store state + PKCE verifier
+    and return Google's consent URL.
+  - Google redirects the browser to the callback with code + state.
+  - We exchange the code ourselves, check the channel with the access token and
+    keep the refresh token (encrypted) for later re-checks.
+*/
+func (h *SocialLinksHandler) StartOAuth(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	if h.oauth == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "oauth code flow not configured"})
+		return
+	}
+
+	p, ok := providerParam(c, h.platforms)
+	if !ok {
+		return
+	}
+	if p.Name() != "youtube" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": p.Name() + " does not support the oauth code flow"})
+		return
+	}
+
+	var req linkSocialReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channelID, ok := normalizeID(c, p, platformUserID(req.ID, req.ChannelID))
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if _, err := h.existingLink(ctx, userID, p.Name(), channelID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	body, err := h.oauth.begin(ctx, h.store, userID, p.Name(), channelID, "verify")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// oauthResult answers the callback, either as JSON or by redirecting the
+// browser back to the frontend with the result in the query string.
+func (h *SocialLinksHandler) oauthResult(c *gin.Context, status int, body gin.H) {
+	if h.oauth == nil || h.oauth.resultURL == "" {
+		c.JSON(status, body)
+		return
+	}
+
+	q := url.Values{}
+	for k, v := range body {
+		q.Set(k, fmt.Sprint(v))
+	}
+	sep := "?"
+	if strings.Contains(h.oauth.resultURL, "?") {
+		sep = "&"
+	}
+	c.Redirect(http.StatusFound, h.oauth.resultURL+sep+q.Encode())
+}
+
+// oauthFailure is respondError for the callback.
+func (h *SocialLinksHandler) oauthFailure(c *gin.Context, err error) {
+	status, msg := http.StatusInternalServerError, err.Error()
+	if he, ok := err.(*httpError); ok {
+		status, msg = he.status, he.msg
+	}
+	h.oauthResult(c, status, gin.H{"verified": false, "error": msg})
+}
+
+// Public: Google redirects here; the state row identifies the user and
+// whether the flow verifies the channel or starts an account recovery.
+func (h *SocialLinksHandler) OAuthCallback(c *gin.Context) {
+	if h.oauth == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "oauth code flow not configured"})
+		return
+	}
+
+	state := strings.TrimSpace(c.Query("state"))
+	if state == "" {
+		h.oauthResult(c, http.StatusBadRequest, gin.H{"verified": false, "error": "missing state"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// Consuming deletes the row, so a state can only ever be used once.
+	st, err := h.store.ConsumeOAuthState(ctx, state)
+	if err != nil {
+		h.oauthResult(c, http.StatusBadRequest, gin.H{"verified": false, "error": "unknown or already used state"})
+		return
+	}
+	if time.Now().After(st.ExpiresAt) {
+		h.oauthResult(c, http.StatusGone, gin.H{"verified": false, "error": "oauth flow expired; start again"})
+		return
+	}
+
+	if e := c.Query("error"); e != "" {
+		h.oauthResult(c, http.StatusForbidden, gin.H{"verified": false, "error": "authorization denied: " + e})
+		return
+	}
+	code := strings.TrimSpace(c.Query("code"))
+	if code == "" {
+		h.oauthResult(c, http.StatusBadRequest, gin.H{"verified": false, "error": "missing code"})
+		return
+	}
+
+	p, ok := h.platforms.Get(st.Platform)
+	if !ok {
+		h.oauthResult(c, http.StatusBadRequest, gin.H{"verified": false, "error": "unsupported platform"})
+		return
+	}
+
+	tok, err := h.oauth.client.Exchange(ctx, code, st.CodeVerifier)
+	if err != nil {
+		h.oauthResult(c, http.StatusBadGateway, gin.H{"verified": false, "error": err.Error()})
+		return
+	}
+
+	if st.Purpose == "recovery" {
+		r, err := h.recovery.recoverWithOAuth(ctx, st, p, tok.AccessToken)
+		if err != nil {
+			h.oauthFailure(c, err)
+			return
+		}
+		h.oauthResult(c, http.StatusOK, gin.H{
+			"verified":    true,
+			"platform":    p.Name(),
+			"id":          st.PlatformUserID,
+			"recovery_id": r.ID,
+			"eligible_at": r.EligibleAt.UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	existing, err := h.existingLink(ctx, st.UserID, p.Name(), st.PlatformUserID)
+	if err != nil {
+		h.oauthFailure(c, err)
+		return
+	}
+
+	owns, err := p.VerifyOwnership(ctx, tok.AccessToken, st.PlatformUserID)
+	if err != nil {
+		h.oauthResult(c, http.StatusBadGateway, gin.H{"verified": false, "error": err.Error()})
+		return
+	}
+	if !owns {
+		h.oauthResult(c, http.StatusForbidden, gin.H{"verified": false, "error": "google account does not own this " + p.Name() + " channel"})
+		return
+	}
+
+	t, err := h.markVerified(ctx, st.UserID, p.Name(), st.PlatformUserID, existing, "oauth")
+	if err != nil {
+		h.oauthFailure(c, err)
+		return
+	}
+
+	// Google only returns a refresh token on consent; keep the last one we got.
+	// While a transfer is pending the owner's credential stays in place and the
+	// token waits on the transfer.
+	if tok.RefreshToken != "" {
+		sealed, err := h.oauth.secrets.Seal(tok.RefreshToken, oauthCredentialKey(p.Name(), st.PlatformUserID))
+		if err == nil && t.ID != 0 {
+			err = h.store.CreateChannelTransferCredential(ctx, db.CreateChannelTransferCredentialParams{
+				TransferID:      t.ID,
+				RefreshTokenEnc: sealed,
+				Scope:           tok.Scope,
+			})
+		} else if err == nil {
+			_, err = h.store.UpsertOAuthCredential(ctx, db.UpsertOAuthCredentialParams{
+				UserID:          st.UserID,
+				Platform:        p.Name(),
+				PlatformUserID:  st.PlatformUserID,
+				RefreshTokenEnc: sealed,
+				Scope:           tok.Scope,
+			})
+		}
+		if err != nil {
+			h.oauthResult(c, http.StatusInternalServerError, gin.H{"verified": t.ID == 0, "error": "ownership proven, but failed to store refresh token"})
+			return
+		}
+	}
+
+	status, body := verifiedBody("oauth", t)
+	body["platform"] = p.Name()
+	body["id"] = st.PlatformUserID
+	h.oauthResult(c, status, body)
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/YoshiTheExplorer/TipMNEE/platform"
+	"github.com/gin-gonic/gin"
+)
+
+type unlinkSocialReq struct {
+	// Confirm must repeat the channel id being unlinked.
+	Confirm string `json:"confirm"`
+}
+
+/*
+Unlink a channel:
+  - The link is deleted, so resolve stops paying the creator directly at once;
+    new tips go to escrow until someone verifies the channel again.
+  - Ledger history and ownership epochs are kept.
+  - Escrow still unclaimed for the channel is recorded and shown to the next
+    verified owner.
+  - An open transfer to another user completes immediately.
+*/
+func (h *SocialLinksHandler) UnlinkChannel(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	p, ok := providerParam(c, h.platforms)
+	if !ok {
+		return
+	}
+	channelID, ok := normalizeID(c, p, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	var req unlinkSocialReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	confirm, err := platform.ResolveID(c.Request.Context(), p, req.Confirm)
+	if err != nil || confirm != channelID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "confirm must repeat the channel id: " + channelID})
+		return
+	}
+
+	if !h.stepUp.Require(c, userID, stepUpChannelUnlink, map[string]string{
+		"platform": p.Name(),
+		"id":       channelID,
+	}) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	res, err := h.store.ReleaseSocialLinkTx(ctx, db.ReleaseSocialLinkTxParams{
+		UserID:         userID,
+		Platform:       p.Name(),
+		PlatformUserID: channelID,
+	})
+	switch {
+	case err == sql.ErrNoRows, errors.Is(err, db.ErrLinkNotOwned):
+		c.JSON(http.StatusNotFound, gin.H{"error": "channel is not linked to this account"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unlink channel"})
+		return
+	}
+	h.resolveCache.Invalidate(p.Name(), channelID)
+
+	body := gin.H{
+		"unlinked":             true,
+		"platform":             p.Name(),
+		"id":                   channelID,
+		"unclaimed_escrow_raw": res.Release.UnclaimedEscrowRaw,
+	}
+	if t := res.Transfer; t != nil {
+		body["transfer"] = t
+		notifyUser(ctx, h.store.Queries, t.ToUserID, "channel_transfer_completed", fmt.Sprintf(
+			"The previous owner unlinked %s channel %s, so your transfer completed.", p.Name(), channelID,
+		))
+		h.flagReleasedEscrow(ctx, t.ToUserID, p.Name(), channelID)
+	}
+
+	c.JSON(http.StatusOK, body)
+}
+
+// flagReleasedEscrow tells a newly verified owner about escrow left unclaimed
+// when the channel was unlinked. Each release is only flagged once.
+func (h *SocialLinksHandler) flagReleasedEscrow(ctx context.Context, userID int64, platformName, channelID string) {
+	releases, err := h.store.FlagChannelReleasesForNextOwner(ctx, db.FlagChannelReleasesForNextOwnerParams{
+		NextOwnerID:    userID,
+		Platform:       platformName,
+		PlatformUserID: channelID,
+	})
+	if err != nil {
+		log.Printf("flag released escrow %s/%s: %v", platformName, channelID, err)
+		return
+	}
+	if len(releases) == 0 {
+		return
+	}
+
+	balance, err := h.store.GetChannelEscrowBalance(ctx, db.GetChannelEscrowBalanceParams{
+		Platform:       platformName,
+		PlatformUserID: channelID,
+	})
+	if err != nil {
+		log.Printf("flag released escrow %s/%s: %v", platformName, channelID, err)
+		return
+	}
+	notifyUser(ctx, h.store.Queries, userID, "released_escrow_available", fmt.Sprintf(
+		"%s channel %s was unlinked by its previous owner with tips still in escrow. "+
+			"The escrow balance is now %s (raw units) and can be claimed by you.",
+		platformName, channelID, balance,
+	))
+}
@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/YoshiTheExplorer/TipMNEE/api/middleware"
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	util "github.com/YoshiTheExplorer/TipMNEE/util"
+	"github.com/gin-gonic/gin"
+)
+
+// Step-up actions.
+const (
+	stepUpPayoutChange       = "payout_change"
+	stepUpPayoutDelete       = "payout_delete"
+	stepUpPayoutChangeCancel = "payout_change_cancel"
+	stepUpSplitChange        = "split_change"
+	stepUpSplitDelete        = "split_delete"
+	stepUpClaim              = "claim"
+	stepUpTransferComplete   = "transfer_complete"
+	stepUpChannelUnlink      = "channel_unlink"
+	stepUpAccountMerge       = "account_merge"
+	stepUpWalletLink         = "wallet_link"
+	stepUpGoogleLink         = "google_link"
+	stepUpIdentityUnlink     = "identity_unlink"
+)
+
+// stepUpActions lists the parameters each action's challenge binds, in message order.
+var stepUpActions = map[string][]string{
+	stepUpPayoutChange:       {"chain", "address", "platform", "id"},
+	stepUpPayoutDelete:       {"chain", "platform", "id"},
+	stepUpPayoutChangeCancel: {"change_id"},
+	stepUpSplitChange:        {"platform", "id", "recipients"},
+	stepUpSplitDelete:        {"platform", "id"},
+	stepUpClaim:              {"platform", "id", "payout_address"},
+	stepUpTransferComplete:   {"transfer_id"},
+	stepUpChannelUnlink:      {"platform", "id"},
+	stepUpAccountMerge:       {"address"},
+	stepUpWalletLink:         {"address"},
+	stepUpGoogleLink:         {},
+	stepUpIdentityUnlink:     {"identity_id"},
+}
+
+// splitStepUpParam is the "recipients" parameter of split_change, e.g.
+// "0xabc…:6000,0xdef…:4000", in the order the recipients were sent.
+func splitStepUpParam(recipients []db.SplitRecipient) string {
+	parts := make([]string, len(recipients))
+	for i, r := range recipients {
+		parts[i] = fmt.Sprintf("%s:%d", normalizeAddress(r.Address), r.Bps)
+	}
+	return strings.Join(parts, ",")
+}
+
+/*
+StepUpHandler re-authenticates sensitive requests, so a stolen JWT alone
+cannot redirect payouts, sign claims, take over channels or add sign-in
+methods. Every action in stepUpActions needs a proof:
+  - Accounts with a wallet sign a challenge. POST /api/me/step-up/message
+    issues a one-time challenge for one of the user's wallets that names the
+    action and its parameters (e.g. the new payout address) and expires after
+    STEP_UP_TTL. The request carries the challenge id and the personal_sign
+    signature in the X-Step-Up-Id and X-Step-Up-Signature headers and is
+    refused unless its own parameters match the signed ones.
+  - Google-only accounts have nothing to sign with. They send a Google ID
+    token for one of their Google identities, issued within STEP_UP_TTL (a
+    fresh sign-in), in X-Step-Up-Google-Token. Once a wallet is linked (which
+    itself needs that token) only the wallet is accepted.
+*/
+type StepUpHandler struct {
+	store  *db.Queries
+	google util.GoogleVerifier
+	ttl    time.Duration
+}
+
+func NewStepUpHandler(store *db.Queries, google util.GoogleVerifier) (*StepUpHandler, error) {
+	ttl, err := durationEnv("STEP_UP_TTL", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	if ttl == 0 {
+		return nil, errEnv("STEP_UP_TTL (must be > 0)")
+	}
+	return &StepUpHandler{store: store, google: google, ttl: ttl}, nil
+}
+
+func stepUpMessage(userID int64, addr, action string, params map[string]string, nonce string, expires time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "TipMNEE wants you to confirm a sensitive action on account #%d.\n\nAction: %s\n", userID, action)
+	for _, key := range stepUpActions[action] {
+		value := params[key]
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(&b, "%s: %s\n", key, value)
+	}
+	fmt.Fprintf(&b, "Wallet: %s\nNonce: %s\nExpires: %s", addr, nonce, expires.UTC().Format(time.RFC3339))
+	return b.String()
+}
+
+// stepUpParams trims values and lowercases addresses (and ENS names) the way
+// the sensitive handlers normalize them.
+func stepUpParams(action string, raw map[string]string) (map[string]string, error) {
+	keys, ok := stepUpActions[action]
+	if !ok {
+		return nil, newHTTPError(http.StatusBadRequest, "unknown step-up action")
+	}
+	allowed := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		allowed[k] = true
+	}
+
+	params := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if !allowed[k] {
+			return nil, newHTTPError(http.StatusBadRequest, fmt.Sprintf("unknown %s parameter %q", action, k))
+		}
+		v = strings.TrimSpace(v)
+		switch k {
+		case "address", "payout_address":
+			v = normalizeAddress(v)
+		case "recipients":
+			v = strings.ToLower(strings.ReplaceAll(v, " ", ""))
+		}
+		params[k] = v
+	}
+	return params, nil
+}
+
+type stepUpMessageReq struct {
+	Address string            `json:"address" binding:"required"` // one of the account's wallets
+	Action  string            `json:"action" binding:"required"`
+	Params  map[string]string `json:"params"`
+}
+
+// Protected: challenge a wallet of this account signs to confirm one action.
+func (h *StepUpHandler) GetStepUpMessage(c *gin.Context) {
+	userID := middleware.MustUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authenticated"})
+		return
+	}
+
+	var req stepUpMessageReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	params, err := stepUpParams(req.Action, req.Params)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	addr := normalizeAddress(req.Address)
+
+	ident, err := h.store.GetIdentity(ctx, db.GetIdentityParams{Provider: "wallet", ProviderUserID: addr})
+	if err == sql.ErrNoRows || (err == nil && ident.UserID != userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "address is not a wallet of this account"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load wallet"})
+		return
+	}
+
+	nonce, err := generateNonce()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate nonce"})
+		return
+	}
+	expires := time.Now().UTC().Add(h.ttl).Truncate(time.Second)
+
+	if _, err := h.store.DeleteExpiredStepUpChallenges(ctx, time.Now()); err != nil {
+		log.Printf("delete expired step-up challenges: %v", err)
+	}
+	ch, err := h.store.CreateStepUpChallenge(ctx, db.CreateStepUpChallengeParams{
+		UserID:    userID,
+		Address:   addr,
+		Action:    req.Action,
+		Nonce:     nonce,
+		Message:   stepUpMessage(userID, addr, req.Action, params, nonce, expires),
+		ExpiresAt: expires,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store challenge"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": ch.ID, "message": ch.Message, "expires_at": ch.ExpiresAt})
+}
+
+// Require checks the request's step-up proof for action and params, and
+// answers the request itself when it is missing or invalid.
+func (h *StepUpHandler) Require(c *gin.Context, userID int64, action string, params map[string]string) bool {
+	ctx := c.Request.Context()
+
+	hasWallet, err := h.hasWallet(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load identities"})
+		return false
+	}
+
+	if !hasWallet {
+		token := strings.TrimSpace(c.GetHeader("X-Step-Up-Google-Token"))
+		if token == "" {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":          "confirm this action with a fresh Google sign-in: send its id_token as X-Step-Up-Google-Token, or link a wallet first",
+				"step_up_action": action,
+				"step_up_method": "google",
+			})
+			return false
+		}
+		if err := h.verifyGoogle(ctx, userID, token); err != nil {
+			respondError(c, err)
+			return false
+		}
+		return true
+	}
+
+	idRaw := strings.TrimSpace(c.GetHeader("X-Step-Up-Id"))
+	signature := strings.TrimSpace(c.GetHeader("X-Step-Up-Signature"))
+	if idRaw == "" && signature == "" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":          "confirm this action with your wallet: sign a challenge from /api/me/step-up/message",
+			"step_up_action": action,
+			"step_up_method": "wallet",
+		})
+		return false
+	}
+
+	if err := h.verify(ctx, userID, idRaw, signature, action, params); err != nil {
+		respondError(c, err)
+		return false
+	}
+	return true
+}
+
+func (h *StepUpHandler) hasWallet(ctx context.Context, userID int64) (bool, error) {
+	idents, err := h.store.ListIdentitiesByUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, ident := range idents {
+		if ident.Provider == "wallet" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// verifyGoogle accepts a Google ID token issued within the step-up TTL for
+// one of userID's Google identities.
+func (h *StepUpHandler) verifyGoogle(ctx context.Context, userID int64, token string) error {
+	if h.google == nil {
+		return newHTTPError(http.StatusServiceUnavailable, "google sign-in is not configured")
+	}
+	gi, err := h.google.VerifyIDToken(ctx, token)
+	if err != nil {
+		return newHTTPError(http.StatusUnauthorized, "invalid google id_token")
+	}
+	if gi.IssuedAt.IsZero() || time.Since(gi.IssuedAt) > h.ttl {
+		return newHTTPError(http.StatusUnauthorized, "google sign-in is too old: sign in again")
+	}
+
+	ident, err := h.store.GetIdentity(ctx, db.GetIdentityParams{Provider: "google", ProviderUserID: strings.TrimSpace(gi.Subject)})
+	if err == sql.ErrNoRows || (err == nil && ident.UserID != userID) {
+		return newHTTPError(http.StatusForbidden, "google account is not linked to this account")
+	}
+	if err != nil {
+		return newHTTPError(http.StatusInternalServerError, "failed to load identity")
+	}
+	return nil
+}
+
+func (h *StepUpHandler) verify(ctx context.Context, userID int64, idRaw, signature, action string, raw map[string]string) error {
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id <= 0 || signature == "" {
+		return newHTTPError(http.StatusBadRequest, "X-Step-Up-Id and X-Step-Up-Signature must both be set")
+	}
+	params, err := stepUpParams(action, raw)
+	if err != nil {
+		return err
+	}
+
+	// One-time use, whatever happens next.
+	ch, err := h.store.UseStepUpChallenge(ctx, db.UseStepUpChallengeParams{ID: id, UserID: userID})
+	if err == sql.ErrNoRows {
+		return newHTTPError(http.StatusUnauthorized, "unknown or already used step-up challenge")
+	}
+	if err != nil {
+		return newHTTPError(http.StatusInternalServerError, "failed to read step-up challenge")
+	}
+
+	if time.Now().UTC().Truncate(time.Second).After(ch.ExpiresAt.UTC()) {
+		return newHTTPError(http.StatusUnauthorized, "step-up challenge expired: request a new one")
+	}
+	if ch.Action != action || ch.Message != stepUpMessage(userID, ch.Address, action, params, ch.Nonce, ch.ExpiresAt) {
+		return newHTTPError(http.StatusUnauthorized, "step-up challenge was issued for a different action or parameters")
+	}
+
+	recovered, err := util.RecoverAddressFromPersonalSign(ch.Message, signature)
+	if err != nil {
+		return newHTTPError(http.StatusUnauthorized, "invalid step-up signature")
+	}
+	if normalizeAddress(recovered) != ch.Address {
+		return newHTTPError(http.StatusUnauthorized, "step-up signature does not match the wallet")
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	util "github.com/YoshiTheExplorer/TipMNEE/util"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeGoogle accepts any ID token as the given subject, issued at issuedAt.
+type fakeGoogle struct {
+	subject  string
+	issuedAt time.Time
+}
+
+func (f fakeGoogle) VerifyIDToken(context.Context, string) (*util.OIDCIdentity, error) {
+	return &util.OIDCIdentity{Subject: f.subject, IssuedAt: f.issuedAt}, nil
+}
+
+func (f fakeGoogle) VerifyAccessToken(context.Context, string) (*util.OIDCIdentity, error) {
+	return &util.OIDCIdentity{Subject: f.subject}, nil
+}
+
+// requireStepUp runs Require for a wallet-less account (the empty database
+// has no identities) and returns whether it passed and the response.
+func requireStepUp(t *testing.T, google util.GoogleVerifier, googleToken string) (bool, *httptest.ResponseRecorder) {
+	t.Helper()
+	h, err := NewStepUpHandler(emptyQueries(t), google)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/api/payouts/youtube/"+testChannelID, nil)
+	if googleToken != "" {
+		c.Request.Header.Set("X-Step-Up-Google-Token", googleToken)
+	}
+	ok := h.Require(c, 1, stepUpSplitDelete, map[string]string{"platform": "youtube", "id": testChannelID})
+	return ok, w
+}
+
+func TestRequireStepUpAsksWalletlessAccountsForGoogle(t *testing.T) {
+	ok, w := requireStepUp(t, fakeGoogle{subject: "123", issuedAt: time.Now()}, "")
+	if ok || w.Code != http.StatusForbidden {
+		t.Fatalf("without a proof: ok = %v, status = %d; want false, 403", ok, w.Code)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["step_up_method"] != "google" || body["step_up_action"] != stepUpSplitDelete {
+		t.Errorf("body = %v, want step_up_method google and step_up_action %s", body, stepUpSplitDelete)
+	}
+}
+
+func TestRequireStepUpRejectsGoogleProofs(t *testing.T) {
+	tests := []struct {
+		name   string
+		google util.GoogleVerifier
+		status int
+	}{
+		{"stale sign-in", fakeGoogle{subject: "123", issuedAt: time.Now().Add(-time.Hour)}, http.StatusUnauthorized},
+		{"unlinked google account", fakeGoogle{subject: "123", issuedAt: time.Now()}, http.StatusForbidden},
+		{"google not configured", nil, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, w := requireStepUp(t, tt.google, "id-token")
+			if ok || w.Code != tt.status {
+				t.Errorf("ok = %v, status = %d; want false, %d", ok, w.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestSplitStepUpParamMatchesClientParams(t *testing.T) {
+	recipients, err := validSplit([]db.SplitRecipient{
+		{Address: "0x00000000000000000000000000000000000A11CE", Bps: 6000},
+		{Address: "0x0000000000000000000000000000000000000B0B", Bps: 4000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromClient, err := stepUpParams(stepUpSplitChange, map[string]string{
+		"recipients": "0x00000000000000000000000000000000000A11CE:6000, 0x0000000000000000000000000000000000000B0B:4000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromHandler, err := stepUpParams(stepUpSplitChange, map[string]string{"recipients": splitStepUpParam(recipients)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromClient["recipients"] != fromHandler["recipients"] {
+		t.Errorf("client recipients %q != handler recipients %q", fromClient["recipients"], fromHandler["recipients"])
+	}
+}
@@ -24,11 +24,28 @@ func (h *UsersHandler) GetMe(c *gin.Context) {
 		return
 	}
 
-	usr, err := h.store.GetUserByID(c.Request.Context(), userID)
+	ctx := c.Request.Context()
+
+	usr, err := h.store.GetUserByID(ctx, userID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, usr)
+	// Effective payout per linked channel: its override, else the user default.
+	channels, err := h.store.ListChannelPayoutsForUser(ctx, db.ListChannelPayoutsForUserParams{
+		UserID: userID,
+		Chain:  "ethereum",
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list channel payouts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, meResponse{User: usr, Channels: channels})
+}
+
+type meResponse struct {
+	db.User
+	Channels []db.ListChannelPayoutsForUserRow `json:"channels"`
 }
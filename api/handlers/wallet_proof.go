@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	util "github.com/YoshiTheExplorer/TipMNEE/util"
+	"github.com/gin-gonic/gin"
+)
+
+// httpError carries the status a handler should answer with when a shared
+// helper fails part way through a request.
+type httpError struct {
+	status int
+	msg    string
+}
+
+func (e *httpError) Error() string { return e.msg }
+
+func newHTTPError(status int, msg string) *httpError {
+	return &httpError{status: status, msg: msg}
+}
+
+func respondError(c *gin.Context, err error) {
+	if he, ok := err.(*httpError); ok {
+		c.JSON(he.status, gin.H{"error": he.msg})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+func normalizeAddress(addr string) string {
+	return strings.ToLower(strings.TrimSpace(addr))
+}
+
+// issueWalletNonce stores a one-time message for addr. build receives the
+// fresh nonce and expiry and returns the exact text the wallet must sign.
+func issueWalletNonce(
+	ctx context.Context,
+	store *db.Queries,
+	addr string,
+	ttl time.Duration,
+	build func(nonce string, expires time.Time) string,
+) (string, error) {
+	nonce, err := generateNonce()
+	if err != nil {
+		return "", newHTTPError(http.StatusInternalServerError, "failed to generate nonce")
+	}
+
+	expires := time.Now().UTC().Add(ttl).Truncate(time.Second)
+	message := build(nonce, expires)
+
+	if err := store.UpsertLoginNonce(ctx, db.UpsertLoginNonceParams{
+		Address:   addr,
+		Nonce:     nonce,
+		ExpiresAt: expires,
+		Message:   message,
+	}); err != nil {
+		return "", newHTTPError(http.StatusInternalServerError, "failed to store nonce")
+	}
+	return message, nil
+}
+
+// takeWalletNonce returns the message stored for addr and deletes the nonce
+// so it cannot be replayed. When build is set, the stored message must equal
+// build(nonce, expires); this stops a signature issued for one action from
+// being reused for another. The caller verifies the signature.
+func takeWalletNonce(
+	ctx context.Context,
+	store *db.Queries,
+	addr string,
+	messagePath string,
+	build func(nonce string, expires time.Time) string,
+) (db.GetLoginNonceRow, error) {
+	// 1) Must have a nonce issued for this address
+	ln, err := store.GetLoginNonce(ctx, addr)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.GetLoginNonceRow{}, newHTTPError(http.StatusUnauthorized, "missing nonce: call "+messagePath+" first")
+		}
+		return db.GetLoginNonceRow{}, newHTTPError(http.StatusInternalServerError, "failed to read nonce")
+	}
+
+	// 2) One-time use nonce (prevents replay), whatever happens next
+	_ = store.DeleteLoginNonce(ctx, addr)
+
+	// 3) Check expiry (UTC + seconds)
+	now := time.Now().UTC().Truncate(time.Second)
+	if now.After(ln.ExpiresAt.UTC().Truncate(time.Second)) {
+		return db.GetLoginNonceRow{}, newHTTPError(http.StatusUnauthorized, "nonce expired: call "+messagePath+" again")
+	}
+
+	// 4) The stored message must belong to this action
+	if build != nil && ln.Message != build(ln.Nonce, ln.ExpiresAt) {
+		return db.GetLoginNonceRow{}, newHTTPError(http.StatusUnauthorized, "nonce was issued for a different action: call "+messagePath+" again")
+	}
+	return ln, nil
+}
+
+// consumeWalletNonce checks a personal_sign signature against the message
+// stored for addr (see takeWalletNonce).
+func consumeWalletNonce(
+	ctx context.Context,
+	store *db.Queries,
+	addr string,
+	signature string,
+	messagePath string,
+	build func(nonce string, expires time.Time) string,
+) error {
+	ln, err := takeWalletNonce(ctx, store, addr, messagePath, build)
+	if err != nil {
+		return err
+	}
+
+	// Verify signature against the exact stored message
+	recovered, err := util.RecoverAddressFromPersonalSign(ln.Message, signature)
+	if err != nil {
+		return newHTTPError(http.StatusUnauthorized, "invalid signature")
+	}
+	if normalizeAddress(recovered) != normalizeAddress(ln.Address) {
+		return newHTTPError(http.StatusUnauthorized, "signature does not match address")
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/YoshiTheExplorer/TipMNEE/platform"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+YouTubeMetadata resolves @handles and custom URLs to channel ids and keeps
+channel metadata (title, avatar, subscriber count) in channel_profiles for
+CHANNEL_PROFILE_TTL, so the extension's lookups don't each cost API quota.
+Video -> channel lookups are cached in video_channels for good. Handles and
+videos the API doesn't know are remembered in memory for CHANNEL_NOT_FOUND_TTL.
+The API itself is reached through the youtube provider (YOUTUBE_API_BASE_URL).
+*/
+type YouTubeMetadata struct {
+	store  *db.Queries
+	yt     *platform.YouTube
+	ttl    time.Duration
+	misses *missCache
+}
+
+func NewYouTubeMetadata(store *db.Queries, platforms *platform.Registry) (*YouTubeMetadata, error) {
+	ttl, err := durationEnv("CHANNEL_PROFILE_TTL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	missTTL, err := durationEnv("CHANNEL_NOT_FOUND_TTL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	m := &YouTubeMetadata{store: store, ttl: ttl, misses: newMissCache(missTTL)}
+	if p, ok := platforms.Get("youtube"); ok {
+		m.yt, _ = p.(*platform.YouTube)
+	}
+	return m, nil
+}
+
+func (m *YouTubeMetadata) fetchedAfter() time.Time {
+	return time.Now().Add(-m.ttl)
+}
+
+// ChannelID turns a channel id, @handle or channel URL into the channel id.
+func (m *YouTubeMetadata) ChannelID(ctx context.Context, raw string) (string, error) {
+	if m.yt == nil {
+		return "", platform.ErrProfileUnavailable
+	}
+	id, handle, err := m.yt.ParseChannelRef(raw)
+	if err != nil || id != "" {
+		return id, err
+	}
+
+	cached, err := m.store.GetFreshChannelProfileByHandle(ctx, db.GetFreshChannelProfileByHandleParams{
+		Platform:     "youtube",
+		Handle:       handle,
+		FetchedAfter: m.fetchedAfter(),
+	})
+	if err == nil {
+		return cached.PlatformUserID, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", err
+	}
+	if m.misses.has("handle:" + handle) {
+		return "", platform.ErrNotFound
+	}
+
+	prof, err := m.yt.ProfileByHandle(ctx, handle)
+	if errors.Is(err, platform.ErrNotFound) {
+		m.misses.add("handle:" + handle)
+	}
+	if err != nil {
+		return "", err
+	}
+	m.save(ctx, prof)
+	return prof.PlatformUserID, nil
+}
+
+// Profile returns cached metadata for a channel id, refreshing it once stale.
+func (m *YouTubeMetadata) Profile(ctx context.Context, id string) (*platform.Profile, error) {
+	if m.yt == nil {
+		return nil, platform.ErrProfileUnavailable
+	}
+
+	cached, err := m.store.GetFreshChannelProfile(ctx, db.GetFreshChannelProfileParams{
+		Platform:       "youtube",
+		PlatformUserID: id,
+		FetchedAfter:   m.fetchedAfter(),
+	})
+	if err == nil {
+		return cachedProfile(cached), nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	prof, err := m.yt.Profile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	m.save(ctx, prof)
+	return prof, nil
+}
+
+// VideoChannelID returns the channel that uploaded videoID.
+func (m *YouTubeMetadata) VideoChannelID(ctx context.Context, videoID string) (string, error) {
+	if m.yt == nil {
+		return "", platform.ErrProfileUnavailable
+	}
+
+	vc, err := m.store.GetVideoChannel(ctx, db.GetVideoChannelParams{Platform: "youtube", VideoID: videoID})
+	if err == nil {
+		return vc.PlatformUserID, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", err
+	}
+	if m.misses.has("video:" + videoID) {
+		return "", platform.ErrNotFound
+	}
+
+	channelID, err := m.yt.VideoChannelID(ctx, videoID)
+	if errors.Is(err, platform.ErrNotFound) {
+		m.misses.add("video:" + videoID)
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := m.store.UpsertVideoChannel(ctx, db.UpsertVideoChannelParams{
+		Platform:       "youtube",
+		VideoID:        videoID,
+		PlatformUserID: channelID,
+	}); err != nil {
+		log.Printf("cache video channel %s: %v", videoID, err)
+	}
+	return channelID, nil
+}
+
+// missCacheMax bounds missCache; past it expired entries are swept and, if
+// that isn't enough, everything is forgotten.
+const missCacheMax = 10000
+
+// missCache remembers keys the API answered "not found" for until ttl passes.
+// A ttl of 0 disables it.
+type missCache struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+func newMissCache(ttl time.Duration) *missCache {
+	return &missCache{ttl: ttl, until: map[string]time.Time{}}
+}
+
+func (mc *missCache) has(key string) bool {
+	if mc.ttl == 0 {
+		return false
+	}
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return time.Now().Before(mc.until[key])
+}
+
+func (mc *missCache) add(key string) {
+	if mc.ttl == 0 {
+		return
+	}
+	now := time.Now()
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if len(mc.until) >= missCacheMax {
+		for k, t := range mc.until {
+			if !now.Before(t) {
+				delete(mc.until, k)
+			}
+		}
+		if len(mc.until) >= missCacheMax {
+			mc.until = map[string]time.Time{}
+		}
+	}
+	mc.until[key] = now.Add(mc.ttl)
+}
+
+// save is best effort; a failed write only costs another API call later.
+func (m *YouTubeMetadata) save(ctx context.Context, prof *platform.Profile) {
+	arg := db.UpsertChannelProfileParams{
+		Platform:       prof.Platform,
+		PlatformUserID: prof.PlatformUserID,
+		Handle:         sql.NullString{String: prof.Handle, Valid: prof.Handle != ""},
+		Title:          prof.DisplayName,
+		AvatarURL:      prof.AvatarURL,
+	}
+	if prof.SubscriberCount != nil {
+		arg.SubscriberCount = sql.NullInt64{Int64: *prof.SubscriberCount, Valid: true}
+	}
+	if _, err := m.store.UpsertChannelProfile(ctx, arg); err != nil {
+		log.Printf("cache channel profile %s/%s: %v", prof.Platform, prof.PlatformUserID, err)
+	}
+}
+
+func cachedProfile(cp db.ChannelProfile) *platform.Profile {
+	p := &platform.Profile{
+		Platform:       cp.Platform,
+		PlatformUserID: cp.PlatformUserID,
+		DisplayName:    cp.Title,
+		AvatarURL:      cp.AvatarURL,
+		URL:            "https://www.youtube.com/channel/" + cp.PlatformUserID,
+		Handle:         cp.Handle.String,
+	}
+	if cp.SubscriberCount.Valid {
+		n := cp.SubscriberCount.Int64
+		p.SubscriberCount = &n
+	}
+	return p
+}
+
+// youtubeChannelParam is normalizeID for routes that also take a handle or
+// channel URL.
+func youtubeChannelParam(c *gin.Context, m *YouTubeMetadata, raw string) (string, bool) {
+	id, err := m.ChannelID(c.Request.Context(), raw)
+	if err == nil {
+		return id, true
+	}
+	respondProfileError(c, err)
+	return "", false
+}
+
+func respondProfileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, platform.ErrInvalidID):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid youtube channel id, handle or url"})
+	case errors.Is(err, platform.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, platform.ErrProfileUnavailable):
+		c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	}
+}
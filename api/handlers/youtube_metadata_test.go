@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	db "github.com/YoshiTheExplorer/TipMNEE/db/sqlc"
+	"github.com/YoshiTheExplorer/TipMNEE/platform"
+)
+
+// emptyDriver is a database/sql driver whose queries return no rows and whose
+// writes succeed, so every cache lookup misses and the API is asked.
+type emptyDriver struct{}
+
+func (emptyDriver) Open(string) (driver.Conn, error) { return emptyConn{}, nil }
+
+type emptyConn struct{}
+
+func (emptyConn) Prepare(string) (driver.Stmt, error) { return emptyStmt{}, nil }
+func (emptyConn) Close() error                        { return nil }
+func (emptyConn) Begin() (driver.Tx, error)           { return nil, errors.New("no transactions") }
+
+type emptyStmt struct{}
+
+func (emptyStmt) Close() error  { return nil }
+func (emptyStmt) NumInput() int { return -1 }
+func (emptyStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+func (emptyStmt) Query([]driver.Value) (driver.Rows, error) { return emptyRows{}, nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return nil }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+var registerEmptyDriver sync.Once
+
+func emptyDB(t *testing.T) *sql.DB {
+	t.Helper()
+	registerEmptyDriver.Do(func() { sql.Register("empty", emptyDriver{}) })
+	conn, err := sql.Open("empty", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func emptyQueries(t *testing.T) *db.Queries {
+	return db.New(emptyDB(t))
+}
+
+// fakeYouTube serves /channels and /videos from fixed maps and counts the
+// requests it gets.
+type fakeYouTube struct {
+	mu       sync.Mutex
+	requests int
+	handles  map[string]string // "@handle" -> channel id
+	videos   map[string]string // video id -> channel id
+}
+
+func (f *fakeYouTube) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests++
+	f.mu.Unlock()
+
+	q := r.URL.Query()
+	if q.Get("key") == "" {
+		http.Error(w, "missing key", http.StatusForbidden)
+		return
+	}
+	var id string
+	switch r.URL.Path {
+	case "/channels":
+		if q.Get("forHandle") != "" {
+			id = f.handles[q.Get("forHandle")]
+		}
+		if q.Get("id") != "" {
+			id = q.Get("id")
+		}
+		if id == "" {
+			fmt.Fprint(w, `{"items":[]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"items":[{"id":%q,"snippet":{"title":"Test"}}]}`, id)
+	case "/videos":
+		id = f.videos[q.Get("id")]
+		if id == "" {
+			fmt.Fprint(w, `{"items":[]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"items":[{"snippet":{"channelId":%q}}]}`, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeYouTube) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+func newTestYouTubeMetadata(t *testing.T, fake *fakeYouTube) *YouTubeMetadata {
+	t.Helper()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	t.Setenv("YOUTUBE_API_BASE_URL", srv.URL)
+	t.Setenv("YOUTUBE_API_KEY", "test-key")
+
+	m, err := NewYouTubeMetadata(emptyQueries(t), platform.NewRegistryFromEnv())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+const testChannelID = "UC0123456789abcdefghijkl"
+
+func TestChannelIDRejectsMalformedChannelID(t *testing.T) {
+	fake := &fakeYouTube{}
+	m := newTestYouTubeMetadata(t, fake)
+
+	for _, raw := range []string{"UC123", "UC0123456789abcdefghijklm", "youtube.com/channel/UCbad"} {
+		if _, err := m.ChannelID(context.Background(), raw); !errors.Is(err, platform.ErrInvalidID) {
+			t.Errorf("ChannelID(%q) error = %v, want ErrInvalidID", raw, err)
+		}
+	}
+	if n := fake.count(); n != 0 {
+		t.Errorf("API requests = %d, want 0", n)
+	}
+}
+
+func TestChannelIDResolvesHandle(t *testing.T) {
+	fake := &fakeYouTube{handles: map[string]string{"@creator": testChannelID}}
+	m := newTestYouTubeMetadata(t, fake)
+
+	for _, raw := range []string{"@Creator", "https://www.youtube.com/@creator", testChannelID} {
+		id, err := m.ChannelID(context.Background(), raw)
+		if err != nil {
+			t.Fatalf("ChannelID(%q): %v", raw, err)
+		}
+		if id != testChannelID {
+			t.Errorf("ChannelID(%q) = %q, want %q", raw, id, testChannelID)
+		}
+	}
+}
+
+func TestChannelIDCachesUnknownHandle(t *testing.T) {
+	fake := &fakeYouTube{}
+	m := newTestYouTubeMetadata(t, fake)
+
+	if _, err := m.ChannelID(context.Background(), "@nobody"); !errors.Is(err, platform.ErrNotFound) {
+		t.Fatalf("first lookup error = %v, want ErrNotFound", err)
+	}
+	asked := fake.count()
+	if asked == 0 {
+		t.Fatal("first lookup did not reach the API")
+	}
+	if _, err := m.ChannelID(context.Background(), "@nobody"); !errors.Is(err, platform.ErrNotFound) {
+		t.Fatalf("second lookup error = %v, want ErrNotFound", err)
+	}
+	if n := fake.count(); n != asked {
+		t.Errorf("API requests after cached miss = %d, want %d", n, asked)
+	}
+}
+
+func TestVideoChannelIDCachesUnknownVideo(t *testing.T) {
+	fake := &fakeYouTube{videos: map[string]string{"dQw4w9WgXcQ": testChannelID}}
+	m := newTestYouTubeMetadata(t, fake)
+
+	id, err := m.VideoChannelID(context.Background(), "dQw4w9WgXcQ")
+	if err != nil || id != testChannelID {
+		t.Fatalf("VideoChannelID = %q, %v; want %q", id, err, testChannelID)
+	}
+
+	if _, err := m.VideoChannelID(context.Background(), "aaaaaaaaaaa"); !errors.Is(err, platform.ErrNotFound) {
+		t.Fatalf("first lookup error = %v, want ErrNotFound", err)
+	}
+	asked := fake.count()
+	if _, err := m.VideoChannelID(context.Background(), "aaaaaaaaaaa"); !errors.Is(err, platform.ErrNotFound) {
+		t.Fatalf("second lookup error = %v, want ErrNotFound", err)
+	}
+	if n := fake.count(); n != asked {
+		t.Errorf("API requests after cached miss = %d, want %d", n, asked)
+	}
+}
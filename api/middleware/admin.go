@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin lets through only the listed user ids. It must run after
+// AuthMiddleware.
+func RequireAdmin(adminIDs []int64) gin.HandlerFunc {
+	admins := make(map[int64]bool, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[id] = true
+	}
+
+	return func(c *gin.Context) {
+		if !admins[MustUserID(c)] {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin only"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -8,8 +8,8 @@ func CORS() gin.HandlerFunc {
 		// (No cookies used, so "*" is fine.)
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		c.Header("Access-Control-Expose-Headers", "Content-Type")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, If-None-Match, X-Step-Up-Id, X-Step-Up-Signature")
+		c.Header("Access-Control-Expose-Headers", "Content-Type, ETag")
 
 		// Handle preflight
 		if c.Request.Method == "OPTIONS" {
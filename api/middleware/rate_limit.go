@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit allows at most limit requests per caller in each fixed window.
+// Authenticated callers are counted by user id; anyone else by client IP,
+// which is only as good as the server's trusted-proxy list. Counts are kept in
+// memory, so every API instance limits on its own. A limit of 0 disables it.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	if limit <= 0 || window <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	type bucket struct {
+		start time.Time
+		count int
+	}
+	var (
+		mu        sync.Mutex
+		buckets   = map[string]*bucket{}
+		lastSweep = time.Now()
+	)
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		key := "ip:" + c.ClientIP()
+		if userID := MustUserID(c); userID != 0 {
+			key = "user:" + strconv.FormatInt(userID, 10)
+		}
+
+		mu.Lock()
+		if now.Sub(lastSweep) > window {
+			for k, b := range buckets {
+				if now.Sub(b.start) >= window {
+					delete(buckets, k)
+				}
+			}
+			lastSweep = now
+		}
+		b := buckets[key]
+		if b == nil || now.Sub(b.start) >= window {
+			b = &bucket{start: now}
+			buckets[key] = b
+		}
+		b.count++
+		over, retry := b.count > limit, b.start.Add(window).Sub(now)
+		mu.Unlock()
+
+		if over {
+			c.Header("Retry-After", strconv.Itoa(int(retry.Seconds())+1))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests; try again later"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,22 @@
+CREATE TABLE user_merges (
+  id                  bigserial PRIMARY KEY,
+  surviving_user_id   bigint      NOT NULL,
+  merged_user_id      bigint      NOT NULL,
+  proof_address       varchar     NOT NULL,
+  identities_moved    bigint      NOT NULL,
+  social_links_moved  bigint      NOT NULL,
+  payouts_moved       bigint      NOT NULL,
+  ledger_events_moved bigint      NOT NULL,
+  discarded_payouts   jsonb       NOT NULL DEFAULT '[]',
+  created_at          timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON user_merges (surviving_user_id);
+
+CREATE INDEX ON user_merges (merged_user_id);
+
+COMMENT ON COLUMN user_merges.merged_user_id IS 'users.id that was folded in and deleted';
+
+COMMENT ON COLUMN user_merges.proof_address IS 'wallet of the merged user that signed the merge message';
+
+COMMENT ON COLUMN user_merges.discarded_payouts IS 'payout rows dropped while resolving (user_id, chain) conflicts';
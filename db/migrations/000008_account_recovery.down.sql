@@ -0,0 +1,7 @@
+ALTER TABLE identities
+DROP COLUMN IF EXISTS email;
+
+COMMENT ON COLUMN user_merges.proof_address IS 'wallet of the merged user that signed the merge message';
+
+DROP TABLE IF EXISTS account_recoveries;
+DROP TABLE IF EXISTS notifications;
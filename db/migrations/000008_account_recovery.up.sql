@@ -0,0 +1,44 @@
+CREATE TABLE notifications (
+  id         bigserial PRIMARY KEY,
+  user_id    bigint      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  kind       varchar     NOT NULL,
+  body       text        NOT NULL,
+  read_at    timestamptz,
+  created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON notifications (user_id, created_at);
+
+COMMENT ON COLUMN notifications.kind IS 'machine-readable type, e.g. ''account_recovery''';
+
+CREATE TABLE account_recoveries (
+  id               bigserial PRIMARY KEY,
+  new_user_id      bigint      NOT NULL,
+  target_user_id   bigint      NOT NULL,
+  platform         varchar     NOT NULL,
+  platform_user_id varchar     NOT NULL,
+  status           varchar     NOT NULL DEFAULT 'pending',
+  eligible_at      timestamptz NOT NULL,
+  completed_at     timestamptz,
+  cancelled_at     timestamptz,
+  created_at       timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX ON account_recoveries (platform, platform_user_id) WHERE status = 'pending';
+
+CREATE INDEX ON account_recoveries (new_user_id);
+
+CREATE INDEX ON account_recoveries (target_user_id);
+
+COMMENT ON COLUMN account_recoveries.new_user_id IS 'user created by the replacement wallet; folded into target_user_id on completion';
+
+COMMENT ON COLUMN account_recoveries.status IS '''pending'' | ''completed'' | ''cancelled''';
+
+COMMENT ON COLUMN account_recoveries.eligible_at IS 'earliest time the recovery can be completed';
+
+COMMENT ON COLUMN user_merges.proof_address IS 'wallet that signed the merge message, or recovery:<platform>:<id> for account recovery';
+
+ALTER TABLE identities
+ADD COLUMN IF NOT EXISTS email varchar;
+
+COMMENT ON COLUMN identities.email IS 'google: verified email at the last sign-in or link, used for out-of-band security notices; NULL otherwise';
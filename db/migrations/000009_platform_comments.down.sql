@@ -0,0 +1,7 @@
+COMMENT ON COLUMN social_links.platform IS '''youtube''';
+
+COMMENT ON COLUMN social_links.platform_user_id IS 'YouTube channelId';
+
+COMMENT ON COLUMN ledger_events.platform IS '''youtube''';
+
+COMMENT ON COLUMN ledger_events.platform_user_id IS 'channelId';
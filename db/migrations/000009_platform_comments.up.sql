@@ -0,0 +1,7 @@
+COMMENT ON COLUMN social_links.platform IS '''youtube'' | ''twitch'' | ''x'' | ''github'' | ''kick''';
+
+COMMENT ON COLUMN social_links.platform_user_id IS 'platform-normalized id: YouTube channelId, otherwise lowercased login/handle/slug';
+
+COMMENT ON COLUMN ledger_events.platform IS '''youtube'' | ''twitch'' | ''x'' | ''github'' | ''kick''';
+
+COMMENT ON COLUMN ledger_events.platform_user_id IS 'platform-normalized id (see social_links.platform_user_id)';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS verification_codes;
+
+ALTER TABLE social_links
+DROP COLUMN IF EXISTS verification_method;
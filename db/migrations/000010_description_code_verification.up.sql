@@ -0,0 +1,23 @@
+ALTER TABLE social_links
+ADD COLUMN IF NOT EXISTS verification_method varchar;
+
+UPDATE social_links
+SET verification_method = 'oauth'
+WHERE verified_at IS NOT NULL AND verification_method IS NULL;
+
+COMMENT ON COLUMN social_links.verification_method IS '''oauth'' | ''description_code''; NULL while unverified';
+
+CREATE TABLE verification_codes (
+  id               bigserial PRIMARY KEY,
+  user_id          bigint      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  platform         varchar     NOT NULL,
+  platform_user_id varchar     NOT NULL,
+  code             varchar     NOT NULL,
+  expires_at       timestamptz NOT NULL,
+  used_at          timestamptz,
+  created_at       timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX ON verification_codes (user_id, platform, platform_user_id);
+
+COMMENT ON COLUMN verification_codes.code IS 'one-time code the creator puts in their channel description';
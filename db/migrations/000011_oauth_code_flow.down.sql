@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS oauth_credentials;
+DROP TABLE IF EXISTS oauth_states;
@@ -0,0 +1,29 @@
+CREATE TABLE oauth_states (
+  state            varchar PRIMARY KEY,
+  user_id          bigint      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  platform         varchar     NOT NULL,
+  platform_user_id varchar     NOT NULL,
+  code_verifier    varchar     NOT NULL,
+  purpose          varchar     NOT NULL DEFAULT 'verify',
+  expires_at       timestamptz NOT NULL,
+  created_at       timestamptz NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE oauth_states IS 'pending authorization-code flows; a row is consumed by the callback';
+COMMENT ON COLUMN oauth_states.code_verifier IS 'PKCE verifier; only its S256 challenge leaves the server';
+COMMENT ON COLUMN oauth_states.purpose IS '''verify'' (link the channel) | ''recovery'' (start an account recovery)';
+
+CREATE TABLE oauth_credentials (
+  id                bigserial PRIMARY KEY,
+  user_id           bigint      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  platform          varchar     NOT NULL,
+  platform_user_id  varchar     NOT NULL,
+  refresh_token_enc bytea       NOT NULL,
+  scope             varchar     NOT NULL DEFAULT '',
+  created_at        timestamptz NOT NULL DEFAULT NOW(),
+  updated_at        timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX ON oauth_credentials (platform, platform_user_id);
+
+COMMENT ON COLUMN oauth_credentials.refresh_token_enc IS 'AES-256-GCM (nonce || ciphertext) under OAUTH_TOKEN_ENCRYPTION_KEY';
@@ -0,0 +1,4 @@
+ALTER TABLE social_links
+DROP COLUMN IF EXISTS stale_at,
+DROP COLUMN IF EXISTS check_failed_at,
+DROP COLUMN IF EXISTS last_checked_at;
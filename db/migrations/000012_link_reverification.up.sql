@@ -0,0 +1,14 @@
+ALTER TABLE social_links
+ADD COLUMN IF NOT EXISTS last_checked_at timestamptz,
+ADD COLUMN IF NOT EXISTS check_failed_at timestamptz,
+ADD COLUMN IF NOT EXISTS stale_at timestamptz;
+
+UPDATE social_links
+SET last_checked_at = verified_at
+WHERE verified_at IS NOT NULL AND last_checked_at IS NULL;
+
+CREATE INDEX ON social_links (last_checked_at) WHERE verified_at IS NOT NULL AND stale_at IS NULL;
+
+COMMENT ON COLUMN social_links.last_checked_at IS 'last ownership (re)check attempt, successful or not';
+COMMENT ON COLUMN social_links.check_failed_at IS 'first failed re-check since the last success; the grace period runs from here';
+COMMENT ON COLUMN social_links.stale_at IS 'verification lapsed; resolve falls back to escrow until re-verified';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS channel_transfer_credentials;
+DROP TABLE IF EXISTS channel_transfers;
@@ -0,0 +1,40 @@
+CREATE TABLE channel_transfers (
+  id               bigserial PRIMARY KEY,
+  platform         varchar     NOT NULL,
+  platform_user_id varchar     NOT NULL,
+  from_user_id     bigint,
+  to_user_id       bigint      NOT NULL,
+  method           varchar     NOT NULL,
+  status           varchar     NOT NULL,
+  eligible_at      timestamptz,
+  disputed_at      timestamptz,
+  dispute_reason   text,
+  resolved_at      timestamptz,
+  resolved_by      bigint,
+  resolution_note  text,
+  created_at       timestamptz NOT NULL DEFAULT NOW()
+);
+
+-- At most one open transfer per channel.
+CREATE UNIQUE INDEX ON channel_transfers (platform, platform_user_id) WHERE status IN ('pending', 'disputed');
+CREATE INDEX ON channel_transfers (from_user_id);
+CREATE INDEX ON channel_transfers (to_user_id);
+
+COMMENT ON TABLE channel_transfers IS 'history of social_links ownership changes; no user FKs so it outlives merged users';
+COMMENT ON COLUMN channel_transfers.from_user_id IS 'previous link owner; NULL when the channel had no link';
+COMMENT ON COLUMN channel_transfers.method IS '''link'' (unverified takeover) | ''oauth'' | ''description_code''';
+COMMENT ON COLUMN channel_transfers.status IS '''pending'' | ''disputed'' | ''completed'' | ''rejected''';
+COMMENT ON COLUMN channel_transfers.eligible_at IS 'end of the cooling-off period for pending transfers';
+COMMENT ON COLUMN channel_transfers.resolved_by IS 'admin users.id when an admin decided the transfer';
+
+-- A refresh token from an OAuth proof that opened a transfer waits here; the
+-- previous owner's stored credential is untouched until the transfer completes.
+CREATE TABLE channel_transfer_credentials (
+  transfer_id       bigint      PRIMARY KEY REFERENCES channel_transfers (id) ON DELETE CASCADE,
+  refresh_token_enc bytea       NOT NULL,
+  scope             varchar     NOT NULL,
+  created_at        timestamptz NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE channel_transfer_credentials IS 'claimant OAuth refresh token held until the transfer completes';
+COMMENT ON COLUMN channel_transfer_credentials.refresh_token_enc IS 'sealed like oauth_credentials.refresh_token_enc';
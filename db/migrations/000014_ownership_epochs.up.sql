@@ -0,0 +1,40 @@
+CREATE TABLE channel_ownership_epochs (
+  id               bigserial PRIMARY KEY,
+  platform         varchar     NOT NULL,
+  platform_user_id varchar     NOT NULL,
+  user_id          bigint      NOT NULL,
+  started_at       timestamptz NOT NULL,
+  ended_at         timestamptz,
+  created_at       timestamptz NOT NULL DEFAULT NOW()
+);
+
+-- At most one open epoch (the current owner) per channel.
+CREATE UNIQUE INDEX ON channel_ownership_epochs (platform, platform_user_id) WHERE ended_at IS NULL;
+CREATE INDEX ON channel_ownership_epochs (platform, platform_user_id, started_at);
+CREATE INDEX ON channel_ownership_epochs (user_id);
+
+COMMENT ON TABLE channel_ownership_epochs IS 'who held a verified link to a channel, and when; source of ledger attribution';
+COMMENT ON COLUMN channel_ownership_epochs.user_id IS 'no FK: epochs move with user merges and outlive deleted users';
+COMMENT ON COLUMN channel_ownership_epochs.ended_at IS 'NULL for the current owner';
+
+-- Current verified owners start their epoch at verification time.
+INSERT INTO channel_ownership_epochs (platform, platform_user_id, user_id, started_at)
+SELECT platform, platform_user_id, user_id, verified_at
+FROM social_links
+WHERE verified_at IS NOT NULL;
+
+-- Re-derive tip and withdrawal attribution from the epochs (see
+-- AttributeLedgerEventsForChannel); withdrawals belong to the channel's owner,
+-- not to whoever recorded them.
+UPDATE ledger_events le
+SET user_id = (
+      SELECT e.user_id
+      FROM channel_ownership_epochs e
+      WHERE e.platform = le.platform
+        AND e.platform_user_id = le.platform_user_id
+        AND (e.ended_at IS NULL OR e.ended_at > le.block_time AT TIME ZONE 'UTC')
+      ORDER BY e.started_at
+      LIMIT 1
+    ),
+    updated_at = NOW()
+WHERE le.event_type IN ('TIP_DIRECT', 'TIP_ESCROW', 'WITHDRAW');
@@ -0,0 +1,18 @@
+CREATE TABLE channel_releases (
+  id                     bigserial PRIMARY KEY,
+  platform               varchar        NOT NULL,
+  platform_user_id       varchar        NOT NULL,
+  user_id                bigint         NOT NULL,
+  was_verified           boolean        NOT NULL,
+  unclaimed_escrow_raw   numeric(78,0)  NOT NULL DEFAULT 0,
+  next_owner_id          bigint,
+  next_owner_notified_at timestamptz,
+  created_at             timestamptz    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON channel_releases (platform, platform_user_id);
+CREATE INDEX ON channel_releases (user_id);
+
+COMMENT ON TABLE channel_releases IS 'social links removed by their owner; ledger history stays in ledger_events';
+COMMENT ON COLUMN channel_releases.user_id IS 'owner who unlinked; no FK so the record outlives merged users';
+COMMENT ON COLUMN channel_releases.unclaimed_escrow_raw IS 'escrow balance for the channel at release time, flagged for the next verified owner';
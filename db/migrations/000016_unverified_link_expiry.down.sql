@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS social_links_unverified_user_id_idx;
+DROP INDEX IF EXISTS social_links_unverified_updated_at_idx;
@@ -0,0 +1,4 @@
+-- Unverified links expire UNVERIFIED_LINK_TTL after they were created or last
+-- taken over (updated_at); these back the expiry sweep and the per-user cap.
+CREATE INDEX social_links_unverified_updated_at_idx ON social_links (updated_at) WHERE verified_at IS NULL;
+CREATE INDEX social_links_unverified_user_id_idx ON social_links (user_id) WHERE verified_at IS NULL;
@@ -0,0 +1,16 @@
+CREATE TABLE channel_profiles (
+  platform         varchar     NOT NULL,
+  platform_user_id varchar     NOT NULL,
+  handle           varchar,
+  title            varchar     NOT NULL DEFAULT '',
+  avatar_url       varchar     NOT NULL DEFAULT '',
+  subscriber_count bigint,
+  fetched_at       timestamptz NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (platform, platform_user_id)
+);
+
+CREATE INDEX ON channel_profiles (platform, handle);
+
+COMMENT ON TABLE channel_profiles IS 'cached public channel metadata; refreshed once older than CHANNEL_PROFILE_TTL';
+COMMENT ON COLUMN channel_profiles.handle IS 'lowercased, without the leading @; handles can move, so the newest row wins';
+COMMENT ON COLUMN channel_profiles.subscriber_count IS 'NULL when the channel hides it';
@@ -0,0 +1,9 @@
+CREATE TABLE video_channels (
+  platform         varchar     NOT NULL,
+  video_id         varchar     NOT NULL,
+  platform_user_id varchar     NOT NULL,
+  fetched_at       timestamptz NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (platform, video_id)
+);
+
+COMMENT ON TABLE video_channels IS 'which channel uploaded a video; a video never changes channel, so rows are kept';
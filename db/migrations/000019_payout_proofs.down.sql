@@ -0,0 +1,3 @@
+ALTER TABLE payouts
+DROP COLUMN IF EXISTS proof_method,
+DROP COLUMN IF EXISTS proven_at;
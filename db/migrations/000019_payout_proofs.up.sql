@@ -0,0 +1,6 @@
+ALTER TABLE payouts
+ADD COLUMN IF NOT EXISTS proven_at timestamptz,
+ADD COLUMN IF NOT EXISTS proof_method varchar;
+
+COMMENT ON COLUMN payouts.proven_at IS 'when the address signed a payout challenge; NULL for addresses registered before proofs were required';
+COMMENT ON COLUMN payouts.proof_method IS '''personal_sign'' (EOA) | ''eip1271'' (contract wallet)';
@@ -0,0 +1,27 @@
+CREATE TABLE payout_history (
+  id           bigserial PRIMARY KEY,
+  user_id      bigint      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  chain        varchar     NOT NULL,
+  old_address  varchar,
+  new_address  varchar     NOT NULL,
+  proof_method varchar,
+  status       varchar     NOT NULL DEFAULT 'pending',
+  effective_at timestamptz NOT NULL,
+  applied_at   timestamptz,
+  cancelled_at timestamptz,
+  created_at   timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX ON payout_history (user_id, chain) WHERE status = 'pending';
+
+CREATE INDEX ON payout_history (user_id, created_at);
+
+CREATE INDEX ON payout_history (effective_at) WHERE status = 'pending';
+
+COMMENT ON COLUMN payout_history.old_address IS 'address in use when the change was requested; NULL for the first registration';
+
+COMMENT ON COLUMN payout_history.proof_method IS 'how new_address proved control (see payouts.proof_method)';
+
+COMMENT ON COLUMN payout_history.status IS '''pending'' | ''applied'' | ''cancelled''';
+
+COMMENT ON COLUMN payout_history.effective_at IS 'when a pending change replaces the payout address; resolve keeps old_address until then';
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS payout_history_pending_idx;
+
+DELETE FROM payout_history WHERE platform IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS payout_history_user_id_chain_idx
+ON payout_history (user_id, chain)
+WHERE status = 'pending';
+
+ALTER TABLE payout_history
+DROP COLUMN IF EXISTS platform_user_id,
+DROP COLUMN IF EXISTS platform;
+
+DROP TABLE IF EXISTS channel_payouts;
@@ -0,0 +1,31 @@
+CREATE TABLE channel_payouts (
+  id               bigserial PRIMARY KEY,
+  user_id          bigint      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  platform         varchar     NOT NULL,
+  platform_user_id varchar     NOT NULL,
+  chain            varchar     NOT NULL,
+  address          varchar     NOT NULL,
+  proven_at        timestamptz,
+  proof_method     varchar,
+  created_at       timestamptz NOT NULL DEFAULT NOW(),
+  updated_at       timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX ON channel_payouts (user_id, platform, platform_user_id, chain);
+
+CREATE INDEX ON channel_payouts (platform, platform_user_id);
+
+COMMENT ON TABLE channel_payouts IS 'per-channel payout overrides; only used while user_id owns the channel, otherwise payouts applies';
+
+ALTER TABLE payout_history
+ADD COLUMN IF NOT EXISTS platform varchar,
+ADD COLUMN IF NOT EXISTS platform_user_id varchar;
+
+COMMENT ON COLUMN payout_history.platform IS 'set (with platform_user_id) for a channel override; NULL for the user default';
+COMMENT ON COLUMN payout_history.new_address IS 'address in use after the change; for a removed override the user default, empty when there is none';
+
+DROP INDEX IF EXISTS payout_history_user_id_chain_idx;
+
+CREATE UNIQUE INDEX payout_history_pending_idx
+ON payout_history (user_id, chain, COALESCE(platform, ''), COALESCE(platform_user_id, ''))
+WHERE status = 'pending';
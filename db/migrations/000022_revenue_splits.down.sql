@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS claim_records;
+DROP TABLE IF EXISTS ledger_allocations;
+DROP TABLE IF EXISTS channel_splits;
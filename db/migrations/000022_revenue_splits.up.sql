@@ -0,0 +1,65 @@
+CREATE TABLE channel_splits (
+  id               bigserial PRIMARY KEY,
+  user_id          bigint      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  platform         varchar     NOT NULL,
+  platform_user_id varchar     NOT NULL,
+  recipients       jsonb       NOT NULL,
+  status           varchar     NOT NULL DEFAULT 'pending',
+  effective_at     timestamptz NOT NULL,
+  activated_at     timestamptz,
+  ended_at         timestamptz,
+  created_at       timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX ON channel_splits (platform, platform_user_id) WHERE status = 'active';
+
+CREATE UNIQUE INDEX ON channel_splits (platform, platform_user_id) WHERE status = 'pending';
+
+CREATE INDEX ON channel_splits (user_id);
+
+CREATE INDEX ON channel_splits (effective_at) WHERE status = 'pending';
+
+COMMENT ON COLUMN channel_splits.recipients IS '[{"address": "0x...", "bps": 2500, "label": "editor"}, ...]; bps sum to 10000';
+
+COMMENT ON COLUMN channel_splits.status IS '''pending'' | ''active'' | ''replaced'' | ''removed'' | ''cancelled''';
+
+COMMENT ON COLUMN channel_splits.effective_at IS 'when a pending split becomes active (PAYOUT_CHANGE_COOLDOWN)';
+
+COMMENT ON COLUMN channel_splits.ended_at IS 'when the split was replaced, removed or cancelled';
+
+CREATE TABLE ledger_allocations (
+  ledger_event_id bigint        NOT NULL REFERENCES ledger_events (id) ON DELETE CASCADE,
+  address         varchar       NOT NULL,
+  split_id        bigint        NOT NULL REFERENCES channel_splits (id),
+  bps             int           NOT NULL,
+  amount_raw      numeric(78,0) NOT NULL,
+  PRIMARY KEY (ledger_event_id, address)
+);
+
+COMMENT ON TABLE ledger_allocations IS 'how a tip divides under the split active at its block time; tips without rows belong wholly to the owner';
+
+CREATE TABLE claim_records (
+  id                 bigserial PRIMARY KEY,
+  user_id            bigint        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  platform           varchar       NOT NULL,
+  platform_user_id   varchar       NOT NULL,
+  payout_address     varchar       NOT NULL,
+  escrow_balance_raw numeric(78,0) NOT NULL,
+  allocations        jsonb         NOT NULL,
+  created_at         timestamptz   NOT NULL DEFAULT NOW(),
+  ledger_event_id    bigint        NOT NULL REFERENCES ledger_events (id) ON DELETE CASCADE
+);
+
+CREATE INDEX ON claim_records (user_id, created_at);
+
+CREATE INDEX ON claim_records (platform, platform_user_id);
+
+-- Claims are recorded when the escrow pays out (the Withdrawn event), not when
+-- they are signed.
+CREATE UNIQUE INDEX ON claim_records (ledger_event_id);
+
+COMMENT ON COLUMN claim_records.escrow_balance_raw IS 'amount the escrow paid out';
+
+COMMENT ON COLUMN claim_records.allocations IS '[{"address": "0x...", "amount_raw": "..."}]: what each collaborator is owed from the claimed balance';
+
+COMMENT ON COLUMN claim_records.ledger_event_id IS 'the WITHDRAW ledger event of the executed claim';
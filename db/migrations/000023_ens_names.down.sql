@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS ens_reverse_names;
+DROP TABLE IF EXISTS ens_names;
+
+ALTER TABLE payout_history
+DROP COLUMN IF EXISTS new_ens_name;
+
+ALTER TABLE channel_payouts
+DROP COLUMN IF EXISTS ens_name;
+
+ALTER TABLE payouts
+DROP COLUMN IF EXISTS ens_name;
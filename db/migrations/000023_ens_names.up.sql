@@ -0,0 +1,30 @@
+ALTER TABLE payouts
+ADD COLUMN IF NOT EXISTS ens_name varchar;
+
+ALTER TABLE channel_payouts
+ADD COLUMN IF NOT EXISTS ens_name varchar;
+
+ALTER TABLE payout_history
+ADD COLUMN IF NOT EXISTS new_ens_name varchar;
+
+COMMENT ON COLUMN payouts.ens_name IS 'ENS name the creator entered (e.g. alice.eth); address is what it resolved to then';
+COMMENT ON COLUMN channel_payouts.ens_name IS 'ENS name the creator entered (e.g. alice.eth); address is what it resolved to then';
+COMMENT ON COLUMN payout_history.new_ens_name IS 'ENS name new_address was resolved from, if any';
+
+CREATE TABLE ens_names (
+  name       varchar     PRIMARY KEY,
+  address    varchar,
+  checked_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE ens_names IS 'last seen forward record of ENS names used as payouts; a change alerts the creator';
+COMMENT ON COLUMN ens_names.address IS 'lowercased 0x...; NULL when the name stopped resolving';
+
+CREATE TABLE ens_reverse_names (
+  address    varchar     PRIMARY KEY,
+  name       varchar,
+  checked_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE ens_reverse_names IS 'cached ENS primary names of payout addresses; refreshed once older than ENS_CHECK_INTERVAL';
+COMMENT ON COLUMN ens_reverse_names.name IS 'NULL when the address has no primary name, or it does not resolve back to the address';
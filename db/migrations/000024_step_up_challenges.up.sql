@@ -0,0 +1,18 @@
+CREATE TABLE step_up_challenges (
+  id         bigserial PRIMARY KEY,
+  user_id    bigint      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  address    varchar     NOT NULL,
+  action     varchar     NOT NULL,
+  nonce      varchar     NOT NULL,
+  message    text        NOT NULL,
+  expires_at timestamptz NOT NULL,
+  used_at    timestamptz,
+  created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON step_up_challenges (expires_at);
+
+COMMENT ON TABLE step_up_challenges IS 'one-time wallet re-authentication for sensitive actions; valid until expires_at (STEP_UP_TTL)';
+COMMENT ON COLUMN step_up_challenges.address IS 'wallet identity of user_id that must sign message (lowercased)';
+COMMENT ON COLUMN step_up_challenges.action IS '''payout_change'' | ''claim'' | ''transfer_complete''';
+COMMENT ON COLUMN step_up_challenges.message IS 'exact text to personal_sign; binds the action parameters';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS claim_relay_txs;
+DROP TABLE IF EXISTS claim_relays;
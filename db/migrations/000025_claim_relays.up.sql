@@ -0,0 +1,56 @@
+CREATE TABLE claim_relays (
+  id               bigserial PRIMARY KEY,
+  user_id          bigint      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  platform         varchar     NOT NULL,
+  platform_user_id varchar     NOT NULL,
+  payout_address   varchar     NOT NULL,
+  expiry           timestamptz NOT NULL,
+  claim_nonce      varchar     NOT NULL UNIQUE,
+  signature        varchar     NOT NULL,
+  relayer_address  varchar     NOT NULL,
+  status           varchar     NOT NULL DEFAULT 'queued',
+  tx_nonce         bigint,
+  gas_limit        bigint,
+  tx_hash          varchar,
+  submitted_at     timestamptz,
+  block_number     bigint,
+  gas_used         bigint,
+  error            text,
+  finished_at      timestamptz,
+  created_at       timestamptz NOT NULL DEFAULT NOW(),
+  updated_at       timestamptz NOT NULL DEFAULT NOW()
+);
+
+-- One claim in flight per channel: a second would revert on an empty escrow.
+CREATE UNIQUE INDEX ON claim_relays (platform, platform_user_id) WHERE status IN ('queued', 'submitted');
+
+CREATE INDEX ON claim_relays (user_id, created_at);
+
+CREATE INDEX ON claim_relays (relayer_address, status);
+
+COMMENT ON TABLE claim_relays IS 'verifier-signed claims submitted to the escrow from the relayer hot wallet, which pays the gas';
+COMMENT ON COLUMN claim_relays.claim_nonce IS 'nonce of the signed claim (bytes32 hex); each signature is relayed once';
+COMMENT ON COLUMN claim_relays.status IS '''queued'' | ''submitted'' | ''confirmed'' | ''failed'' | ''expired''';
+COMMENT ON COLUMN claim_relays.tx_nonce IS 'account nonce of the hot wallet transaction; kept across fee replacements';
+COMMENT ON COLUMN claim_relays.tx_hash IS 'latest broadcast attempt, or the mined one once a receipt is seen';
+COMMENT ON COLUMN claim_relays.submitted_at IS 'when the latest attempt was broadcast (replaced after RELAYER_REPLACE_AFTER)';
+
+CREATE TABLE claim_relay_txs (
+  id          bigserial PRIMARY KEY,
+  relay_id    bigint        NOT NULL REFERENCES claim_relays (id) ON DELETE CASCADE,
+  tx_hash     varchar       NOT NULL UNIQUE,
+  tx_nonce    bigint        NOT NULL,
+  gas_tip_cap numeric(78,0) NOT NULL,
+  gas_fee_cap numeric(78,0) NOT NULL,
+  created_at  timestamptz   NOT NULL DEFAULT NOW(),
+  kind        varchar       NOT NULL DEFAULT 'claim',
+  accepted    boolean       NOT NULL DEFAULT false
+);
+
+CREATE INDEX ON claim_relay_txs (relay_id);
+
+COMMENT ON TABLE claim_relay_txs IS 'every broadcast of a relayed claim; any of them may be the one that gets mined';
+-- A relay whose claim signature expires before it is mined gives its nonce to
+-- a 0-value self-transfer, so later relays are not stuck behind it.
+COMMENT ON COLUMN claim_relay_txs.kind IS '''claim'' | ''cancel'' (0-value self-transfer taking the nonce of an expired claim)';
+COMMENT ON COLUMN claim_relay_txs.accepted IS 'whether the node took the broadcast; replacements bump from the latest accepted attempt';
@@ -0,0 +1,42 @@
+-- name: CreateAccountRecovery :one
+INSERT INTO account_recoveries (
+  new_user_id, target_user_id, platform, platform_user_id, status, eligible_at, created_at
+) VALUES (
+  $1, $2, $3, $4, 'pending', $5, NOW()
+)
+RETURNING id, new_user_id, target_user_id, platform, platform_user_id, status,
+  eligible_at, completed_at, cancelled_at, created_at;
+
+-- name: GetAccountRecoveryForUpdate :one
+SELECT id, new_user_id, target_user_id, platform, platform_user_id, status,
+  eligible_at, completed_at, cancelled_at, created_at
+FROM account_recoveries
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: ListAccountRecoveriesForUser :many
+SELECT id, new_user_id, target_user_id, platform, platform_user_id, status,
+  eligible_at, completed_at, cancelled_at, created_at
+FROM account_recoveries
+WHERE new_user_id = sqlc.arg(user_id) OR target_user_id = sqlc.arg(user_id)
+ORDER BY created_at DESC;
+
+-- name: CompleteAccountRecovery :one
+UPDATE account_recoveries
+SET status = 'completed',
+    completed_at = NOW()
+WHERE id = $1
+  AND status = 'pending'
+RETURNING id, new_user_id, target_user_id, platform, platform_user_id, status,
+  eligible_at, completed_at, cancelled_at, created_at;
+
+-- name: CancelAccountRecovery :one
+UPDATE account_recoveries
+SET status = 'cancelled',
+    cancelled_at = NOW()
+WHERE id = $1
+  AND status = 'pending'
+  AND (new_user_id = sqlc.arg(user_id) OR target_user_id = sqlc.arg(user_id))
+RETURNING id, new_user_id, target_user_id, platform, platform_user_id, status,
+  eligible_at, completed_at, cancelled_at, created_at;
@@ -0,0 +1,69 @@
+-- name: GetChannelPayout :one
+SELECT id, user_id, platform, platform_user_id, chain, address, proven_at, proof_method, created_at, updated_at, ens_name
+FROM channel_payouts
+WHERE user_id = $1
+  AND platform = $2
+  AND platform_user_id = $3
+  AND chain = $4
+LIMIT 1;
+
+-- name: UpsertChannelPayout :one
+INSERT INTO channel_payouts (
+  user_id, platform, platform_user_id, chain, address, proven_at, proof_method, ens_name, created_at, updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW()
+)
+ON CONFLICT (user_id, platform, platform_user_id, chain) DO UPDATE
+SET address = EXCLUDED.address,
+    proven_at = EXCLUDED.proven_at,
+    proof_method = EXCLUDED.proof_method,
+    ens_name = EXCLUDED.ens_name,
+    updated_at = NOW()
+RETURNING id, user_id, platform, platform_user_id, chain, address, proven_at, proof_method, created_at, updated_at, ens_name;
+
+-- name: DeleteChannelPayout :one
+DELETE FROM channel_payouts
+WHERE user_id = $1
+  AND platform = $2
+  AND platform_user_id = $3
+  AND chain = $4
+RETURNING id, user_id, platform, platform_user_id, chain, address, proven_at, proof_method, created_at, updated_at, ens_name;
+
+-- name: DeleteChannelPayoutsForChannel :exec
+DELETE FROM channel_payouts
+WHERE platform = $1
+  AND platform_user_id = $2;
+
+-- name: ListChannelPayoutsForUser :many
+SELECT sl.platform, sl.platform_user_id, sl.verified_at,
+  COALESCE(cp.address, p.address) AS address,
+  (cp.id IS NOT NULL)::boolean AS is_override,
+  CASE WHEN cp.id IS NOT NULL THEN cp.ens_name ELSE p.ens_name END AS ens_name,
+  rn.name AS reverse_name
+FROM social_links sl
+LEFT JOIN channel_payouts cp
+  ON cp.user_id = sl.user_id
+ AND cp.platform = sl.platform
+ AND cp.platform_user_id = sl.platform_user_id
+ AND cp.chain = sqlc.arg(chain)
+LEFT JOIN payouts p
+  ON p.user_id = sl.user_id
+ AND p.chain = sqlc.arg(chain)
+LEFT JOIN ens_reverse_names rn
+  ON rn.address = COALESCE(cp.address, p.address)
+WHERE sl.user_id = sqlc.arg(user_id)
+ORDER BY sl.platform, sl.platform_user_id;
+
+-- name: MoveChannelPayoutsToUser :exec
+UPDATE channel_payouts cp
+SET user_id = sqlc.arg(to_user_id),
+    updated_at = NOW()
+WHERE cp.user_id = sqlc.arg(from_user_id)
+  AND NOT EXISTS (
+    SELECT 1
+    FROM channel_payouts s
+    WHERE s.user_id = sqlc.arg(to_user_id)
+      AND s.platform = cp.platform
+      AND s.platform_user_id = cp.platform_user_id
+      AND s.chain = cp.chain
+  );
@@ -0,0 +1,29 @@
+-- name: UpsertChannelProfile :one
+INSERT INTO channel_profiles (
+  platform, platform_user_id, handle, title, avatar_url, subscriber_count, fetched_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, NOW()
+)
+ON CONFLICT (platform, platform_user_id) DO UPDATE
+SET handle = EXCLUDED.handle,
+    title = EXCLUDED.title,
+    avatar_url = EXCLUDED.avatar_url,
+    subscriber_count = EXCLUDED.subscriber_count,
+    fetched_at = NOW()
+RETURNING platform, platform_user_id, handle, title, avatar_url, subscriber_count, fetched_at;
+
+-- name: GetFreshChannelProfile :one
+SELECT platform, platform_user_id, handle, title, avatar_url, subscriber_count, fetched_at
+FROM channel_profiles
+WHERE platform = sqlc.arg(platform)
+  AND platform_user_id = sqlc.arg(platform_user_id)
+  AND fetched_at >= sqlc.arg(fetched_after);
+
+-- name: GetFreshChannelProfileByHandle :one
+SELECT platform, platform_user_id, handle, title, avatar_url, subscriber_count, fetched_at
+FROM channel_profiles
+WHERE platform = sqlc.arg(platform)
+  AND handle = sqlc.arg(handle)::varchar
+  AND fetched_at >= sqlc.arg(fetched_after)
+ORDER BY fetched_at DESC
+LIMIT 1;
@@ -0,0 +1,19 @@
+-- name: CreateChannelRelease :one
+INSERT INTO channel_releases (
+  platform, platform_user_id, user_id, was_verified, unclaimed_escrow_raw, created_at
+) VALUES (
+  $1, $2, $3, $4, $5, NOW()
+)
+RETURNING id, platform, platform_user_id, user_id, was_verified, unclaimed_escrow_raw,
+  next_owner_id, next_owner_notified_at, created_at;
+
+-- name: FlagChannelReleasesForNextOwner :many
+UPDATE channel_releases
+SET next_owner_id = sqlc.arg(next_owner_id)::bigint,
+    next_owner_notified_at = NOW()
+WHERE platform = sqlc.arg(platform)
+  AND platform_user_id = sqlc.arg(platform_user_id)
+  AND next_owner_notified_at IS NULL
+  AND unclaimed_escrow_raw > 0
+RETURNING id, platform, platform_user_id, user_id, was_verified, unclaimed_escrow_raw,
+  next_owner_id, next_owner_notified_at, created_at;
@@ -0,0 +1,66 @@
+-- name: GetChannelSplit :one
+SELECT id, user_id, platform, platform_user_id, recipients, status, effective_at, activated_at, ended_at, created_at
+FROM channel_splits
+WHERE platform = $1
+  AND platform_user_id = $2
+  AND status = $3
+LIMIT 1;
+
+-- name: ListOpenChannelSplits :many
+SELECT id, user_id, platform, platform_user_id, recipients, status, effective_at, activated_at, ended_at, created_at
+FROM channel_splits
+WHERE platform = $1
+  AND platform_user_id = $2
+  AND status IN ('active', 'pending')
+ORDER BY created_at;
+
+-- name: CreateChannelSplit :one
+INSERT INTO channel_splits (
+  user_id, platform, platform_user_id, recipients, status, effective_at, activated_at, created_at
+) VALUES (
+  sqlc.arg(user_id), sqlc.arg(platform), sqlc.arg(platform_user_id), sqlc.arg(recipients),
+  sqlc.arg(status)::varchar, sqlc.arg(effective_at),
+  CASE WHEN sqlc.arg(status)::varchar = 'active' THEN NOW() END,
+  NOW()
+)
+RETURNING id, user_id, platform, platform_user_id, recipients, status, effective_at, activated_at, ended_at, created_at;
+
+-- name: EndChannelSplits :execrows
+UPDATE channel_splits
+SET status = sqlc.arg(to_status)::varchar,
+    ended_at = NOW()
+WHERE platform = sqlc.arg(platform)
+  AND platform_user_id = sqlc.arg(platform_user_id)
+  AND status = sqlc.arg(from_status)::varchar;
+
+-- name: ListDueChannelSplits :many
+SELECT id, user_id, platform, platform_user_id, recipients, status, effective_at, activated_at, ended_at, created_at
+FROM channel_splits
+WHERE status = 'pending'
+  AND effective_at <= sqlc.arg(due_before)
+ORDER BY effective_at
+LIMIT sqlc.arg(max_splits);
+
+-- name: ActivateChannelSplit :one
+UPDATE channel_splits
+SET status = 'active',
+    activated_at = NOW()
+WHERE id = $1
+  AND status = 'pending'
+  AND effective_at <= NOW()
+RETURNING id, user_id, platform, platform_user_id, recipients, status, effective_at, activated_at, ended_at, created_at;
+
+-- name: GetChannelSplitAt :one
+SELECT id, user_id, platform, platform_user_id, recipients, status, effective_at, activated_at, ended_at, created_at
+FROM channel_splits
+WHERE platform = sqlc.arg(platform)
+  AND platform_user_id = sqlc.arg(platform_user_id)
+  AND activated_at <= sqlc.arg(at)::timestamptz
+  AND (ended_at IS NULL OR ended_at > sqlc.arg(at)::timestamptz)
+ORDER BY activated_at DESC
+LIMIT 1;
+
+-- name: MoveChannelSplitsToUser :exec
+UPDATE channel_splits
+SET user_id = sqlc.arg(to_user_id)
+WHERE user_id = sqlc.arg(from_user_id);
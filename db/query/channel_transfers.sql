@@ -0,0 +1,81 @@
+-- name: CreateChannelTransfer :one
+INSERT INTO channel_transfers (
+  platform, platform_user_id, from_user_id, to_user_id, method, status, eligible_at, resolved_at, created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7,
+  CASE WHEN $6 = 'completed' THEN NOW() END,
+  NOW()
+)
+RETURNING id, platform, platform_user_id, from_user_id, to_user_id, method, status,
+  eligible_at, disputed_at, dispute_reason, resolved_at, resolved_by, resolution_note, created_at;
+
+-- name: GetOpenChannelTransfer :one
+SELECT id, platform, platform_user_id, from_user_id, to_user_id, method, status,
+  eligible_at, disputed_at, dispute_reason, resolved_at, resolved_by, resolution_note, created_at
+FROM channel_transfers
+WHERE platform = $1
+  AND platform_user_id = $2
+  AND status IN ('pending', 'disputed')
+LIMIT 1;
+
+-- name: GetChannelTransferForUpdate :one
+SELECT id, platform, platform_user_id, from_user_id, to_user_id, method, status,
+  eligible_at, disputed_at, dispute_reason, resolved_at, resolved_by, resolution_note, created_at
+FROM channel_transfers
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: ListChannelTransfersForUser :many
+SELECT id, platform, platform_user_id, from_user_id, to_user_id, method, status,
+  eligible_at, disputed_at, dispute_reason, resolved_at, resolved_by, resolution_note, created_at
+FROM channel_transfers
+WHERE from_user_id = sqlc.arg(user_id)::bigint OR to_user_id = sqlc.arg(user_id)::bigint
+ORDER BY created_at DESC;
+
+-- name: ListChannelTransfersByStatus :many
+SELECT id, platform, platform_user_id, from_user_id, to_user_id, method, status,
+  eligible_at, disputed_at, dispute_reason, resolved_at, resolved_by, resolution_note, created_at
+FROM channel_transfers
+WHERE status = $1
+ORDER BY created_at;
+
+-- name: DisputeChannelTransfer :one
+UPDATE channel_transfers
+SET status = 'disputed',
+    disputed_at = NOW(),
+    dispute_reason = sqlc.arg(reason)::text
+WHERE id = sqlc.arg(id)
+  AND status = 'pending'
+  AND from_user_id = sqlc.arg(user_id)::bigint
+RETURNING id, platform, platform_user_id, from_user_id, to_user_id, method, status,
+  eligible_at, disputed_at, dispute_reason, resolved_at, resolved_by, resolution_note, created_at;
+
+-- name: ResolveChannelTransfer :one
+UPDATE channel_transfers
+SET status = $2,
+    resolved_at = NOW(),
+    resolved_by = $3,
+    resolution_note = $4
+WHERE id = $1
+  AND status IN ('pending', 'disputed')
+RETURNING id, platform, platform_user_id, from_user_id, to_user_id, method, status,
+  eligible_at, disputed_at, dispute_reason, resolved_at, resolved_by, resolution_note, created_at;
+
+-- name: MoveChannelTransfersToUser :exec
+UPDATE channel_transfers
+SET from_user_id = CASE WHEN from_user_id = sqlc.arg(from_user_id)::bigint THEN sqlc.arg(to_user_id)::bigint ELSE from_user_id END,
+    to_user_id = CASE WHEN to_user_id = sqlc.arg(from_user_id)::bigint THEN sqlc.arg(to_user_id)::bigint ELSE to_user_id END
+WHERE from_user_id = sqlc.arg(from_user_id)::bigint OR to_user_id = sqlc.arg(from_user_id)::bigint;
+
+-- name: CreateChannelTransferCredential :exec
+INSERT INTO channel_transfer_credentials (
+  transfer_id, refresh_token_enc, scope, created_at
+) VALUES (
+  $1, $2, $3, NOW()
+);
+
+-- name: TakeChannelTransferCredential :one
+DELETE FROM channel_transfer_credentials
+WHERE transfer_id = $1
+RETURNING transfer_id, refresh_token_enc, scope, created_at;
@@ -0,0 +1,18 @@
+-- name: CreateClaimRecord :one
+INSERT INTO claim_records (
+  user_id, platform, platform_user_id, payout_address, escrow_balance_raw, allocations, ledger_event_id, created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, NOW()
+)
+RETURNING id, user_id, platform, platform_user_id, payout_address, escrow_balance_raw, allocations, created_at, ledger_event_id;
+
+-- name: ListClaimRecordsForUser :many
+SELECT id, user_id, platform, platform_user_id, payout_address, escrow_balance_raw, allocations, created_at, ledger_event_id
+FROM claim_records
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: MoveClaimRecordsToUser :exec
+UPDATE claim_records
+SET user_id = sqlc.arg(to_user_id)
+WHERE user_id = sqlc.arg(from_user_id);
@@ -0,0 +1,127 @@
+-- name: CreateClaimRelay :one
+INSERT INTO claim_relays (
+  user_id, platform, platform_user_id, payout_address, expiry, claim_nonce, signature, relayer_address, created_at, updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW()
+)
+RETURNING id, user_id, platform, platform_user_id, payout_address, expiry, claim_nonce, signature, relayer_address, status, tx_nonce, gas_limit, tx_hash, submitted_at, block_number, gas_used, error, finished_at, created_at, updated_at;
+
+-- name: CountClaimRelaysSince :one
+SELECT COUNT(*)::bigint AS relays
+FROM claim_relays
+WHERE user_id = $1
+  AND created_at >= sqlc.arg(since)
+  AND (tx_nonce IS NOT NULL OR status IN ('queued', 'submitted', 'confirmed'));
+
+-- name: GetClaimRelayForUser :one
+SELECT id, user_id, platform, platform_user_id, payout_address, expiry, claim_nonce, signature, relayer_address, status, tx_nonce, gas_limit, tx_hash, submitted_at, block_number, gas_used, error, finished_at, created_at, updated_at
+FROM claim_relays
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: ListClaimRelaysForUser :many
+SELECT id, user_id, platform, platform_user_id, payout_address, expiry, claim_nonce, signature, relayer_address, status, tx_nonce, gas_limit, tx_hash, submitted_at, block_number, gas_used, error, finished_at, created_at, updated_at
+FROM claim_relays
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT 100;
+
+-- name: ListClaimRelaysByStatus :many
+SELECT id, user_id, platform, platform_user_id, payout_address, expiry, claim_nonce, signature, relayer_address, status, tx_nonce, gas_limit, tx_hash, submitted_at, block_number, gas_used, error, finished_at, created_at, updated_at
+FROM claim_relays
+WHERE relayer_address = $1
+  AND status = $2
+ORDER BY tx_nonce NULLS LAST, id
+LIMIT sqlc.arg(max_relays);
+
+-- name: NextClaimRelayNonce :one
+SELECT COALESCE(MAX(tx_nonce) + 1, 0)::bigint AS next_nonce
+FROM claim_relays
+WHERE relayer_address = $1
+  AND status = 'submitted';
+
+-- name: MarkClaimRelaySubmitted :one
+UPDATE claim_relays
+SET status = 'submitted',
+    tx_nonce = $2,
+    gas_limit = $3,
+    tx_hash = $4,
+    submitted_at = NOW(),
+    error = NULL,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, user_id, platform, platform_user_id, payout_address, expiry, claim_nonce, signature, relayer_address, status, tx_nonce, gas_limit, tx_hash, submitted_at, block_number, gas_used, error, finished_at, created_at, updated_at;
+
+-- name: SetClaimRelayMined :exec
+UPDATE claim_relays
+SET tx_hash = $2,
+    block_number = $3,
+    gas_used = $4,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: RequeueClaimRelay :exec
+UPDATE claim_relays
+SET status = 'queued',
+    tx_nonce = NULL,
+    gas_limit = NULL,
+    tx_hash = NULL,
+    submitted_at = NULL,
+    block_number = NULL,
+    gas_used = NULL,
+    error = $2,
+    updated_at = NOW()
+WHERE id = $1
+  AND status = 'submitted';
+
+-- name: FinishClaimRelay :one
+UPDATE claim_relays
+SET status = $2,
+    error = $3,
+    finished_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1
+  AND status IN ('queued', 'submitted')
+RETURNING id, user_id, platform, platform_user_id, payout_address, expiry, claim_nonce, signature, relayer_address, status, tx_nonce, gas_limit, tx_hash, submitted_at, block_number, gas_used, error, finished_at, created_at, updated_at;
+
+-- name: ExpireQueuedClaimRelays :many
+UPDATE claim_relays
+SET status = 'expired',
+    error = 'claim signature expired before it could be sent',
+    finished_at = NOW(),
+    updated_at = NOW()
+WHERE relayer_address = $1
+  AND status = 'queued'
+  AND expiry <= sqlc.arg(expires_before)
+RETURNING id, user_id, platform, platform_user_id, payout_address, expiry, claim_nonce, signature, relayer_address, status, tx_nonce, gas_limit, tx_hash, submitted_at, block_number, gas_used, error, finished_at, created_at, updated_at;
+
+-- name: MoveClaimRelaysToUser :exec
+UPDATE claim_relays
+SET user_id = sqlc.arg(to_user_id),
+    updated_at = NOW()
+WHERE user_id = sqlc.arg(from_user_id);
+
+-- name: CreateClaimRelayTx :one
+INSERT INTO claim_relay_txs (
+  relay_id, tx_hash, tx_nonce, gas_tip_cap, gas_fee_cap, kind, created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, NOW()
+)
+RETURNING id, relay_id, tx_hash, tx_nonce, gas_tip_cap, gas_fee_cap, created_at, kind, accepted;
+
+-- name: MarkClaimRelayTxAccepted :exec
+UPDATE claim_relay_txs
+SET accepted = true
+WHERE tx_hash = $1;
+
+-- name: ListClaimRelayTxs :many
+SELECT id, relay_id, tx_hash, tx_nonce, gas_tip_cap, gas_fee_cap, created_at, kind, accepted
+FROM claim_relay_txs
+WHERE relay_id = $1
+ORDER BY id;
+
+-- name: TryLockClaimRelayer :one
+SELECT pg_try_advisory_lock(sqlc.arg(lock_key)::bigint) AS locked;
+
+-- name: UnlockClaimRelayer :exec
+SELECT pg_advisory_unlock(sqlc.arg(lock_key)::bigint);
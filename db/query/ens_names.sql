@@ -0,0 +1,57 @@
+-- name: GetENSName :one
+SELECT name, address, checked_at
+FROM ens_names
+WHERE name = $1
+LIMIT 1;
+
+-- name: UpsertENSName :exec
+INSERT INTO ens_names (
+  name, address, checked_at
+) VALUES (
+  $1, $2, NOW()
+)
+ON CONFLICT (name) DO UPDATE
+SET address = EXCLUDED.address,
+    checked_at = NOW();
+
+-- name: ListENSNamesToCheck :many
+SELECT n.ens_name::varchar AS name
+FROM (
+  SELECT ens_name FROM payouts WHERE ens_name IS NOT NULL
+  UNION
+  SELECT ens_name FROM channel_payouts WHERE ens_name IS NOT NULL
+) n
+LEFT JOIN ens_names e ON e.name = n.ens_name
+WHERE e.checked_at IS NULL
+   OR e.checked_at < sqlc.arg(checked_before)
+ORDER BY e.checked_at NULLS FIRST
+LIMIT sqlc.arg(max_names);
+
+-- name: GetENSReverseName :one
+SELECT address, name, checked_at
+FROM ens_reverse_names
+WHERE address = $1
+LIMIT 1;
+
+-- name: UpsertENSReverseName :exec
+INSERT INTO ens_reverse_names (
+  address, name, checked_at
+) VALUES (
+  $1, $2, NOW()
+)
+ON CONFLICT (address) DO UPDATE
+SET name = EXCLUDED.name,
+    checked_at = NOW();
+
+-- name: ListAddressesForReverseCheck :many
+SELECT a.address::varchar AS address
+FROM (
+  SELECT address FROM payouts
+  UNION
+  SELECT address FROM channel_payouts
+) a
+LEFT JOIN ens_reverse_names r ON r.address = a.address
+WHERE r.checked_at IS NULL
+   OR r.checked_at < sqlc.arg(checked_before)
+ORDER BY r.checked_at NULLS FIRST
+LIMIT sqlc.arg(max_addresses);
@@ -1,5 +1,5 @@
 -- name: GetIdentity :one
-SELECT id, user_id, provider, provider_user_id, created_at, updated_at
+SELECT id, user_id, provider, provider_user_id, created_at, updated_at, email
 FROM identities
 WHERE provider = $1 AND provider_user_id = $2
 LIMIT 1;
@@ -10,10 +10,39 @@ INSERT INTO identities (
 ) VALUES (
   $1, $2, $3, NOW(), NOW()
 )
-RETURNING id, user_id, provider, provider_user_id, created_at, updated_at;
+RETURNING id, user_id, provider, provider_user_id, created_at, updated_at, email;
 
 -- name: ListIdentitiesByUser :many
-SELECT id, user_id, provider, provider_user_id, created_at, updated_at
+SELECT id, user_id, provider, provider_user_id, created_at, updated_at, email
 FROM identities
 WHERE user_id = $1
 ORDER BY id DESC;
+
+-- name: CountIdentitiesByUser :one
+SELECT COUNT(*)::bigint AS identities
+FROM identities
+WHERE user_id = $1;
+
+-- name: DeleteIdentityForUser :execrows
+DELETE FROM identities
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: MoveIdentitiesToUser :execrows
+UPDATE identities
+SET user_id = sqlc.arg(to_user_id),
+    updated_at = NOW()
+WHERE user_id = sqlc.arg(from_user_id);
+
+-- name: SetIdentityEmail :exec
+UPDATE identities
+SET email = $3,
+    updated_at = NOW()
+WHERE provider = $1
+  AND provider_user_id = $2;
+
+-- name: ListIdentityEmailsByUser :many
+SELECT DISTINCT email::varchar AS email
+FROM identities
+WHERE user_id = $1
+  AND email IS NOT NULL;
@@ -0,0 +1,28 @@
+-- name: InsertLedgerAllocation :exec
+INSERT INTO ledger_allocations (
+  ledger_event_id, address, split_id, bps, amount_raw
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (ledger_event_id, address) DO NOTHING;
+
+-- name: SumEscrowAllocationsBetween :many
+SELECT la.address, SUM(la.amount_raw)::text AS amount_raw
+FROM ledger_allocations la
+JOIN ledger_events le ON le.id = la.ledger_event_id
+WHERE le.platform = sqlc.arg(platform)
+  AND le.platform_user_id = sqlc.arg(platform_user_id)
+  AND le.event_type = 'TIP_ESCROW'
+  AND le.block_time > sqlc.arg(after)
+  AND le.block_time <= sqlc.arg(until)
+GROUP BY la.address
+ORDER BY la.address;
+
+-- name: SumEscrowTipsBetween :one
+SELECT COALESCE(SUM(amount_raw), 0)::text AS amount_raw
+FROM ledger_events
+WHERE platform = sqlc.arg(platform)
+  AND platform_user_id = sqlc.arg(platform_user_id)
+  AND event_type = 'TIP_ESCROW'
+  AND block_time > sqlc.arg(after)
+  AND block_time <= sqlc.arg(until);
@@ -1,14 +1,50 @@
 -- name: GetEarningsSummaryForUser :one
+WITH mine AS (
+  SELECT platform, platform_user_id, started_at, ended_at
+  FROM channel_ownership_epochs
+  WHERE user_id = sqlc.arg(user_id)::bigint
+),
+carry AS (
+  SELECT
+    COALESCE(SUM(cin.balance), 0) AS carried_in,
+    COALESCE(SUM(cout.balance), 0) AS carried_out
+  FROM mine m
+  LEFT JOIN LATERAL (
+    SELECT MAX(p.ended_at) AS at
+    FROM channel_ownership_epochs p
+    WHERE p.platform = m.platform
+      AND p.platform_user_id = m.platform_user_id
+      AND p.ended_at <= m.started_at
+  ) prev ON TRUE
+  LEFT JOIN LATERAL (
+    SELECT GREATEST(COALESCE(SUM(CASE le.event_type WHEN 'TIP_ESCROW' THEN le.amount_raw WHEN 'WITHDRAW' THEN -le.amount_raw ELSE 0 END), 0), 0) AS balance
+    FROM ledger_events le
+    WHERE le.platform = m.platform
+      AND le.platform_user_id = m.platform_user_id
+      AND le.block_time AT TIME ZONE 'UTC' < prev.at
+  ) cin ON TRUE
+  LEFT JOIN LATERAL (
+    SELECT GREATEST(COALESCE(SUM(CASE le.event_type WHEN 'TIP_ESCROW' THEN le.amount_raw WHEN 'WITHDRAW' THEN -le.amount_raw ELSE 0 END), 0), 0) AS balance
+    FROM ledger_events le
+    WHERE le.platform = m.platform
+      AND le.platform_user_id = m.platform_user_id
+      AND le.block_time AT TIME ZONE 'UTC' < m.ended_at
+  ) cout ON TRUE
+),
+own AS (
+  SELECT
+    COALESCE(SUM(CASE WHEN event_type IN ('TIP_DIRECT','TIP_ESCROW') THEN amount_raw ELSE 0 END), 0) AS earned,
+    COALESCE(SUM(CASE WHEN event_type = 'WITHDRAW' THEN amount_raw ELSE 0 END), 0) AS withdrawn
+  FROM ledger_events
+  WHERE user_id = sqlc.arg(user_id)::bigint
+)
 SELECT
-  COALESCE(SUM(CASE WHEN event_type IN ('TIP_DIRECT','TIP_ESCROW') THEN amount_raw ELSE 0 END), 0)::text AS earned_raw,
-  COALESCE(SUM(CASE WHEN event_type = 'WITHDRAW' THEN amount_raw ELSE 0 END), 0)::text AS withdrawn_raw,
-  (
-    COALESCE(SUM(CASE WHEN event_type IN ('TIP_DIRECT','TIP_ESCROW') THEN amount_raw ELSE 0 END), 0)
-    -
-    COALESCE(SUM(CASE WHEN event_type = 'WITHDRAW' THEN amount_raw ELSE 0 END), 0)
-  )::text AS pending_raw
-FROM ledger_events
-WHERE user_id = $1::bigint;
+  own.earned::text AS earned_raw,
+  own.withdrawn::text AS withdrawn_raw,
+  carry.carried_in::text AS escrow_carried_in_raw,
+  carry.carried_out::text AS escrow_carried_out_raw,
+  (own.earned + carry.carried_in - carry.carried_out - own.withdrawn)::text AS pending_raw
+FROM own, carry;
 
 -- name: ListTipsForUser :many
 SELECT
@@ -20,13 +56,21 @@ WHERE user_id = $1
 ORDER BY block_time DESC
 LIMIT $2 OFFSET $3;
 
--- name: BackfillLedgerEventsUserIDForChannel :exec
-UPDATE ledger_events
-SET user_id = $1,
+-- name: AttributeLedgerEventsForChannel :execrows
+UPDATE ledger_events le
+SET user_id = (
+      SELECT e.user_id
+      FROM channel_ownership_epochs e
+      WHERE e.platform = le.platform
+        AND e.platform_user_id = le.platform_user_id
+        AND (e.ended_at IS NULL OR e.ended_at > le.block_time AT TIME ZONE 'UTC')
+      ORDER BY e.started_at
+      LIMIT 1
+    ),
     updated_at = NOW()
-WHERE platform = $2
-  AND platform_user_id = $3
-  AND user_id IS NULL;
+WHERE le.platform = $1
+  AND le.platform_user_id = $2
+  AND le.event_type IN ('TIP_DIRECT', 'TIP_ESCROW', 'WITHDRAW');
 
 -- name: InsertLedgerEvent :one
 INSERT INTO ledger_events (
@@ -39,4 +83,25 @@ INSERT INTO ledger_events (
 ON CONFLICT (tx_hash, log_index) DO NOTHING
 RETURNING
   id, platform, platform_user_id, user_id, event_type, amount_raw, message,
-  tx_hash, log_index, block_time, created_at, updated_at;
\ No newline at end of file
+  tx_hash, log_index, block_time, created_at, updated_at;
+
+
+-- name: MoveLedgerEventsToUser :execrows
+UPDATE ledger_events
+SET user_id = sqlc.arg(to_user_id)::bigint,
+    updated_at = NOW()
+WHERE user_id = sqlc.arg(from_user_id)::bigint;
+
+-- name: GetLastWithdrawalTime :one
+SELECT COALESCE(MAX(block_time), 'epoch'::timestamp)::timestamp AS block_time
+FROM ledger_events
+WHERE platform = sqlc.arg(platform)
+  AND platform_user_id = sqlc.arg(platform_user_id)
+  AND event_type = 'WITHDRAW'
+  AND block_time < sqlc.arg(before);
+
+-- name: GetChannelEscrowBalance :one
+SELECT GREATEST(COALESCE(SUM(CASE event_type WHEN 'TIP_ESCROW' THEN amount_raw WHEN 'WITHDRAW' THEN -amount_raw ELSE 0 END), 0), 0)::text AS balance_raw
+FROM ledger_events
+WHERE platform = $1
+  AND platform_user_id = $2;
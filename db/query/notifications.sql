@@ -0,0 +1,23 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (user_id, kind, body, created_at)
+VALUES ($1, $2, $3, NOW())
+RETURNING id, user_id, kind, body, read_at, created_at;
+
+-- name: ListNotificationsForUser :many
+SELECT id, user_id, kind, body, read_at, created_at
+FROM notifications
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE id = $1
+  AND user_id = $2
+  AND read_at IS NULL;
+
+-- name: MoveNotificationsToUser :exec
+UPDATE notifications
+SET user_id = sqlc.arg(to_user_id)
+WHERE user_id = sqlc.arg(from_user_id);
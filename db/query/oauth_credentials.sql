@@ -0,0 +1,28 @@
+-- name: UpsertOAuthCredential :one
+INSERT INTO oauth_credentials (
+  user_id, platform, platform_user_id, refresh_token_enc, scope, created_at, updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, NOW(), NOW()
+)
+ON CONFLICT (platform, platform_user_id) DO UPDATE
+SET user_id = EXCLUDED.user_id,
+    refresh_token_enc = EXCLUDED.refresh_token_enc,
+    scope = EXCLUDED.scope,
+    updated_at = NOW()
+RETURNING id, user_id, platform, platform_user_id, refresh_token_enc, scope, created_at, updated_at;
+
+-- name: GetOAuthCredentialForChannel :one
+SELECT id, user_id, platform, platform_user_id, refresh_token_enc, scope, created_at, updated_at
+FROM oauth_credentials
+WHERE platform = $1 AND platform_user_id = $2
+LIMIT 1;
+
+-- name: MoveOAuthCredentialsToUser :exec
+UPDATE oauth_credentials
+SET user_id = sqlc.arg(to_user_id),
+    updated_at = NOW()
+WHERE user_id = sqlc.arg(from_user_id);
+
+-- name: DeleteOAuthCredentialForChannel :exec
+DELETE FROM oauth_credentials
+WHERE platform = $1 AND platform_user_id = $2;
@@ -0,0 +1,15 @@
+-- name: CreateOAuthState :exec
+INSERT INTO oauth_states (
+  state, user_id, platform, platform_user_id, code_verifier, purpose, expires_at, created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, NOW()
+);
+
+-- name: ConsumeOAuthState :one
+DELETE FROM oauth_states
+WHERE state = $1
+RETURNING state, user_id, platform, platform_user_id, code_verifier, purpose, expires_at, created_at;
+
+-- name: DeleteExpiredOAuthStates :exec
+DELETE FROM oauth_states
+WHERE expires_at < NOW();
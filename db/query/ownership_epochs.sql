@@ -0,0 +1,33 @@
+-- name: GetOpenOwnershipEpoch :one
+SELECT id, platform, platform_user_id, user_id, started_at, ended_at, created_at
+FROM channel_ownership_epochs
+WHERE platform = $1
+  AND platform_user_id = $2
+  AND ended_at IS NULL
+LIMIT 1
+FOR UPDATE;
+
+-- name: CreateOwnershipEpoch :one
+INSERT INTO channel_ownership_epochs (
+  platform, platform_user_id, user_id, started_at, created_at
+) VALUES (
+  $1, $2, $3, NOW(), NOW()
+)
+RETURNING id, platform, platform_user_id, user_id, started_at, ended_at, created_at;
+
+-- name: CloseOwnershipEpoch :exec
+UPDATE channel_ownership_epochs
+SET ended_at = NOW()
+WHERE id = $1
+  AND ended_at IS NULL;
+
+-- name: ListOwnershipEpochsForUser :many
+SELECT id, platform, platform_user_id, user_id, started_at, ended_at, created_at
+FROM channel_ownership_epochs
+WHERE user_id = $1
+ORDER BY started_at DESC;
+
+-- name: MoveOwnershipEpochsToUser :exec
+UPDATE channel_ownership_epochs
+SET user_id = sqlc.arg(to_user_id)
+WHERE user_id = sqlc.arg(from_user_id);
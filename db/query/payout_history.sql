@@ -0,0 +1,77 @@
+-- name: CreatePayoutChange :one
+INSERT INTO payout_history (
+  user_id, chain, platform, platform_user_id, old_address, new_address, new_ens_name, proof_method, status,
+  effective_at, applied_at, created_at
+) VALUES (
+  sqlc.arg(user_id), sqlc.arg(chain), sqlc.arg(platform), sqlc.arg(platform_user_id),
+  sqlc.arg(old_address), sqlc.arg(new_address), sqlc.arg(new_ens_name), sqlc.arg(proof_method),
+  sqlc.arg(status)::varchar, sqlc.arg(effective_at),
+  CASE WHEN sqlc.arg(status)::varchar = 'applied' THEN NOW() END,
+  NOW()
+)
+RETURNING id, user_id, chain, old_address, new_address, proof_method, status,
+  effective_at, applied_at, cancelled_at, created_at, platform, platform_user_id, new_ens_name;
+
+-- name: ListPayoutHistoryByUser :many
+SELECT id, user_id, chain, old_address, new_address, proof_method, status,
+  effective_at, applied_at, cancelled_at, created_at, platform, platform_user_id, new_ens_name
+FROM payout_history
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: ListDuePayoutChanges :many
+SELECT id, user_id, chain, old_address, new_address, proof_method, status,
+  effective_at, applied_at, cancelled_at, created_at, platform, platform_user_id, new_ens_name
+FROM payout_history
+WHERE status = 'pending'
+  AND effective_at <= sqlc.arg(due_before)
+ORDER BY effective_at
+LIMIT sqlc.arg(max_changes);
+
+-- name: ApplyPayoutChange :one
+UPDATE payout_history
+SET status = 'applied',
+    applied_at = NOW()
+WHERE id = $1
+  AND status = 'pending'
+  AND effective_at <= NOW()
+RETURNING id, user_id, chain, old_address, new_address, proof_method, status,
+  effective_at, applied_at, cancelled_at, created_at, platform, platform_user_id, new_ens_name;
+
+-- name: CancelPayoutChange :one
+UPDATE payout_history
+SET status = 'cancelled',
+    cancelled_at = NOW()
+WHERE id = $1
+  AND user_id = $2
+  AND status = 'pending'
+RETURNING id, user_id, chain, old_address, new_address, proof_method, status,
+  effective_at, applied_at, cancelled_at, created_at, platform, platform_user_id, new_ens_name;
+
+-- name: CancelPendingPayoutChanges :execrows
+UPDATE payout_history
+SET status = 'cancelled',
+    cancelled_at = NOW()
+WHERE user_id = sqlc.arg(user_id)
+  AND (
+    sqlc.narg(chain)::varchar IS NULL
+    OR (
+      chain = sqlc.narg(chain)::varchar
+      AND platform IS NOT DISTINCT FROM sqlc.narg(platform)::varchar
+      AND platform_user_id IS NOT DISTINCT FROM sqlc.narg(platform_user_id)::varchar
+    )
+  )
+  AND status = 'pending';
+
+-- name: CancelPendingPayoutChangesForChannel :exec
+UPDATE payout_history
+SET status = 'cancelled',
+    cancelled_at = NOW()
+WHERE platform = $1
+  AND platform_user_id = $2
+  AND status = 'pending';
+
+-- name: MovePayoutHistoryToUser :exec
+UPDATE payout_history
+SET user_id = sqlc.arg(to_user_id)
+WHERE user_id = sqlc.arg(from_user_id);
@@ -1,20 +1,128 @@
 -- name: UpsertPayout :one
 INSERT INTO payouts (
-  user_id, chain, address, created_at, updated_at
+  user_id, chain, address, proven_at, proof_method, ens_name, created_at, updated_at
 ) VALUES (
-  $1, $2, $3, NOW(), NOW()
+  $1, $2, $3, $4, $5, $6, NOW(), NOW()
 )
 ON CONFLICT (user_id, chain) DO UPDATE
 SET address = EXCLUDED.address,
+    proven_at = EXCLUDED.proven_at,
+    proof_method = EXCLUDED.proof_method,
+    ens_name = EXCLUDED.ens_name,
     updated_at = NOW()
-RETURNING id, user_id, chain, address, created_at, updated_at;
+RETURNING id, user_id, chain, address, created_at, updated_at, proven_at, proof_method, ens_name;
+
+-- name: GetPayoutForChain :one
+SELECT id, user_id, chain, address, created_at, updated_at, proven_at, proof_method, ens_name
+FROM payouts
+WHERE user_id = $1
+  AND chain = $2
+LIMIT 1;
 
 -- name: ResolvePayoutByChannelID :one
-SELECT p.address
+SELECT COALESCE(cp.address, p.address, '')::varchar AS address,
+  COALESCE(cs.recipients, '[]'::jsonb)::jsonb AS split_recipients,
+  COALESCE(rn.name, '')::varchar AS name
 FROM social_links sl
-JOIN payouts p ON p.user_id = sl.user_id
+LEFT JOIN channel_payouts cp
+  ON cp.user_id = sl.user_id
+ AND cp.platform = sl.platform
+ AND cp.platform_user_id = sl.platform_user_id
+ AND cp.chain = $3
+LEFT JOIN payouts p
+  ON p.user_id = sl.user_id
+ AND p.chain = $3
+LEFT JOIN channel_splits cs
+  ON cs.user_id = sl.user_id
+ AND cs.platform = sl.platform
+ AND cs.platform_user_id = sl.platform_user_id
+ AND cs.status = 'active'
+LEFT JOIN ens_reverse_names rn
+  ON rn.address = COALESCE(cp.address, p.address)
 WHERE sl.platform = $1
   AND sl.platform_user_id = $2
   AND sl.verified_at IS NOT NULL
-  AND p.chain = $3
+  AND sl.stale_at IS NULL
+  AND (COALESCE(cp.address, p.address) IS NOT NULL OR cs.id IS NOT NULL)
 LIMIT 1;
+
+-- name: ListPayoutsByUser :many
+SELECT id, user_id, chain, address, created_at, updated_at, proven_at, proof_method, ens_name
+FROM payouts
+WHERE user_id = $1
+ORDER BY chain;
+
+-- name: IsProvenPayoutAddress :one
+SELECT (EXISTS (
+  SELECT 1
+  FROM payouts
+  WHERE payouts.user_id = sqlc.arg(user_id)
+    AND payouts.address = sqlc.arg(address)
+    AND payouts.proven_at IS NOT NULL
+) OR EXISTS (
+  SELECT 1
+  FROM channel_payouts
+  WHERE channel_payouts.user_id = sqlc.arg(user_id)
+    AND channel_payouts.platform = sqlc.arg(platform)
+    AND channel_payouts.platform_user_id = sqlc.arg(platform_user_id)
+    AND channel_payouts.address = sqlc.arg(address)
+    AND channel_payouts.proven_at IS NOT NULL
+))::boolean AS proven;
+
+-- name: ListChannelsByPayoutAddress :many
+SELECT sl.platform, sl.platform_user_id
+FROM social_links sl
+LEFT JOIN channel_payouts cp
+  ON cp.user_id = sl.user_id
+ AND cp.platform = sl.platform
+ AND cp.platform_user_id = sl.platform_user_id
+ AND cp.chain = sqlc.arg(chain)
+LEFT JOIN payouts p
+  ON p.user_id = sl.user_id
+ AND p.chain = sqlc.arg(chain)
+WHERE COALESCE(cp.address, p.address) = sqlc.arg(address);
+
+-- name: ListPayoutsByENSName :many
+SELECT p.user_id, p.chain, NULL::varchar AS platform, NULL::varchar AS platform_user_id, p.address
+FROM payouts p
+WHERE p.ens_name = sqlc.arg(ens_name)
+UNION ALL
+SELECT cp.user_id, cp.chain, cp.platform, cp.platform_user_id, cp.address
+FROM channel_payouts cp
+WHERE cp.ens_name = sqlc.arg(ens_name);
+
+-- name: DeletePayout :exec
+DELETE FROM payouts
+WHERE id = $1;
+
+-- name: MovePayoutsToUser :execrows
+UPDATE payouts
+SET user_id = sqlc.arg(to_user_id),
+    updated_at = NOW()
+WHERE user_id = sqlc.arg(from_user_id);
+
+-- name: ResolvePayoutsByChannelIDs :many
+SELECT sl.platform_user_id, COALESCE(cp.address, p.address, '')::varchar AS address,
+  COALESCE(cs.recipients, '[]'::jsonb)::jsonb AS split_recipients,
+  COALESCE(rn.name, '')::varchar AS name
+FROM social_links sl
+LEFT JOIN channel_payouts cp
+  ON cp.user_id = sl.user_id
+ AND cp.platform = sl.platform
+ AND cp.platform_user_id = sl.platform_user_id
+ AND cp.chain = sqlc.arg(chain)
+LEFT JOIN payouts p
+  ON p.user_id = sl.user_id
+ AND p.chain = sqlc.arg(chain)
+LEFT JOIN channel_splits cs
+  ON cs.user_id = sl.user_id
+ AND cs.platform = sl.platform
+ AND cs.platform_user_id = sl.platform_user_id
+ AND cs.status = 'active'
+LEFT JOIN ens_reverse_names rn
+  ON rn.address = COALESCE(cp.address, p.address)
+WHERE sl.platform = sqlc.arg(platform)
+  AND sl.platform_user_id = ANY(sqlc.arg(platform_user_ids)::varchar[])
+  AND sl.verified_at IS NOT NULL
+  AND sl.stale_at IS NULL
+  AND (COALESCE(cp.address, p.address) IS NOT NULL OR cs.id IS NOT NULL);
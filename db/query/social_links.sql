@@ -1,30 +1,107 @@
 -- name: GetSocialLinkByPlatformUser :one
-SELECT id, user_id, platform, platform_user_id, verified_at, created_at, updated_at
+SELECT id, user_id, platform, platform_user_id, verified_at, created_at, updated_at, verification_method,
+       last_checked_at, check_failed_at, stale_at
 FROM social_links
 WHERE platform = $1 AND platform_user_id = $2
 LIMIT 1;
 
 -- name: CreateSocialLink :one
 INSERT INTO social_links (
-  user_id, platform, platform_user_id, verified_at, created_at, updated_at
+  user_id, platform, platform_user_id, verified_at, verification_method, last_checked_at, created_at, updated_at
 ) VALUES (
-  $1, $2, $3, $4, NOW(), NOW()
+  $1, $2, $3, $4, $5, $4, NOW(), NOW()
 )
-RETURNING id, user_id, platform, platform_user_id, verified_at, created_at, updated_at;
+RETURNING id, user_id, platform, platform_user_id, verified_at, created_at, updated_at, verification_method,
+          last_checked_at, check_failed_at, stale_at;
 
 -- name: UpdateSocialLinkVerifiedAt :one
 UPDATE social_links
 SET
   verified_at = $2,
+  verification_method = $3,
+  last_checked_at = $2,
+  check_failed_at = NULL,
+  stale_at = NULL,
   updated_at = NOW()
 WHERE id = $1
-RETURNING id, user_id, platform, platform_user_id, verified_at, created_at, updated_at;
+RETURNING id, user_id, platform, platform_user_id, verified_at, created_at, updated_at, verification_method,
+          last_checked_at, check_failed_at, stale_at;
 
 -- name: TransferSocialLinkToUser :one
 UPDATE social_links
 SET
   user_id = $2,
   verified_at = $3,
+  verification_method = $4,
+  last_checked_at = $3,
+  check_failed_at = NULL,
+  stale_at = NULL,
   updated_at = NOW()
 WHERE id = $1
-RETURNING id, user_id, platform, platform_user_id, verified_at, created_at, updated_at;
\ No newline at end of file
+RETURNING id, user_id, platform, platform_user_id, verified_at, created_at, updated_at, verification_method,
+          last_checked_at, check_failed_at, stale_at;
+
+-- name: MoveSocialLinksToUser :execrows
+UPDATE social_links
+SET user_id = sqlc.arg(to_user_id),
+    updated_at = NOW()
+WHERE user_id = sqlc.arg(from_user_id);
+
+-- name: ListSocialLinksDueForRecheck :many
+SELECT id, user_id, platform, platform_user_id, verified_at, created_at, updated_at, verification_method,
+       last_checked_at, check_failed_at, stale_at
+FROM social_links
+WHERE verified_at IS NOT NULL
+  AND stale_at IS NULL
+  AND (check_failed_at IS NOT NULL OR COALESCE(last_checked_at, verified_at) < sqlc.arg(checked_before)::timestamptz)
+ORDER BY COALESCE(last_checked_at, verified_at)
+LIMIT sqlc.arg(max_links);
+
+-- name: MarkSocialLinkRechecked :exec
+UPDATE social_links
+SET last_checked_at = NOW(),
+    check_failed_at = NULL,
+    stale_at = NULL,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: MarkSocialLinkCheckFailed :exec
+UPDATE social_links
+SET last_checked_at = NOW(),
+    check_failed_at = COALESCE(check_failed_at, NOW()),
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: MarkSocialLinkCheckSkipped :exec
+UPDATE social_links
+SET last_checked_at = NOW()
+WHERE id = $1;
+
+-- name: MarkSocialLinkStale :exec
+UPDATE social_links
+SET stale_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND stale_at IS NULL;
+
+-- name: DeleteSocialLink :exec
+DELETE FROM social_links
+WHERE id = $1;
+
+-- name: DeleteExpiredUnverifiedSocialLink :execrows
+DELETE FROM social_links
+WHERE platform = sqlc.arg(platform)
+  AND platform_user_id = sqlc.arg(platform_user_id)
+  AND verified_at IS NULL
+  AND updated_at < sqlc.arg(expired_before);
+
+-- name: DeleteExpiredUnverifiedSocialLinks :execrows
+DELETE FROM social_links
+WHERE verified_at IS NULL
+  AND updated_at < sqlc.arg(expired_before);
+
+-- name: CountPendingSocialLinksForUser :one
+SELECT COUNT(*)::bigint AS pending
+FROM social_links
+WHERE user_id = sqlc.arg(user_id)
+  AND verified_at IS NULL
+  AND updated_at >= sqlc.arg(expired_before);
@@ -0,0 +1,23 @@
+-- name: CreateStepUpChallenge :one
+INSERT INTO step_up_challenges (
+  user_id, address, action, nonce, message, expires_at, created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, NOW()
+)
+RETURNING id, user_id, address, action, nonce, message, expires_at, used_at, created_at;
+
+-- name: UseStepUpChallenge :one
+UPDATE step_up_challenges
+SET used_at = NOW()
+WHERE id = $1
+  AND user_id = $2
+  AND used_at IS NULL
+RETURNING id, user_id, address, action, nonce, message, expires_at, used_at, created_at;
+
+-- name: DeleteExpiredStepUpChallenges :execrows
+DELETE FROM step_up_challenges
+WHERE expires_at < sqlc.arg(expired_before);
+
+-- name: DeleteStepUpChallengesForUser :exec
+DELETE FROM step_up_challenges
+WHERE user_id = $1;
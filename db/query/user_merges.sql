@@ -0,0 +1,21 @@
+-- name: CreateUserMerge :one
+INSERT INTO user_merges (
+  surviving_user_id, merged_user_id, proof_address,
+  identities_moved, social_links_moved, payouts_moved, ledger_events_moved,
+  discarded_payouts, created_at
+) VALUES (
+  $1, $2, $3,
+  $4, $5, $6, $7,
+  $8, NOW()
+)
+RETURNING id, surviving_user_id, merged_user_id, proof_address,
+  identities_moved, social_links_moved, payouts_moved, ledger_events_moved,
+  discarded_payouts, created_at;
+
+-- name: ListUserMergesForUser :many
+SELECT id, surviving_user_id, merged_user_id, proof_address,
+  identities_moved, social_links_moved, payouts_moved, ledger_events_moved,
+  discarded_payouts, created_at
+FROM user_merges
+WHERE surviving_user_id = $1
+ORDER BY created_at DESC;
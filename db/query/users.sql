@@ -8,3 +8,13 @@ SELECT id, created_at, updated_at
 FROM users
 WHERE id = $1
 LIMIT 1;
+
+-- name: LockUser :one
+SELECT id
+FROM users
+WHERE id = $1
+FOR UPDATE;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1;
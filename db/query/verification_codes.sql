@@ -0,0 +1,27 @@
+-- name: UpsertVerificationCode :one
+INSERT INTO verification_codes (
+  user_id, platform, platform_user_id, code, expires_at, created_at
+) VALUES (
+  $1, $2, $3, $4, $5, NOW()
+)
+ON CONFLICT (user_id, platform, platform_user_id) DO UPDATE
+SET code = EXCLUDED.code,
+    expires_at = EXCLUDED.expires_at,
+    used_at = NULL,
+    created_at = NOW()
+RETURNING id, user_id, platform, platform_user_id, code, expires_at, used_at, created_at;
+
+-- name: GetVerificationCode :one
+SELECT id, user_id, platform, platform_user_id, code, expires_at, used_at, created_at
+FROM verification_codes
+WHERE user_id = $1 AND platform = $2 AND platform_user_id = $3
+LIMIT 1;
+
+-- name: MarkVerificationCodeUsed :exec
+UPDATE verification_codes
+SET used_at = NOW()
+WHERE id = $1;
+
+-- name: DeleteVerificationCodesForUser :exec
+DELETE FROM verification_codes
+WHERE user_id = $1;
@@ -0,0 +1,11 @@
+-- name: GetVideoChannel :one
+SELECT platform, video_id, platform_user_id, fetched_at
+FROM video_channels
+WHERE platform = $1 AND video_id = $2;
+
+-- name: UpsertVideoChannel :exec
+INSERT INTO video_channels (platform, video_id, platform_user_id, fetched_at)
+VALUES ($1, $2, $3, NOW())
+ON CONFLICT (platform, video_id) DO UPDATE
+SET platform_user_id = EXCLUDED.platform_user_id,
+    fetched_at = NOW();
@@ -7,15 +7,29 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
+const countIdentitiesByUser = `-- name: CountIdentitiesByUser :one
+SELECT COUNT(*)::bigint AS identities
+FROM identities
+WHERE user_id = $1
+`
+
+func (q *Queries) CountIdentitiesByUser(ctx context.Context, userID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countIdentitiesByUser, userID)
+	var identities int64
+	err := row.Scan(&identities)
+	return identities, err
+}
+
 const createIdentity = `-- name: CreateIdentity :one
 INSERT INTO identities (
   user_id, provider, provider_user_id, created_at, updated_at
 ) VALUES (
   $1, $2, $3, NOW(), NOW()
 )
-RETURNING id, user_id, provider, provider_user_id, created_at, updated_at
+RETURNING id, user_id, provider, provider_user_id, created_at, updated_at, email
 `
 
 type CreateIdentityParams struct {
@@ -34,12 +48,32 @@ func (q *Queries) CreateIdentity(ctx context.Context, arg CreateIdentityParams)
 		&i.ProviderUserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Email,
 	)
 	return i, err
 }
 
+const deleteIdentityForUser = `-- name: DeleteIdentityForUser :execrows
+DELETE FROM identities
+WHERE id = $1
+  AND user_id = $2
+`
+
+type DeleteIdentityForUserParams struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) DeleteIdentityForUser(ctx context.Context, arg DeleteIdentityForUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteIdentityForUser, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getIdentity = `-- name: GetIdentity :one
-SELECT id, user_id, provider, provider_user_id, created_at, updated_at
+SELECT id, user_id, provider, provider_user_id, created_at, updated_at, email
 FROM identities
 WHERE provider = $1 AND provider_user_id = $2
 LIMIT 1
@@ -60,12 +94,13 @@ func (q *Queries) GetIdentity(ctx context.Context, arg GetIdentityParams) (Ident
 		&i.ProviderUserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Email,
 	)
 	return i, err
 }
 
 const listIdentitiesByUser = `-- name: ListIdentitiesByUser :many
-SELECT id, user_id, provider, provider_user_id, created_at, updated_at
+SELECT id, user_id, provider, provider_user_id, created_at, updated_at, email
 FROM identities
 WHERE user_id = $1
 ORDER BY id DESC
@@ -87,6 +122,7 @@ func (q *Queries) ListIdentitiesByUser(ctx context.Context, userID int64) ([]Ide
 			&i.ProviderUserID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Email,
 		); err != nil {
 			return nil, err
 		}
@@ -100,3 +136,72 @@ func (q *Queries) ListIdentitiesByUser(ctx context.Context, userID int64) ([]Ide
 	}
 	return items, nil
 }
+
+const listIdentityEmailsByUser = `-- name: ListIdentityEmailsByUser :many
+SELECT DISTINCT email::varchar AS email
+FROM identities
+WHERE user_id = $1
+  AND email IS NOT NULL
+`
+
+func (q *Queries) ListIdentityEmailsByUser(ctx context.Context, userID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listIdentityEmailsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		items = append(items, email)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveIdentitiesToUser = `-- name: MoveIdentitiesToUser :execrows
+UPDATE identities
+SET user_id = $1,
+    updated_at = NOW()
+WHERE user_id = $2
+`
+
+type MoveIdentitiesToUserParams struct {
+	ToUserID   int64 `json:"to_user_id"`
+	FromUserID int64 `json:"from_user_id"`
+}
+
+func (q *Queries) MoveIdentitiesToUser(ctx context.Context, arg MoveIdentitiesToUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, moveIdentitiesToUser, arg.ToUserID, arg.FromUserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setIdentityEmail = `-- name: SetIdentityEmail :exec
+UPDATE identities
+SET email = $3,
+    updated_at = NOW()
+WHERE provider = $1
+  AND provider_user_id = $2
+`
+
+type SetIdentityEmailParams struct {
+	Provider       string         `json:"provider"`
+	ProviderUserID string         `json:"provider_user_id"`
+	Email          sql.NullString `json:"email"`
+}
+
+func (q *Queries) SetIdentityEmail(ctx context.Context, arg SetIdentityEmailParams) error {
+	_, err := q.db.ExecContext(ctx, setIdentityEmail, arg.Provider, arg.ProviderUserID, arg.Email)
+	return err
+}
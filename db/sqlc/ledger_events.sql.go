@@ -11,52 +11,147 @@ import (
 	"time"
 )
 
-const backfillLedgerEventsUserIDForChannel = `-- name: BackfillLedgerEventsUserIDForChannel :exec
-UPDATE ledger_events
-SET user_id = $1,
+const attributeLedgerEventsForChannel = `-- name: AttributeLedgerEventsForChannel :execrows
+UPDATE ledger_events le
+SET user_id = (
+      SELECT e.user_id
+      FROM channel_ownership_epochs e
+      WHERE e.platform = le.platform
+        AND e.platform_user_id = le.platform_user_id
+        AND (e.ended_at IS NULL OR e.ended_at > le.block_time AT TIME ZONE 'UTC')
+      ORDER BY e.started_at
+      LIMIT 1
+    ),
     updated_at = NOW()
-WHERE platform = $2
-  AND platform_user_id = $3
-  AND user_id IS NULL
+WHERE le.platform = $1
+  AND le.platform_user_id = $2
+  AND le.event_type IN ('TIP_DIRECT', 'TIP_ESCROW', 'WITHDRAW')
+`
+
+type AttributeLedgerEventsForChannelParams struct {
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+}
+
+func (q *Queries) AttributeLedgerEventsForChannel(ctx context.Context, arg AttributeLedgerEventsForChannelParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, attributeLedgerEventsForChannel, arg.Platform, arg.PlatformUserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getChannelEscrowBalance = `-- name: GetChannelEscrowBalance :one
+SELECT GREATEST(COALESCE(SUM(CASE event_type WHEN 'TIP_ESCROW' THEN amount_raw WHEN 'WITHDRAW' THEN -amount_raw ELSE 0 END), 0), 0)::text AS balance_raw
+FROM ledger_events
+WHERE platform = $1
+  AND platform_user_id = $2
 `
 
-type BackfillLedgerEventsUserIDForChannelParams struct {
-	UserID         sql.NullInt64 `json:"user_id"`
-	Platform       string        `json:"platform"`
-	PlatformUserID string        `json:"platform_user_id"`
+type GetChannelEscrowBalanceParams struct {
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
 }
 
-func (q *Queries) BackfillLedgerEventsUserIDForChannel(ctx context.Context, arg BackfillLedgerEventsUserIDForChannelParams) error {
-	_, err := q.db.ExecContext(ctx, backfillLedgerEventsUserIDForChannel, arg.UserID, arg.Platform, arg.PlatformUserID)
-	return err
+func (q *Queries) GetChannelEscrowBalance(ctx context.Context, arg GetChannelEscrowBalanceParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getChannelEscrowBalance, arg.Platform, arg.PlatformUserID)
+	var balance_raw string
+	err := row.Scan(&balance_raw)
+	return balance_raw, err
 }
 
 const getEarningsSummaryForUser = `-- name: GetEarningsSummaryForUser :one
+WITH mine AS (
+  SELECT platform, platform_user_id, started_at, ended_at
+  FROM channel_ownership_epochs
+  WHERE user_id = $1::bigint
+),
+carry AS (
+  SELECT
+    COALESCE(SUM(cin.balance), 0) AS carried_in,
+    COALESCE(SUM(cout.balance), 0) AS carried_out
+  FROM mine m
+  LEFT JOIN LATERAL (
+    SELECT MAX(p.ended_at) AS at
+    FROM channel_ownership_epochs p
+    WHERE p.platform = m.platform
+      AND p.platform_user_id = m.platform_user_id
+      AND p.ended_at <= m.started_at
+  ) prev ON TRUE
+  LEFT JOIN LATERAL (
+    SELECT GREATEST(COALESCE(SUM(CASE le.event_type WHEN 'TIP_ESCROW' THEN le.amount_raw WHEN 'WITHDRAW' THEN -le.amount_raw ELSE 0 END), 0), 0) AS balance
+    FROM ledger_events le
+    WHERE le.platform = m.platform
+      AND le.platform_user_id = m.platform_user_id
+      AND le.block_time AT TIME ZONE 'UTC' < prev.at
+  ) cin ON TRUE
+  LEFT JOIN LATERAL (
+    SELECT GREATEST(COALESCE(SUM(CASE le.event_type WHEN 'TIP_ESCROW' THEN le.amount_raw WHEN 'WITHDRAW' THEN -le.amount_raw ELSE 0 END), 0), 0) AS balance
+    FROM ledger_events le
+    WHERE le.platform = m.platform
+      AND le.platform_user_id = m.platform_user_id
+      AND le.block_time AT TIME ZONE 'UTC' < m.ended_at
+  ) cout ON TRUE
+),
+own AS (
+  SELECT
+    COALESCE(SUM(CASE WHEN event_type IN ('TIP_DIRECT','TIP_ESCROW') THEN amount_raw ELSE 0 END), 0) AS earned,
+    COALESCE(SUM(CASE WHEN event_type = 'WITHDRAW' THEN amount_raw ELSE 0 END), 0) AS withdrawn
+  FROM ledger_events
+  WHERE user_id = $1::bigint
+)
 SELECT
-  COALESCE(SUM(CASE WHEN event_type IN ('TIP_DIRECT','TIP_ESCROW') THEN amount_raw ELSE 0 END), 0)::text AS earned_raw,
-  COALESCE(SUM(CASE WHEN event_type = 'WITHDRAW' THEN amount_raw ELSE 0 END), 0)::text AS withdrawn_raw,
-  (
-    COALESCE(SUM(CASE WHEN event_type IN ('TIP_DIRECT','TIP_ESCROW') THEN amount_raw ELSE 0 END), 0)
-    -
-    COALESCE(SUM(CASE WHEN event_type = 'WITHDRAW' THEN amount_raw ELSE 0 END), 0)
-  )::text AS pending_raw
-FROM ledger_events
-WHERE user_id = $1::bigint
+  own.earned::text AS earned_raw,
+  own.withdrawn::text AS withdrawn_raw,
+  carry.carried_in::text AS escrow_carried_in_raw,
+  carry.carried_out::text AS escrow_carried_out_raw,
+  (own.earned + carry.carried_in - carry.carried_out - own.withdrawn)::text AS pending_raw
+FROM own, carry
 `
 
 type GetEarningsSummaryForUserRow struct {
-	EarnedRaw    string `json:"earned_raw"`
-	WithdrawnRaw string `json:"withdrawn_raw"`
-	PendingRaw   string `json:"pending_raw"`
+	EarnedRaw           string `json:"earned_raw"`
+	WithdrawnRaw        string `json:"withdrawn_raw"`
+	EscrowCarriedInRaw  string `json:"escrow_carried_in_raw"`
+	EscrowCarriedOutRaw string `json:"escrow_carried_out_raw"`
+	PendingRaw          string `json:"pending_raw"`
 }
 
-func (q *Queries) GetEarningsSummaryForUser(ctx context.Context, dollar_1 int64) (GetEarningsSummaryForUserRow, error) {
-	row := q.db.QueryRowContext(ctx, getEarningsSummaryForUser, dollar_1)
+func (q *Queries) GetEarningsSummaryForUser(ctx context.Context, userID int64) (GetEarningsSummaryForUserRow, error) {
+	row := q.db.QueryRowContext(ctx, getEarningsSummaryForUser, userID)
 	var i GetEarningsSummaryForUserRow
-	err := row.Scan(&i.EarnedRaw, &i.WithdrawnRaw, &i.PendingRaw)
+	err := row.Scan(
+		&i.EarnedRaw,
+		&i.WithdrawnRaw,
+		&i.EscrowCarriedInRaw,
+		&i.EscrowCarriedOutRaw,
+		&i.PendingRaw,
+	)
 	return i, err
 }
 
+const getLastWithdrawalTime = `-- name: GetLastWithdrawalTime :one
+SELECT COALESCE(MAX(block_time), 'epoch'::timestamp)::timestamp AS block_time
+FROM ledger_events
+WHERE platform = $1
+  AND platform_user_id = $2
+  AND event_type = 'WITHDRAW'
+  AND block_time < $3
+`
+
+type GetLastWithdrawalTimeParams struct {
+	Platform       string    `json:"platform"`
+	PlatformUserID string    `json:"platform_user_id"`
+	Before         time.Time `json:"before"`
+}
+
+func (q *Queries) GetLastWithdrawalTime(ctx context.Context, arg GetLastWithdrawalTimeParams) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getLastWithdrawalTime, arg.Platform, arg.PlatformUserID, arg.Before)
+	var block_time time.Time
+	err := row.Scan(&block_time)
+	return block_time, err
+}
+
 const insertLedgerEvent = `-- name: InsertLedgerEvent :one
 INSERT INTO ledger_events (
   platform, platform_user_id, user_id, event_type, amount_raw, message,
@@ -165,3 +260,23 @@ func (q *Queries) ListTipsForUser(ctx context.Context, arg ListTipsForUserParams
 	}
 	return items, nil
 }
+
+const moveLedgerEventsToUser = `-- name: MoveLedgerEventsToUser :execrows
+UPDATE ledger_events
+SET user_id = $1::bigint,
+    updated_at = NOW()
+WHERE user_id = $2::bigint
+`
+
+type MoveLedgerEventsToUserParams struct {
+	ToUserID   int64 `json:"to_user_id"`
+	FromUserID int64 `json:"from_user_id"`
+}
+
+func (q *Queries) MoveLedgerEventsToUser(ctx context.Context, arg MoveLedgerEventsToUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, moveLedgerEventsToUser, arg.ToUserID, arg.FromUserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -6,9 +6,207 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+type AccountRecovery struct {
+	ID int64 `json:"id"`
+	// user created by the replacement wallet; folded into target_user_id on completion
+	NewUserID      int64  `json:"new_user_id"`
+	TargetUserID   int64  `json:"target_user_id"`
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+	// 'pending' | 'completed' | 'cancelled'
+	Status string `json:"status"`
+	// earliest time the recovery can be completed
+	EligibleAt  time.Time    `json:"eligible_at"`
+	CompletedAt sql.NullTime `json:"completed_at"`
+	CancelledAt sql.NullTime `json:"cancelled_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// who held a verified link to a channel, and when; source of ledger attribution
+type ChannelOwnershipEpoch struct {
+	ID             int64  `json:"id"`
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+	// no FK: epochs move with user merges and outlive deleted users
+	UserID    int64     `json:"user_id"`
+	StartedAt time.Time `json:"started_at"`
+	// NULL for the current owner
+	EndedAt   sql.NullTime `json:"ended_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// per-channel payout overrides; only used while user_id owns the channel, otherwise payouts applies
+type ChannelPayout struct {
+	ID             int64          `json:"id"`
+	UserID         int64          `json:"user_id"`
+	Platform       string         `json:"platform"`
+	PlatformUserID string         `json:"platform_user_id"`
+	Chain          string         `json:"chain"`
+	Address        string         `json:"address"`
+	ProvenAt       sql.NullTime   `json:"proven_at"`
+	ProofMethod    sql.NullString `json:"proof_method"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	// ENS name the creator entered (e.g. alice.eth); address is what it resolved to then
+	EnsName sql.NullString `json:"ens_name"`
+}
+
+// cached public channel metadata; refreshed once older than CHANNEL_PROFILE_TTL
+type ChannelProfile struct {
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+	// lowercased, without the leading @; handles can move, so the newest row wins
+	Handle    sql.NullString `json:"handle"`
+	Title     string         `json:"title"`
+	AvatarURL string         `json:"avatar_url"`
+	// NULL when the channel hides it
+	SubscriberCount sql.NullInt64 `json:"subscriber_count"`
+	FetchedAt       time.Time     `json:"fetched_at"`
+}
+
+// social links removed by their owner; ledger history stays in ledger_events
+type ChannelRelease struct {
+	ID             int64  `json:"id"`
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+	// owner who unlinked; no FK so the record outlives merged users
+	UserID      int64 `json:"user_id"`
+	WasVerified bool  `json:"was_verified"`
+	// escrow balance for the channel at release time, flagged for the next verified owner
+	UnclaimedEscrowRaw  string        `json:"unclaimed_escrow_raw"`
+	NextOwnerID         sql.NullInt64 `json:"next_owner_id"`
+	NextOwnerNotifiedAt sql.NullTime  `json:"next_owner_notified_at"`
+	CreatedAt           time.Time     `json:"created_at"`
+}
+
+type ChannelSplit struct {
+	ID             int64  `json:"id"`
+	UserID         int64  `json:"user_id"`
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+	// [{"address": "0x...", "bps": 2500, "label": "editor"}, ...]; bps sum to 10000
+	Recipients json.RawMessage `json:"recipients"`
+	// 'pending' | 'active' | 'replaced' | 'removed' | 'cancelled'
+	Status string `json:"status"`
+	// when a pending split becomes active (PAYOUT_CHANGE_COOLDOWN)
+	EffectiveAt time.Time    `json:"effective_at"`
+	ActivatedAt sql.NullTime `json:"activated_at"`
+	// when the split was replaced, removed or cancelled
+	EndedAt   sql.NullTime `json:"ended_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// history of social_links ownership changes; no user FKs so it outlives merged users
+type ChannelTransfer struct {
+	ID             int64  `json:"id"`
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+	// previous link owner; NULL when the channel had no link
+	FromUserID sql.NullInt64 `json:"from_user_id"`
+	ToUserID   int64         `json:"to_user_id"`
+	// 'link' (unverified takeover) | 'oauth' | 'description_code'
+	Method string `json:"method"`
+	// 'pending' | 'disputed' | 'completed' | 'rejected'
+	Status string `json:"status"`
+	// end of the cooling-off period for pending transfers
+	EligibleAt    sql.NullTime   `json:"eligible_at"`
+	DisputedAt    sql.NullTime   `json:"disputed_at"`
+	DisputeReason sql.NullString `json:"dispute_reason"`
+	ResolvedAt    sql.NullTime   `json:"resolved_at"`
+	// admin users.id when an admin decided the transfer
+	ResolvedBy     sql.NullInt64  `json:"resolved_by"`
+	ResolutionNote sql.NullString `json:"resolution_note"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// claimant OAuth refresh token held until the transfer completes
+type ChannelTransferCredential struct {
+	TransferID int64 `json:"transfer_id"`
+	// sealed like oauth_credentials.refresh_token_enc
+	RefreshTokenEnc []byte    `json:"refresh_token_enc"`
+	Scope           string    `json:"scope"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type ClaimRecord struct {
+	ID             int64  `json:"id"`
+	UserID         int64  `json:"user_id"`
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+	PayoutAddress  string `json:"payout_address"`
+	// amount the escrow paid out
+	EscrowBalanceRaw string `json:"escrow_balance_raw"`
+	// [{"address": "0x...", "amount_raw": "..."}]: what each collaborator is owed from the claimed balance
+	Allocations json.RawMessage `json:"allocations"`
+	CreatedAt   time.Time       `json:"created_at"`
+	// the WITHDRAW ledger event of the executed claim
+	LedgerEventID int64 `json:"ledger_event_id"`
+}
+
+// verifier-signed claims submitted to the escrow from the relayer hot wallet, which pays the gas
+type ClaimRelay struct {
+	ID             int64     `json:"id"`
+	UserID         int64     `json:"user_id"`
+	Platform       string    `json:"platform"`
+	PlatformUserID string    `json:"platform_user_id"`
+	PayoutAddress  string    `json:"payout_address"`
+	Expiry         time.Time `json:"expiry"`
+	// nonce of the signed claim (bytes32 hex); each signature is relayed once
+	ClaimNonce     string `json:"claim_nonce"`
+	Signature      string `json:"signature"`
+	RelayerAddress string `json:"relayer_address"`
+	// 'queued' | 'submitted' | 'confirmed' | 'failed' | 'expired'
+	Status string `json:"status"`
+	// account nonce of the hot wallet transaction; kept across fee replacements
+	TxNonce  sql.NullInt64 `json:"tx_nonce"`
+	GasLimit sql.NullInt64 `json:"gas_limit"`
+	// latest broadcast attempt, or the mined one once a receipt is seen
+	TxHash sql.NullString `json:"tx_hash"`
+	// when the latest attempt was broadcast (replaced after RELAYER_REPLACE_AFTER)
+	SubmittedAt sql.NullTime   `json:"submitted_at"`
+	BlockNumber sql.NullInt64  `json:"block_number"`
+	GasUsed     sql.NullInt64  `json:"gas_used"`
+	Error       sql.NullString `json:"error"`
+	FinishedAt  sql.NullTime   `json:"finished_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// every broadcast of a relayed claim; any of them may be the one that gets mined
+type ClaimRelayTx struct {
+	ID        int64     `json:"id"`
+	RelayID   int64     `json:"relay_id"`
+	TxHash    string    `json:"tx_hash"`
+	TxNonce   int64     `json:"tx_nonce"`
+	GasTipCap string    `json:"gas_tip_cap"`
+	GasFeeCap string    `json:"gas_fee_cap"`
+	CreatedAt time.Time `json:"created_at"`
+	// 'claim' | 'cancel' (0-value self-transfer taking the nonce of an expired claim)
+	Kind string `json:"kind"`
+	// whether the node took the broadcast; replacements bump from the latest accepted attempt
+	Accepted bool `json:"accepted"`
+}
+
+// last seen forward record of ENS names used as payouts; a change alerts the creator
+type EnsName struct {
+	Name string `json:"name"`
+	// lowercased 0x...; NULL when the name stopped resolving
+	Address   sql.NullString `json:"address"`
+	CheckedAt time.Time      `json:"checked_at"`
+}
+
+// cached ENS primary names of payout addresses; refreshed once older than ENS_CHECK_INTERVAL
+type EnsReverseName struct {
+	Address string `json:"address"`
+	// NULL when the address has no primary name, or it does not resolve back to the address
+	Name      sql.NullString `json:"name"`
+	CheckedAt time.Time      `json:"checked_at"`
+}
+
 type Identity struct {
 	ID     int64 `json:"id"`
 	UserID int64 `json:"user_id"`
@@ -18,13 +216,24 @@ type Identity struct {
 	ProviderUserID string    `json:"provider_user_id"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+	// google: verified email at the last sign-in or link, used for out-of-band security notices; NULL otherwise
+	Email sql.NullString `json:"email"`
+}
+
+// how a tip divides under the split active at its block time; tips without rows belong wholly to the owner
+type LedgerAllocation struct {
+	LedgerEventID int64  `json:"ledger_event_id"`
+	Address       string `json:"address"`
+	SplitID       int64  `json:"split_id"`
+	Bps           int32  `json:"bps"`
+	AmountRaw     string `json:"amount_raw"`
 }
 
 type LedgerEvent struct {
 	ID int64 `json:"id"`
-	// 'youtube'
+	// 'youtube' | 'twitch' | 'x' | 'github' | 'kick'
 	Platform string `json:"platform"`
-	// channelId
+	// platform-normalized id (see social_links.platform_user_id)
 	PlatformUserID string `json:"platform_user_id"`
 	// nullable until claimed
 	UserID sql.NullInt64 `json:"user_id"`
@@ -49,6 +258,42 @@ type LoginNonce struct {
 	Message   string    `json:"message"`
 }
 
+type Notification struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+	// machine-readable type, e.g. 'account_recovery'
+	Kind      string       `json:"kind"`
+	Body      string       `json:"body"`
+	ReadAt    sql.NullTime `json:"read_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type OauthCredential struct {
+	ID             int64  `json:"id"`
+	UserID         int64  `json:"user_id"`
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+	// AES-256-GCM (nonce || ciphertext) under OAUTH_TOKEN_ENCRYPTION_KEY
+	RefreshTokenEnc []byte    `json:"refresh_token_enc"`
+	Scope           string    `json:"scope"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// pending authorization-code flows; a row is consumed by the callback
+type OauthState struct {
+	State          string `json:"state"`
+	UserID         int64  `json:"user_id"`
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+	// PKCE verifier; only its S256 challenge leaves the server
+	CodeVerifier string `json:"code_verifier"`
+	// 'verify' (link the channel) | 'recovery' (start an account recovery)
+	Purpose   string    `json:"purpose"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Payout struct {
 	ID     int64 `json:"id"`
 	UserID int64 `json:"user_id"`
@@ -58,18 +303,72 @@ type Payout struct {
 	Address   string    `json:"address"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// when the address signed a payout challenge; NULL for addresses registered before proofs were required
+	ProvenAt sql.NullTime `json:"proven_at"`
+	// 'personal_sign' (EOA) | 'eip1271' (contract wallet)
+	ProofMethod sql.NullString `json:"proof_method"`
+	// ENS name the creator entered (e.g. alice.eth); address is what it resolved to then
+	EnsName sql.NullString `json:"ens_name"`
+}
+
+type PayoutHistory struct {
+	ID     int64  `json:"id"`
+	UserID int64  `json:"user_id"`
+	Chain  string `json:"chain"`
+	// address in use when the change was requested; NULL for the first registration
+	OldAddress sql.NullString `json:"old_address"`
+	// address in use after the change; for a removed override the user default, empty when there is none
+	NewAddress string `json:"new_address"`
+	// how new_address proved control (see payouts.proof_method)
+	ProofMethod sql.NullString `json:"proof_method"`
+	// 'pending' | 'applied' | 'cancelled'
+	Status string `json:"status"`
+	// when a pending change replaces the payout address; resolve keeps old_address until then
+	EffectiveAt time.Time    `json:"effective_at"`
+	AppliedAt   sql.NullTime `json:"applied_at"`
+	CancelledAt sql.NullTime `json:"cancelled_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	// set (with platform_user_id) for a channel override; NULL for the user default
+	Platform       sql.NullString `json:"platform"`
+	PlatformUserID sql.NullString `json:"platform_user_id"`
+	// ENS name new_address was resolved from, if any
+	NewEnsName sql.NullString `json:"new_ens_name"`
 }
 
 type SocialLink struct {
 	ID     int64 `json:"id"`
 	UserID int64 `json:"user_id"`
-	// 'youtube'
+	// 'youtube' | 'twitch' | 'x' | 'github' | 'kick'
 	Platform string `json:"platform"`
-	// YouTube channelId
+	// platform-normalized id: YouTube channelId, otherwise lowercased login/handle/slug
 	PlatformUserID string       `json:"platform_user_id"`
 	VerifiedAt     sql.NullTime `json:"verified_at"`
 	CreatedAt      time.Time    `json:"created_at"`
 	UpdatedAt      time.Time    `json:"updated_at"`
+	// 'oauth' | 'description_code'; NULL while unverified
+	VerificationMethod sql.NullString `json:"verification_method"`
+	// last ownership (re)check attempt, successful or not
+	LastCheckedAt sql.NullTime `json:"last_checked_at"`
+	// first failed re-check since the last success; the grace period runs from here
+	CheckFailedAt sql.NullTime `json:"check_failed_at"`
+	// verification lapsed; resolve falls back to escrow until re-verified
+	StaleAt sql.NullTime `json:"stale_at"`
+}
+
+// one-time wallet re-authentication for sensitive actions; valid until expires_at (STEP_UP_TTL)
+type StepUpChallenge struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+	// wallet identity of user_id that must sign message (lowercased)
+	Address string `json:"address"`
+	// 'payout_change' | 'claim' | 'transfer_complete'
+	Action string `json:"action"`
+	Nonce  string `json:"nonce"`
+	// exact text to personal_sign; binds the action parameters
+	Message   string       `json:"message"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
 }
 
 type User struct {
@@ -77,3 +376,39 @@ type User struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+type UserMerge struct {
+	ID              int64 `json:"id"`
+	SurvivingUserID int64 `json:"surviving_user_id"`
+	// users.id that was folded in and deleted
+	MergedUserID int64 `json:"merged_user_id"`
+	// wallet that signed the merge message, or recovery:<platform>:<id> for account recovery
+	ProofAddress      string `json:"proof_address"`
+	IdentitiesMoved   int64  `json:"identities_moved"`
+	SocialLinksMoved  int64  `json:"social_links_moved"`
+	PayoutsMoved      int64  `json:"payouts_moved"`
+	LedgerEventsMoved int64  `json:"ledger_events_moved"`
+	// payout rows dropped while resolving (user_id, chain) conflicts
+	DiscardedPayouts json.RawMessage `json:"discarded_payouts"`
+	CreatedAt        time.Time       `json:"created_at"`
+}
+
+type VerificationCode struct {
+	ID             int64  `json:"id"`
+	UserID         int64  `json:"user_id"`
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+	// one-time code the creator puts in their channel description
+	Code      string       `json:"code"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// which channel uploaded a video; a video never changes channel, so rows are kept
+type VideoChannel struct {
+	Platform       string    `json:"platform"`
+	VideoID        string    `json:"video_id"`
+	PlatformUserID string    `json:"platform_user_id"`
+	FetchedAt      time.Time `json:"fetched_at"`
+}
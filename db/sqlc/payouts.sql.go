@@ -7,16 +7,268 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/lib/pq"
 )
 
+const deletePayout = `-- name: DeletePayout :exec
+DELETE FROM payouts
+WHERE id = $1
+`
+
+func (q *Queries) DeletePayout(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePayout, id)
+	return err
+}
+
+const getPayoutForChain = `-- name: GetPayoutForChain :one
+SELECT id, user_id, chain, address, created_at, updated_at, proven_at, proof_method, ens_name
+FROM payouts
+WHERE user_id = $1
+  AND chain = $2
+LIMIT 1
+`
+
+type GetPayoutForChainParams struct {
+	UserID int64  `json:"user_id"`
+	Chain  string `json:"chain"`
+}
+
+func (q *Queries) GetPayoutForChain(ctx context.Context, arg GetPayoutForChainParams) (Payout, error) {
+	row := q.db.QueryRowContext(ctx, getPayoutForChain, arg.UserID, arg.Chain)
+	var i Payout
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Chain,
+		&i.Address,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProvenAt,
+		&i.ProofMethod,
+		&i.EnsName,
+	)
+	return i, err
+}
+
+const isProvenPayoutAddress = `-- name: IsProvenPayoutAddress :one
+SELECT (EXISTS (
+  SELECT 1
+  FROM payouts
+  WHERE payouts.user_id = $1
+    AND payouts.address = $2
+    AND payouts.proven_at IS NOT NULL
+) OR EXISTS (
+  SELECT 1
+  FROM channel_payouts
+  WHERE channel_payouts.user_id = $1
+    AND channel_payouts.platform = $3
+    AND channel_payouts.platform_user_id = $4
+    AND channel_payouts.address = $2
+    AND channel_payouts.proven_at IS NOT NULL
+))::boolean AS proven
+`
+
+type IsProvenPayoutAddressParams struct {
+	UserID         int64  `json:"user_id"`
+	Address        string `json:"address"`
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+}
+
+func (q *Queries) IsProvenPayoutAddress(ctx context.Context, arg IsProvenPayoutAddressParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isProvenPayoutAddress,
+		arg.UserID,
+		arg.Address,
+		arg.Platform,
+		arg.PlatformUserID,
+	)
+	var proven bool
+	err := row.Scan(&proven)
+	return proven, err
+}
+
+const listChannelsByPayoutAddress = `-- name: ListChannelsByPayoutAddress :many
+SELECT sl.platform, sl.platform_user_id
+FROM social_links sl
+LEFT JOIN channel_payouts cp
+  ON cp.user_id = sl.user_id
+ AND cp.platform = sl.platform
+ AND cp.platform_user_id = sl.platform_user_id
+ AND cp.chain = $1
+LEFT JOIN payouts p
+  ON p.user_id = sl.user_id
+ AND p.chain = $1
+WHERE COALESCE(cp.address, p.address) = $2
+`
+
+type ListChannelsByPayoutAddressParams struct {
+	Chain   string `json:"chain"`
+	Address string `json:"address"`
+}
+
+type ListChannelsByPayoutAddressRow struct {
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+}
+
+func (q *Queries) ListChannelsByPayoutAddress(ctx context.Context, arg ListChannelsByPayoutAddressParams) ([]ListChannelsByPayoutAddressRow, error) {
+	rows, err := q.db.QueryContext(ctx, listChannelsByPayoutAddress, arg.Chain, arg.Address)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListChannelsByPayoutAddressRow{}
+	for rows.Next() {
+		var i ListChannelsByPayoutAddressRow
+		if err := rows.Scan(&i.Platform, &i.PlatformUserID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPayoutsByENSName = `-- name: ListPayoutsByENSName :many
+SELECT p.user_id, p.chain, NULL::varchar AS platform, NULL::varchar AS platform_user_id, p.address
+FROM payouts p
+WHERE p.ens_name = $1
+UNION ALL
+SELECT cp.user_id, cp.chain, cp.platform, cp.platform_user_id, cp.address
+FROM channel_payouts cp
+WHERE cp.ens_name = $1
+`
+
+type ListPayoutsByENSNameRow struct {
+	UserID         int64          `json:"user_id"`
+	Chain          string         `json:"chain"`
+	Platform       sql.NullString `json:"platform"`
+	PlatformUserID sql.NullString `json:"platform_user_id"`
+	Address        string         `json:"address"`
+}
+
+func (q *Queries) ListPayoutsByENSName(ctx context.Context, ensName sql.NullString) ([]ListPayoutsByENSNameRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPayoutsByENSName, ensName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPayoutsByENSNameRow{}
+	for rows.Next() {
+		var i ListPayoutsByENSNameRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Chain,
+			&i.Platform,
+			&i.PlatformUserID,
+			&i.Address,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPayoutsByUser = `-- name: ListPayoutsByUser :many
+SELECT id, user_id, chain, address, created_at, updated_at, proven_at, proof_method, ens_name
+FROM payouts
+WHERE user_id = $1
+ORDER BY chain
+`
+
+func (q *Queries) ListPayoutsByUser(ctx context.Context, userID int64) ([]Payout, error) {
+	rows, err := q.db.QueryContext(ctx, listPayoutsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Payout{}
+	for rows.Next() {
+		var i Payout
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Chain,
+			&i.Address,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ProvenAt,
+			&i.ProofMethod,
+			&i.EnsName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const movePayoutsToUser = `-- name: MovePayoutsToUser :execrows
+UPDATE payouts
+SET user_id = $1,
+    updated_at = NOW()
+WHERE user_id = $2
+`
+
+type MovePayoutsToUserParams struct {
+	ToUserID   int64 `json:"to_user_id"`
+	FromUserID int64 `json:"from_user_id"`
+}
+
+func (q *Queries) MovePayoutsToUser(ctx context.Context, arg MovePayoutsToUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, movePayoutsToUser, arg.ToUserID, arg.FromUserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const resolvePayoutByChannelID = `-- name: ResolvePayoutByChannelID :one
-SELECT p.address
+SELECT COALESCE(cp.address, p.address, '')::varchar AS address,
+  COALESCE(cs.recipients, '[]'::jsonb)::jsonb AS split_recipients,
+  COALESCE(rn.name, '')::varchar AS name
 FROM social_links sl
-JOIN payouts p ON p.user_id = sl.user_id
+LEFT JOIN channel_payouts cp
+  ON cp.user_id = sl.user_id
+ AND cp.platform = sl.platform
+ AND cp.platform_user_id = sl.platform_user_id
+ AND cp.chain = $3
+LEFT JOIN payouts p
+  ON p.user_id = sl.user_id
+ AND p.chain = $3
+LEFT JOIN channel_splits cs
+  ON cs.user_id = sl.user_id
+ AND cs.platform = sl.platform
+ AND cs.platform_user_id = sl.platform_user_id
+ AND cs.status = 'active'
+LEFT JOIN ens_reverse_names rn
+  ON rn.address = COALESCE(cp.address, p.address)
 WHERE sl.platform = $1
   AND sl.platform_user_id = $2
   AND sl.verified_at IS NOT NULL
-  AND p.chain = $3
+  AND sl.stale_at IS NULL
+  AND (COALESCE(cp.address, p.address) IS NOT NULL OR cs.id IS NOT NULL)
 LIMIT 1
 `
 
@@ -26,33 +278,120 @@ type ResolvePayoutByChannelIDParams struct {
 	Chain          string `json:"chain"`
 }
 
-func (q *Queries) ResolvePayoutByChannelID(ctx context.Context, arg ResolvePayoutByChannelIDParams) (string, error) {
+type ResolvePayoutByChannelIDRow struct {
+	Address         string          `json:"address"`
+	SplitRecipients json.RawMessage `json:"split_recipients"`
+	Name            string          `json:"name"`
+}
+
+func (q *Queries) ResolvePayoutByChannelID(ctx context.Context, arg ResolvePayoutByChannelIDParams) (ResolvePayoutByChannelIDRow, error) {
 	row := q.db.QueryRowContext(ctx, resolvePayoutByChannelID, arg.Platform, arg.PlatformUserID, arg.Chain)
-	var address string
-	err := row.Scan(&address)
-	return address, err
+	var i ResolvePayoutByChannelIDRow
+	err := row.Scan(&i.Address, &i.SplitRecipients, &i.Name)
+	return i, err
+}
+
+const resolvePayoutsByChannelIDs = `-- name: ResolvePayoutsByChannelIDs :many
+SELECT sl.platform_user_id, COALESCE(cp.address, p.address, '')::varchar AS address,
+  COALESCE(cs.recipients, '[]'::jsonb)::jsonb AS split_recipients,
+  COALESCE(rn.name, '')::varchar AS name
+FROM social_links sl
+LEFT JOIN channel_payouts cp
+  ON cp.user_id = sl.user_id
+ AND cp.platform = sl.platform
+ AND cp.platform_user_id = sl.platform_user_id
+ AND cp.chain = $1
+LEFT JOIN payouts p
+  ON p.user_id = sl.user_id
+ AND p.chain = $1
+LEFT JOIN channel_splits cs
+  ON cs.user_id = sl.user_id
+ AND cs.platform = sl.platform
+ AND cs.platform_user_id = sl.platform_user_id
+ AND cs.status = 'active'
+LEFT JOIN ens_reverse_names rn
+  ON rn.address = COALESCE(cp.address, p.address)
+WHERE sl.platform = $2
+  AND sl.platform_user_id = ANY($3::varchar[])
+  AND sl.verified_at IS NOT NULL
+  AND sl.stale_at IS NULL
+  AND (COALESCE(cp.address, p.address) IS NOT NULL OR cs.id IS NOT NULL)
+`
+
+type ResolvePayoutsByChannelIDsParams struct {
+	Chain           string   `json:"chain"`
+	Platform        string   `json:"platform"`
+	PlatformUserIds []string `json:"platform_user_ids"`
+}
+
+type ResolvePayoutsByChannelIDsRow struct {
+	PlatformUserID  string          `json:"platform_user_id"`
+	Address         string          `json:"address"`
+	SplitRecipients json.RawMessage `json:"split_recipients"`
+	Name            string          `json:"name"`
+}
+
+func (q *Queries) ResolvePayoutsByChannelIDs(ctx context.Context, arg ResolvePayoutsByChannelIDsParams) ([]ResolvePayoutsByChannelIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, resolvePayoutsByChannelIDs, arg.Chain, arg.Platform, pq.Array(arg.PlatformUserIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ResolvePayoutsByChannelIDsRow{}
+	for rows.Next() {
+		var i ResolvePayoutsByChannelIDsRow
+		if err := rows.Scan(
+			&i.PlatformUserID,
+			&i.Address,
+			&i.SplitRecipients,
+			&i.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const upsertPayout = `-- name: UpsertPayout :one
 INSERT INTO payouts (
-  user_id, chain, address, created_at, updated_at
+  user_id, chain, address, proven_at, proof_method, ens_name, created_at, updated_at
 ) VALUES (
-  $1, $2, $3, NOW(), NOW()
+  $1, $2, $3, $4, $5, $6, NOW(), NOW()
 )
 ON CONFLICT (user_id, chain) DO UPDATE
 SET address = EXCLUDED.address,
+    proven_at = EXCLUDED.proven_at,
+    proof_method = EXCLUDED.proof_method,
+    ens_name = EXCLUDED.ens_name,
     updated_at = NOW()
-RETURNING id, user_id, chain, address, created_at, updated_at
+RETURNING id, user_id, chain, address, created_at, updated_at, proven_at, proof_method, ens_name
 `
 
 type UpsertPayoutParams struct {
-	UserID  int64  `json:"user_id"`
-	Chain   string `json:"chain"`
-	Address string `json:"address"`
+	UserID      int64          `json:"user_id"`
+	Chain       string         `json:"chain"`
+	Address     string         `json:"address"`
+	ProvenAt    sql.NullTime   `json:"proven_at"`
+	ProofMethod sql.NullString `json:"proof_method"`
+	EnsName     sql.NullString `json:"ens_name"`
 }
 
 func (q *Queries) UpsertPayout(ctx context.Context, arg UpsertPayoutParams) (Payout, error) {
-	row := q.db.QueryRowContext(ctx, upsertPayout, arg.UserID, arg.Chain, arg.Address)
+	row := q.db.QueryRowContext(ctx, upsertPayout,
+		arg.UserID,
+		arg.Chain,
+		arg.Address,
+		arg.ProvenAt,
+		arg.ProofMethod,
+		arg.EnsName,
+	)
 	var i Payout
 	err := row.Scan(
 		&i.ID,
@@ -61,6 +400,9 @@ func (q *Queries) UpsertPayout(ctx context.Context, arg UpsertPayoutParams) (Pay
 		&i.Address,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProvenAt,
+		&i.ProofMethod,
+		&i.EnsName,
 	)
 	return i, err
 }
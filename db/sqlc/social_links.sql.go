@@ -8,22 +8,45 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
+const countPendingSocialLinksForUser = `-- name: CountPendingSocialLinksForUser :one
+SELECT COUNT(*)::bigint AS pending
+FROM social_links
+WHERE user_id = $1
+  AND verified_at IS NULL
+  AND updated_at >= $2
+`
+
+type CountPendingSocialLinksForUserParams struct {
+	UserID        int64     `json:"user_id"`
+	ExpiredBefore time.Time `json:"expired_before"`
+}
+
+func (q *Queries) CountPendingSocialLinksForUser(ctx context.Context, arg CountPendingSocialLinksForUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPendingSocialLinksForUser, arg.UserID, arg.ExpiredBefore)
+	var pending int64
+	err := row.Scan(&pending)
+	return pending, err
+}
+
 const createSocialLink = `-- name: CreateSocialLink :one
 INSERT INTO social_links (
-  user_id, platform, platform_user_id, verified_at, created_at, updated_at
+  user_id, platform, platform_user_id, verified_at, verification_method, last_checked_at, created_at, updated_at
 ) VALUES (
-  $1, $2, $3, $4, NOW(), NOW()
+  $1, $2, $3, $4, $5, $4, NOW(), NOW()
 )
-RETURNING id, user_id, platform, platform_user_id, verified_at, created_at, updated_at
+RETURNING id, user_id, platform, platform_user_id, verified_at, created_at, updated_at, verification_method,
+          last_checked_at, check_failed_at, stale_at
 `
 
 type CreateSocialLinkParams struct {
-	UserID         int64        `json:"user_id"`
-	Platform       string       `json:"platform"`
-	PlatformUserID string       `json:"platform_user_id"`
-	VerifiedAt     sql.NullTime `json:"verified_at"`
+	UserID             int64          `json:"user_id"`
+	Platform           string         `json:"platform"`
+	PlatformUserID     string         `json:"platform_user_id"`
+	VerifiedAt         sql.NullTime   `json:"verified_at"`
+	VerificationMethod sql.NullString `json:"verification_method"`
 }
 
 func (q *Queries) CreateSocialLink(ctx context.Context, arg CreateSocialLinkParams) (SocialLink, error) {
@@ -32,6 +55,7 @@ func (q *Queries) CreateSocialLink(ctx context.Context, arg CreateSocialLinkPara
 		arg.Platform,
 		arg.PlatformUserID,
 		arg.VerifiedAt,
+		arg.VerificationMethod,
 	)
 	var i SocialLink
 	err := row.Scan(
@@ -42,12 +66,63 @@ func (q *Queries) CreateSocialLink(ctx context.Context, arg CreateSocialLinkPara
 		&i.VerifiedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.VerificationMethod,
+		&i.LastCheckedAt,
+		&i.CheckFailedAt,
+		&i.StaleAt,
 	)
 	return i, err
 }
 
+const deleteExpiredUnverifiedSocialLink = `-- name: DeleteExpiredUnverifiedSocialLink :execrows
+DELETE FROM social_links
+WHERE platform = $1
+  AND platform_user_id = $2
+  AND verified_at IS NULL
+  AND updated_at < $3
+`
+
+type DeleteExpiredUnverifiedSocialLinkParams struct {
+	Platform       string    `json:"platform"`
+	PlatformUserID string    `json:"platform_user_id"`
+	ExpiredBefore  time.Time `json:"expired_before"`
+}
+
+func (q *Queries) DeleteExpiredUnverifiedSocialLink(ctx context.Context, arg DeleteExpiredUnverifiedSocialLinkParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredUnverifiedSocialLink, arg.Platform, arg.PlatformUserID, arg.ExpiredBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredUnverifiedSocialLinks = `-- name: DeleteExpiredUnverifiedSocialLinks :execrows
+DELETE FROM social_links
+WHERE verified_at IS NULL
+  AND updated_at < $1
+`
+
+func (q *Queries) DeleteExpiredUnverifiedSocialLinks(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredUnverifiedSocialLinks, expiredBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteSocialLink = `-- name: DeleteSocialLink :exec
+DELETE FROM social_links
+WHERE id = $1
+`
+
+func (q *Queries) DeleteSocialLink(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSocialLink, id)
+	return err
+}
+
 const getSocialLinkByPlatformUser = `-- name: GetSocialLinkByPlatformUser :one
-SELECT id, user_id, platform, platform_user_id, verified_at, created_at, updated_at
+SELECT id, user_id, platform, platform_user_id, verified_at, created_at, updated_at, verification_method,
+       last_checked_at, check_failed_at, stale_at
 FROM social_links
 WHERE platform = $1 AND platform_user_id = $2
 LIMIT 1
@@ -69,28 +144,164 @@ func (q *Queries) GetSocialLinkByPlatformUser(ctx context.Context, arg GetSocial
 		&i.VerifiedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.VerificationMethod,
+		&i.LastCheckedAt,
+		&i.CheckFailedAt,
+		&i.StaleAt,
 	)
 	return i, err
 }
 
+const listSocialLinksDueForRecheck = `-- name: ListSocialLinksDueForRecheck :many
+SELECT id, user_id, platform, platform_user_id, verified_at, created_at, updated_at, verification_method,
+       last_checked_at, check_failed_at, stale_at
+FROM social_links
+WHERE verified_at IS NOT NULL
+  AND stale_at IS NULL
+  AND (check_failed_at IS NOT NULL OR COALESCE(last_checked_at, verified_at) < $1::timestamptz)
+ORDER BY COALESCE(last_checked_at, verified_at)
+LIMIT $2
+`
+
+type ListSocialLinksDueForRecheckParams struct {
+	CheckedBefore time.Time `json:"checked_before"`
+	MaxLinks      int32     `json:"max_links"`
+}
+
+func (q *Queries) ListSocialLinksDueForRecheck(ctx context.Context, arg ListSocialLinksDueForRecheckParams) ([]SocialLink, error) {
+	rows, err := q.db.QueryContext(ctx, listSocialLinksDueForRecheck, arg.CheckedBefore, arg.MaxLinks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SocialLink{}
+	for rows.Next() {
+		var i SocialLink
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Platform,
+			&i.PlatformUserID,
+			&i.VerifiedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.VerificationMethod,
+			&i.LastCheckedAt,
+			&i.CheckFailedAt,
+			&i.StaleAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markSocialLinkCheckFailed = `-- name: MarkSocialLinkCheckFailed :exec
+UPDATE social_links
+SET last_checked_at = NOW(),
+    check_failed_at = COALESCE(check_failed_at, NOW()),
+    updated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkSocialLinkCheckFailed(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markSocialLinkCheckFailed, id)
+	return err
+}
+
+const markSocialLinkCheckSkipped = `-- name: MarkSocialLinkCheckSkipped :exec
+UPDATE social_links
+SET last_checked_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkSocialLinkCheckSkipped(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markSocialLinkCheckSkipped, id)
+	return err
+}
+
+const markSocialLinkRechecked = `-- name: MarkSocialLinkRechecked :exec
+UPDATE social_links
+SET last_checked_at = NOW(),
+    check_failed_at = NULL,
+    stale_at = NULL,
+    updated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkSocialLinkRechecked(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markSocialLinkRechecked, id)
+	return err
+}
+
+const markSocialLinkStale = `-- name: MarkSocialLinkStale :exec
+UPDATE social_links
+SET stale_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND stale_at IS NULL
+`
+
+func (q *Queries) MarkSocialLinkStale(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markSocialLinkStale, id)
+	return err
+}
+
+const moveSocialLinksToUser = `-- name: MoveSocialLinksToUser :execrows
+UPDATE social_links
+SET user_id = $1,
+    updated_at = NOW()
+WHERE user_id = $2
+`
+
+type MoveSocialLinksToUserParams struct {
+	ToUserID   int64 `json:"to_user_id"`
+	FromUserID int64 `json:"from_user_id"`
+}
+
+func (q *Queries) MoveSocialLinksToUser(ctx context.Context, arg MoveSocialLinksToUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, moveSocialLinksToUser, arg.ToUserID, arg.FromUserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const transferSocialLinkToUser = `-- name: TransferSocialLinkToUser :one
 UPDATE social_links
 SET
   user_id = $2,
   verified_at = $3,
+  verification_method = $4,
+  last_checked_at = $3,
+  check_failed_at = NULL,
+  stale_at = NULL,
   updated_at = NOW()
 WHERE id = $1
-RETURNING id, user_id, platform, platform_user_id, verified_at, created_at, updated_at
+RETURNING id, user_id, platform, platform_user_id, verified_at, created_at, updated_at, verification_method,
+          last_checked_at, check_failed_at, stale_at
 `
 
 type TransferSocialLinkToUserParams struct {
-	ID         int64        `json:"id"`
-	UserID     int64        `json:"user_id"`
-	VerifiedAt sql.NullTime `json:"verified_at"`
+	ID                 int64          `json:"id"`
+	UserID             int64          `json:"user_id"`
+	VerifiedAt         sql.NullTime   `json:"verified_at"`
+	VerificationMethod sql.NullString `json:"verification_method"`
 }
 
 func (q *Queries) TransferSocialLinkToUser(ctx context.Context, arg TransferSocialLinkToUserParams) (SocialLink, error) {
-	row := q.db.QueryRowContext(ctx, transferSocialLinkToUser, arg.ID, arg.UserID, arg.VerifiedAt)
+	row := q.db.QueryRowContext(ctx, transferSocialLinkToUser,
+		arg.ID,
+		arg.UserID,
+		arg.VerifiedAt,
+		arg.VerificationMethod,
+	)
 	var i SocialLink
 	err := row.Scan(
 		&i.ID,
@@ -100,6 +311,10 @@ func (q *Queries) TransferSocialLinkToUser(ctx context.Context, arg TransferSoci
 		&i.VerifiedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.VerificationMethod,
+		&i.LastCheckedAt,
+		&i.CheckFailedAt,
+		&i.StaleAt,
 	)
 	return i, err
 }
@@ -108,18 +323,24 @@ const updateSocialLinkVerifiedAt = `-- name: UpdateSocialLinkVerifiedAt :one
 UPDATE social_links
 SET
   verified_at = $2,
+  verification_method = $3,
+  last_checked_at = $2,
+  check_failed_at = NULL,
+  stale_at = NULL,
   updated_at = NOW()
 WHERE id = $1
-RETURNING id, user_id, platform, platform_user_id, verified_at, created_at, updated_at
+RETURNING id, user_id, platform, platform_user_id, verified_at, created_at, updated_at, verification_method,
+          last_checked_at, check_failed_at, stale_at
 `
 
 type UpdateSocialLinkVerifiedAtParams struct {
-	ID         int64        `json:"id"`
-	VerifiedAt sql.NullTime `json:"verified_at"`
+	ID                 int64          `json:"id"`
+	VerifiedAt         sql.NullTime   `json:"verified_at"`
+	VerificationMethod sql.NullString `json:"verification_method"`
 }
 
 func (q *Queries) UpdateSocialLinkVerifiedAt(ctx context.Context, arg UpdateSocialLinkVerifiedAtParams) (SocialLink, error) {
-	row := q.db.QueryRowContext(ctx, updateSocialLinkVerifiedAt, arg.ID, arg.VerifiedAt)
+	row := q.db.QueryRowContext(ctx, updateSocialLinkVerifiedAt, arg.ID, arg.VerifiedAt, arg.VerificationMethod)
 	var i SocialLink
 	err := row.Scan(
 		&i.ID,
@@ -129,6 +350,10 @@ func (q *Queries) UpdateSocialLinkVerifiedAt(ctx context.Context, arg UpdateSoci
 		&i.VerifiedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.VerificationMethod,
+		&i.LastCheckedAt,
+		&i.CheckFailedAt,
+		&i.StaleAt,
 	)
 	return i, err
 }
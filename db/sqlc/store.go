@@ -0,0 +1,1348 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+var (
+	ErrRecoveryNotOwned    = errors.New("recovery was started by another user")
+	ErrRecoveryNotPending  = errors.New("recovery is no longer pending")
+	ErrRecoveryNotEligible = errors.New("recovery waiting period has not elapsed")
+
+	ErrTransferNotOwned    = errors.New("transfer was requested by another user")
+	ErrTransferNotPending  = errors.New("transfer is not pending")
+	ErrTransferNotEligible = errors.New("transfer cooling-off period has not elapsed")
+
+	ErrLinkNotOwned = errors.New("channel is linked to another user")
+
+	ErrLastIdentity = errors.New("cannot remove the last identity on an account")
+
+	ErrRelayQuota = errors.New("claim relay quota reached")
+)
+
+// Store provides all queries plus multi-statement transactions.
+type Store struct {
+	*Queries
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{
+		db:      db,
+		Queries: New(db),
+	}
+}
+
+// execTx runs fn inside a database transaction and rolls back on error.
+func (store *Store) execTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q := New(tx)
+	err = fn(q)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// PayoutConflict policies for MergeUsersTx when both users have a payout on the same chain.
+const (
+	KeepSurvivorPayout = "survivor"
+	KeepMergedPayout   = "merged"
+)
+
+type MergeUsersTxParams struct {
+	SurvivingUserID int64  `json:"surviving_user_id"`
+	MergedUserID    int64  `json:"merged_user_id"`
+	ProofAddress    string `json:"proof_address"`
+	PayoutConflict  string `json:"payout_conflict"`
+	// PayoutCooldown delays a KeepMergedPayout replacement like any other
+	// payout change (see ChangePayoutTx).
+	PayoutCooldown time.Duration `json:"payout_cooldown"`
+}
+
+type MergeUsersTxResult struct {
+	Merge   UserMerge `json:"merge"`
+	Payouts []Payout  `json:"payouts"`
+	// PayoutChanges are the survivor's address changes made by KeepMergedPayout.
+	PayoutChanges []PayoutHistory `json:"payout_changes"`
+}
+
+// UnlinkIdentityTx deletes one of userID's identities unless it is the last.
+// The user row is locked first, so concurrent unlinks run one after the other
+// and cannot remove the last two identities together. sql.ErrNoRows means the
+// identity does not belong to userID.
+func (store *Store) UnlinkIdentityTx(ctx context.Context, userID, identityID int64) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if _, err := q.LockUser(ctx, userID); err != nil {
+			return err
+		}
+		deleted, err := q.DeleteIdentityForUser(ctx, DeleteIdentityForUserParams{
+			ID:     identityID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return sql.ErrNoRows
+		}
+		left, err := q.CountIdentitiesByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if left == 0 {
+			return ErrLastIdentity // rolls the delete back
+		}
+		return nil
+	})
+}
+
+// MergeUsersTx folds MergedUserID into SurvivingUserID: identities, social
+// links, payouts, notifications and ledger attribution move over, the merged
+// user row is deleted, and a user_merges audit row records what happened.
+// Open step-up challenges and description verification codes of the merged
+// user are discarded: both are short-lived proofs bound to that account, and
+// the survivor can request new ones.
+func (store *Store) MergeUsersTx(ctx context.Context, arg MergeUsersTxParams) (MergeUsersTxResult, error) {
+	var result MergeUsersTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = mergeUsers(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func mergeUsers(ctx context.Context, q *Queries, arg MergeUsersTxParams) (MergeUsersTxResult, error) {
+	var result MergeUsersTxResult
+
+	move := MoveIdentitiesToUserParams{ToUserID: arg.SurvivingUserID, FromUserID: arg.MergedUserID}
+
+	identitiesMoved, err := q.MoveIdentitiesToUser(ctx, move)
+	if err != nil {
+		return result, err
+	}
+
+	socialLinksMoved, err := q.MoveSocialLinksToUser(ctx, MoveSocialLinksToUserParams(move))
+	if err != nil {
+		return result, err
+	}
+
+	if err := q.MoveOAuthCredentialsToUser(ctx, MoveOAuthCredentialsToUserParams(move)); err != nil {
+		return result, err
+	}
+
+	if err := q.MoveOwnershipEpochsToUser(ctx, MoveOwnershipEpochsToUserParams(move)); err != nil {
+		return result, err
+	}
+
+	if err := q.MoveChannelTransfersToUser(ctx, MoveChannelTransfersToUserParams{
+		FromUserID: arg.MergedUserID,
+		ToUserID:   arg.SurvivingUserID,
+	}); err != nil {
+		return result, err
+	}
+
+	// Resolve (user_id, chain) conflicts before moving payouts over.
+	survivorPayouts, err := q.ListPayoutsByUser(ctx, arg.SurvivingUserID)
+	if err != nil {
+		return result, err
+	}
+	mergedPayouts, err := q.ListPayoutsByUser(ctx, arg.MergedUserID)
+	if err != nil {
+		return result, err
+	}
+
+	byChain := make(map[string]Payout, len(survivorPayouts))
+	for _, p := range survivorPayouts {
+		byChain[p.Chain] = p
+	}
+
+	discarded := []Payout{}
+	for _, mp := range mergedPayouts {
+		sp, conflict := byChain[mp.Chain]
+		if !conflict {
+			continue
+		}
+		if arg.PayoutConflict == KeepMergedPayout {
+			// Goes through payout_history and the cooldown, so a merge is
+			// not a way around either.
+			changed, err := changePayout(ctx, q, ChangePayoutTxParams{
+				UserID:      arg.SurvivingUserID,
+				Chain:       mp.Chain,
+				Address:     mp.Address,
+				ProofMethod: mp.ProofMethod.String,
+				ENSName:     mp.EnsName.String,
+				Cooldown:    arg.PayoutCooldown,
+			})
+			if err != nil {
+				return result, err
+			}
+			if changed.Change != nil {
+				result.PayoutChanges = append(result.PayoutChanges, *changed.Change)
+			}
+			discarded = append(discarded, sp)
+		} else {
+			discarded = append(discarded, mp)
+		}
+		if err := q.DeletePayout(ctx, mp.ID); err != nil {
+			return result, err
+		}
+	}
+
+	payoutsMoved, err := q.MovePayoutsToUser(ctx, MovePayoutsToUserParams(move))
+	if err != nil {
+		return result, err
+	}
+
+	// Pending address changes of the merged account are dropped, not carried
+	// over onto the survivor's payouts; its history is kept.
+	if _, err := q.CancelPendingPayoutChanges(ctx, CancelPendingPayoutChangesParams{UserID: arg.MergedUserID}); err != nil {
+		return result, err
+	}
+	if err := q.MovePayoutHistoryToUser(ctx, MovePayoutHistoryToUserParams(move)); err != nil {
+		return result, err
+	}
+	// An override the survivor already has for the same channel wins; the
+	// merged one is deleted with the user.
+	if err := q.MoveChannelPayoutsToUser(ctx, MoveChannelPayoutsToUserParams(move)); err != nil {
+		return result, err
+	}
+	if err := q.MoveChannelSplitsToUser(ctx, MoveChannelSplitsToUserParams(move)); err != nil {
+		return result, err
+	}
+	if err := q.MoveClaimRecordsToUser(ctx, MoveClaimRecordsToUserParams(move)); err != nil {
+		return result, err
+	}
+	// Relays in flight keep being tracked; deleting them would lose their nonces.
+	if err := q.MoveClaimRelaysToUser(ctx, MoveClaimRelaysToUserParams(move)); err != nil {
+		return result, err
+	}
+
+	if err := q.MoveNotificationsToUser(ctx, MoveNotificationsToUserParams(move)); err != nil {
+		return result, err
+	}
+	// Deleted explicitly rather than by the cascade, so dropping them is a
+	// choice made here and not a side effect of DeleteUser.
+	if err := q.DeleteStepUpChallengesForUser(ctx, arg.MergedUserID); err != nil {
+		return result, err
+	}
+	if err := q.DeleteVerificationCodesForUser(ctx, arg.MergedUserID); err != nil {
+		return result, err
+	}
+
+	ledgerEventsMoved, err := q.MoveLedgerEventsToUser(ctx, MoveLedgerEventsToUserParams(move))
+	if err != nil {
+		return result, err
+	}
+
+	if err := q.DeleteUser(ctx, arg.MergedUserID); err != nil {
+		return result, err
+	}
+
+	discardedJSON, err := json.Marshal(discarded)
+	if err != nil {
+		return result, err
+	}
+
+	result.Merge, err = q.CreateUserMerge(ctx, CreateUserMergeParams{
+		SurvivingUserID:   arg.SurvivingUserID,
+		MergedUserID:      arg.MergedUserID,
+		ProofAddress:      arg.ProofAddress,
+		IdentitiesMoved:   identitiesMoved,
+		SocialLinksMoved:  socialLinksMoved,
+		PayoutsMoved:      payoutsMoved,
+		LedgerEventsMoved: ledgerEventsMoved,
+		DiscardedPayouts:  discardedJSON,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Payouts, err = q.ListPayoutsByUser(ctx, arg.SurvivingUserID)
+	return result, err
+}
+
+type CompleteAccountRecoveryTxResult struct {
+	Recovery AccountRecovery    `json:"recovery"`
+	Merge    MergeUsersTxResult `json:"merge"`
+}
+
+// CompleteAccountRecoveryTx marks a pending, eligible recovery completed and
+// folds the replacement user (and its new wallet) into the recovered account.
+func (store *Store) CompleteAccountRecoveryTx(ctx context.Context, recoveryID, newUserID int64) (CompleteAccountRecoveryTxResult, error) {
+	var result CompleteAccountRecoveryTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		r, err := q.GetAccountRecoveryForUpdate(ctx, recoveryID)
+		if err != nil {
+			return err
+		}
+		if r.NewUserID != newUserID {
+			return ErrRecoveryNotOwned
+		}
+		if r.Status != "pending" {
+			return ErrRecoveryNotPending
+		}
+		if time.Now().Before(r.EligibleAt) {
+			return ErrRecoveryNotEligible
+		}
+
+		result.Recovery, err = q.CompleteAccountRecovery(ctx, recoveryID)
+		if err != nil {
+			return err
+		}
+
+		result.Merge, err = mergeUsers(ctx, q, MergeUsersTxParams{
+			SurvivingUserID: r.TargetUserID,
+			MergedUserID:    r.NewUserID,
+			ProofAddress:    fmt.Sprintf("recovery:%s:%s", r.Platform, r.PlatformUserID),
+			PayoutConflict:  KeepSurvivorPayout,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+type CompleteChannelTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	// ToUserID completes an undisputed transfer after the cooling-off period.
+	ToUserID int64 `json:"to_user_id"`
+	// AdminUserID approves a pending or disputed transfer at any time.
+	AdminUserID int64  `json:"admin_user_id"`
+	Note        string `json:"note"`
+}
+
+// CompleteChannelTransferTx moves the channel's link to the requesting user,
+// marks it verified with the transfer's method, closes the transfer and starts
+// the new owner's ownership epoch.
+func (store *Store) CompleteChannelTransferTx(ctx context.Context, arg CompleteChannelTransferTxParams) (ChannelTransfer, error) {
+	var result ChannelTransfer
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		t, err := q.GetChannelTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		if arg.AdminUserID == 0 {
+			if t.ToUserID != arg.ToUserID {
+				return ErrTransferNotOwned
+			}
+			if t.Status != "pending" {
+				return ErrTransferNotPending
+			}
+			if !t.EligibleAt.Valid || time.Now().Before(t.EligibleAt.Time) {
+				return ErrTransferNotEligible
+			}
+		} else if t.Status != "pending" && t.Status != "disputed" {
+			return ErrTransferNotPending
+		}
+
+		result, err = completeChannelTransfer(ctx, q, t, arg.AdminUserID, arg.Note)
+		return err
+	})
+
+	return result, err
+}
+
+// completeChannelTransfer gives the channel's link to t.ToUserID (creating it
+// if the previous owner already unlinked), stores the OAuth credential held on
+// the transfer and closes the transfer.
+func completeChannelTransfer(ctx context.Context, q *Queries, t ChannelTransfer, adminUserID int64, note string) (ChannelTransfer, error) {
+	verifiedAt := sql.NullTime{Time: time.Now(), Valid: true}
+	verifiedBy := sql.NullString{String: t.Method, Valid: true}
+
+	sl, err := q.GetSocialLinkByPlatformUser(ctx, GetSocialLinkByPlatformUserParams{
+		Platform:       t.Platform,
+		PlatformUserID: t.PlatformUserID,
+	})
+	switch {
+	case err == sql.ErrNoRows:
+		_, err = q.CreateSocialLink(ctx, CreateSocialLinkParams{
+			UserID:             t.ToUserID,
+			Platform:           t.Platform,
+			PlatformUserID:     t.PlatformUserID,
+			VerifiedAt:         verifiedAt,
+			VerificationMethod: verifiedBy,
+		})
+	case err == nil:
+		_, err = q.TransferSocialLinkToUser(ctx, TransferSocialLinkToUserParams{
+			ID:                 sl.ID,
+			UserID:             t.ToUserID,
+			VerifiedAt:         verifiedAt,
+			VerificationMethod: verifiedBy,
+		})
+	}
+	if err != nil {
+		return ChannelTransfer{}, err
+	}
+	if err := dropChannelPayouts(ctx, q, t.Platform, t.PlatformUserID); err != nil {
+		return ChannelTransfer{}, err
+	}
+	// The claimant's OAuth proof replaces the previous owner's credential.
+	cred, err := q.TakeChannelTransferCredential(ctx, t.ID)
+	switch {
+	case err == nil:
+		_, err = q.UpsertOAuthCredential(ctx, UpsertOAuthCredentialParams{
+			UserID:          t.ToUserID,
+			Platform:        t.Platform,
+			PlatformUserID:  t.PlatformUserID,
+			RefreshTokenEnc: cred.RefreshTokenEnc,
+			Scope:           cred.Scope,
+		})
+	case err == sql.ErrNoRows:
+		err = nil
+	}
+	if err != nil {
+		return ChannelTransfer{}, err
+	}
+
+	done, err := q.ResolveChannelTransfer(ctx, ResolveChannelTransferParams{
+		ID:             t.ID,
+		Status:         "completed",
+		ResolvedBy:     sql.NullInt64{Int64: adminUserID, Valid: adminUserID != 0},
+		ResolutionNote: sql.NullString{String: note, Valid: note != ""},
+	})
+	if err != nil {
+		return ChannelTransfer{}, err
+	}
+
+	return done, startOwnershipEpoch(ctx, q, t.Platform, t.PlatformUserID, t.ToUserID)
+}
+
+type ReleaseSocialLinkTxParams struct {
+	UserID         int64  `json:"user_id"`
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+}
+
+type ReleaseSocialLinkTxResult struct {
+	Release ChannelRelease `json:"release"`
+	// Transfer is set when an open transfer away from this user was completed
+	// because the channel was released.
+	Transfer *ChannelTransfer `json:"transfer,omitempty"`
+}
+
+// ReleaseSocialLinkTx removes a user's link to a channel. Ledger history is
+// kept; the ownership epoch ends, stored OAuth credentials are dropped and the
+// channel's unclaimed escrow is recorded for the next verified owner. An open
+// transfer requested by someone else completes right away.
+func (store *Store) ReleaseSocialLinkTx(ctx context.Context, arg ReleaseSocialLinkTxParams) (ReleaseSocialLinkTxResult, error) {
+	var result ReleaseSocialLinkTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		key := GetSocialLinkByPlatformUserParams{Platform: arg.Platform, PlatformUserID: arg.PlatformUserID}
+
+		sl, err := q.GetSocialLinkByPlatformUser(ctx, key)
+		if err != nil {
+			return err
+		}
+		if sl.UserID != arg.UserID {
+			return ErrLinkNotOwned
+		}
+
+		if err := q.DeleteSocialLink(ctx, sl.ID); err != nil {
+			return err
+		}
+		if err := q.DeleteOAuthCredentialForChannel(ctx, DeleteOAuthCredentialForChannelParams(key)); err != nil {
+			return err
+		}
+		if err := dropChannelPayouts(ctx, q, arg.Platform, arg.PlatformUserID); err != nil {
+			return err
+		}
+		if err := startOwnershipEpoch(ctx, q, arg.Platform, arg.PlatformUserID, 0); err != nil {
+			return err
+		}
+
+		balance, err := q.GetChannelEscrowBalance(ctx, GetChannelEscrowBalanceParams(key))
+		if err != nil {
+			return err
+		}
+		result.Release, err = q.CreateChannelRelease(ctx, CreateChannelReleaseParams{
+			Platform:           arg.Platform,
+			PlatformUserID:     arg.PlatformUserID,
+			UserID:             arg.UserID,
+			WasVerified:        sl.VerifiedAt.Valid,
+			UnclaimedEscrowRaw: balance,
+		})
+		if err != nil {
+			return err
+		}
+
+		t, err := q.GetOpenChannelTransfer(ctx, GetOpenChannelTransferParams(key))
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !t.FromUserID.Valid || t.FromUserID.Int64 != arg.UserID {
+			return nil
+		}
+		done, err := completeChannelTransfer(ctx, q, t, 0, "channel released by previous owner")
+		if err != nil {
+			return err
+		}
+		result.Transfer = &done
+		return nil
+	})
+
+	return result, err
+}
+
+type ChangePayoutTxParams struct {
+	UserID int64  `json:"user_id"`
+	Chain  string `json:"chain"`
+	// Platform and PlatformUserID target a channel override; empty for the user default.
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+	Address        string `json:"address"`
+	ProofMethod    string `json:"proof_method"`
+	// ENSName is the name Address was resolved from, if any.
+	ENSName string `json:"ens_name"`
+	// Cooldown delays replacing an existing, different address; 0 applies it at once.
+	Cooldown time.Duration `json:"cooldown"`
+}
+
+type ChangePayoutTxResult struct {
+	// Payout is the user default for the chain, when one is set.
+	Payout *Payout `json:"payout"`
+	// ChannelPayout is the channel's override, when one is set.
+	ChannelPayout *ChannelPayout `json:"channel_payout,omitempty"`
+	// Change is nil when the address was already in use (only the proof is refreshed).
+	Change *PayoutHistory `json:"change"`
+	// Superseded counts pending changes for the same target cancelled by this request.
+	Superseded int64 `json:"superseded"`
+}
+
+// ChangePayoutTx records a payout address change for the user default or a
+// channel override. Setting the first address (or a zero cooldown) applies at
+// once; replacing a different address (for an override, the address the
+// channel currently resolves to) stays pending in payout_history until
+// Cooldown has passed, and resolve keeps the old one. Any earlier pending
+// change for the same target is cancelled.
+func (store *Store) ChangePayoutTx(ctx context.Context, arg ChangePayoutTxParams) (ChangePayoutTxResult, error) {
+	var result ChangePayoutTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = changePayout(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func changePayout(ctx context.Context, q *Queries, arg ChangePayoutTxParams) (ChangePayoutTxResult, error) {
+	var result ChangePayoutTxResult
+
+	channel := arg.Platform != ""
+
+	current, err := q.GetPayoutForChain(ctx, GetPayoutForChainParams{UserID: arg.UserID, Chain: arg.Chain})
+	if err == nil {
+		result.Payout = &current
+	} else if err != sql.ErrNoRows {
+		return result, err
+	}
+	if channel {
+		cp, err := q.GetChannelPayout(ctx, GetChannelPayoutParams{
+			UserID:         arg.UserID,
+			Platform:       arg.Platform,
+			PlatformUserID: arg.PlatformUserID,
+			Chain:          arg.Chain,
+		})
+		if err == nil {
+			result.ChannelPayout = &cp
+		} else if err != sql.ErrNoRows {
+			return result, err
+		}
+	}
+
+	// The address tips go to right now.
+	oldAddress := sql.NullString{}
+	switch {
+	case result.ChannelPayout != nil:
+		oldAddress = sql.NullString{String: result.ChannelPayout.Address, Valid: true}
+	case result.Payout != nil:
+		oldAddress = sql.NullString{String: result.Payout.Address, Valid: true}
+	}
+
+	platform := sql.NullString{String: arg.Platform, Valid: channel}
+	platformUserID := sql.NullString{String: arg.PlatformUserID, Valid: channel}
+
+	result.Superseded, err = q.CancelPendingPayoutChanges(ctx, CancelPendingPayoutChangesParams{
+		UserID:         arg.UserID,
+		Chain:          sql.NullString{String: arg.Chain, Valid: true},
+		Platform:       platform,
+		PlatformUserID: platformUserID,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if arg.ENSName != "" {
+		// The record the name had when the creator chose it; the ENS watch
+		// alerts when it changes.
+		if err := q.UpsertENSName(ctx, UpsertENSNameParams{
+			Name:    arg.ENSName,
+			Address: sql.NullString{String: arg.Address, Valid: true},
+		}); err != nil {
+			return result, err
+		}
+	}
+
+	proof := sql.NullString{String: arg.ProofMethod, Valid: arg.ProofMethod != ""}
+	change := CreatePayoutChangeParams{
+		UserID:         arg.UserID,
+		Chain:          arg.Chain,
+		Platform:       platform,
+		PlatformUserID: platformUserID,
+		OldAddress:     oldAddress,
+		NewAddress:     arg.Address,
+		NewEnsName:     sql.NullString{String: arg.ENSName, Valid: arg.ENSName != ""},
+		ProofMethod:    proof,
+		Status:         "applied",
+		EffectiveAt:    time.Now(),
+	}
+
+	if oldAddress.Valid && oldAddress.String != arg.Address && arg.Cooldown > 0 {
+		change.Status = "pending"
+		change.EffectiveAt = change.EffectiveAt.Add(arg.Cooldown)
+		ch, err := q.CreatePayoutChange(ctx, change)
+		if err != nil {
+			return result, err
+		}
+		result.Change = &ch
+		return result, nil
+	}
+
+	if err := applyPayoutAddress(ctx, q, &result, change, sql.NullTime{Time: change.EffectiveAt, Valid: true}); err != nil {
+		return result, err
+	}
+	if oldAddress.Valid && oldAddress.String == arg.Address {
+		return result, nil
+	}
+
+	ch, err := q.CreatePayoutChange(ctx, change)
+	if err != nil {
+		return result, err
+	}
+	result.Change = &ch
+	return result, nil
+}
+
+// applyPayoutAddress writes the change's address to payouts or channel_payouts.
+func applyPayoutAddress(ctx context.Context, q *Queries, result *ChangePayoutTxResult, change CreatePayoutChangeParams, provenAt sql.NullTime) error {
+	if change.Platform.Valid {
+		cp, err := q.UpsertChannelPayout(ctx, UpsertChannelPayoutParams{
+			UserID:         change.UserID,
+			Platform:       change.Platform.String,
+			PlatformUserID: change.PlatformUserID.String,
+			Chain:          change.Chain,
+			Address:        change.NewAddress,
+			ProvenAt:       provenAt,
+			ProofMethod:    change.ProofMethod,
+			EnsName:        change.NewEnsName,
+		})
+		if err != nil {
+			return err
+		}
+		result.ChannelPayout = &cp
+		return nil
+	}
+
+	p, err := q.UpsertPayout(ctx, UpsertPayoutParams{
+		UserID:      change.UserID,
+		Chain:       change.Chain,
+		Address:     change.NewAddress,
+		ProvenAt:    provenAt,
+		ProofMethod: change.ProofMethod,
+		EnsName:     change.NewEnsName,
+	})
+	if err != nil {
+		return err
+	}
+	result.Payout = &p
+	return nil
+}
+
+// ApplyPayoutChangeTx switches the payout to a pending change whose cooldown
+// has passed. sql.ErrNoRows means it was cancelled (or is not due yet).
+func (store *Store) ApplyPayoutChangeTx(ctx context.Context, changeID int64) (PayoutHistory, error) {
+	var result PayoutHistory
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.ApplyPayoutChange(ctx, changeID)
+		if err != nil {
+			return err
+		}
+		// The address proved control when the change was requested.
+		return applyPayoutAddress(ctx, q, &ChangePayoutTxResult{}, CreatePayoutChangeParams{
+			UserID:         result.UserID,
+			Chain:          result.Chain,
+			Platform:       result.Platform,
+			PlatformUserID: result.PlatformUserID,
+			NewAddress:     result.NewAddress,
+			NewEnsName:     result.NewEnsName,
+			ProofMethod:    result.ProofMethod,
+		}, sql.NullTime{Time: result.CreatedAt, Valid: true})
+	})
+
+	return result, err
+}
+
+type DeleteChannelPayoutTxParams struct {
+	UserID         int64  `json:"user_id"`
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+	Chain          string `json:"chain"`
+}
+
+type DeleteChannelPayoutTxResult struct {
+	// ChannelPayout is the removed override; nil when there was none.
+	ChannelPayout *ChannelPayout `json:"channel_payout"`
+	// Change records the removal; new_address is the user default the
+	// channel falls back to, empty when there is none.
+	Change    *PayoutHistory `json:"change"`
+	Cancelled int64          `json:"cancelled_changes"`
+}
+
+// DeleteChannelPayoutTx removes a channel override and any pending change to
+// it, and records the removal in payout_history.
+func (store *Store) DeleteChannelPayoutTx(ctx context.Context, arg DeleteChannelPayoutTxParams) (DeleteChannelPayoutTxResult, error) {
+	var result DeleteChannelPayoutTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		platform := sql.NullString{String: arg.Platform, Valid: true}
+		platformUserID := sql.NullString{String: arg.PlatformUserID, Valid: true}
+
+		var err error
+		result.Cancelled, err = q.CancelPendingPayoutChanges(ctx, CancelPendingPayoutChangesParams{
+			UserID:         arg.UserID,
+			Chain:          sql.NullString{String: arg.Chain, Valid: true},
+			Platform:       platform,
+			PlatformUserID: platformUserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		cp, err := q.DeleteChannelPayout(ctx, DeleteChannelPayoutParams{
+			UserID:         arg.UserID,
+			Platform:       arg.Platform,
+			PlatformUserID: arg.PlatformUserID,
+			Chain:          arg.Chain,
+		})
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		result.ChannelPayout = &cp
+
+		change := CreatePayoutChangeParams{
+			UserID:         arg.UserID,
+			Chain:          arg.Chain,
+			Platform:       platform,
+			PlatformUserID: platformUserID,
+			OldAddress:     sql.NullString{String: cp.Address, Valid: true},
+			Status:         "applied",
+			EffectiveAt:    time.Now(),
+		}
+		def, err := q.GetPayoutForChain(ctx, GetPayoutForChainParams{UserID: arg.UserID, Chain: arg.Chain})
+		switch {
+		case err == nil:
+			change.NewAddress = def.Address
+			change.NewEnsName = def.EnsName
+			change.ProofMethod = def.ProofMethod
+		case err != sql.ErrNoRows:
+			return err
+		}
+		ch, err := q.CreatePayoutChange(ctx, change)
+		if err != nil {
+			return err
+		}
+		result.Change = &ch
+		return nil
+	})
+
+	return result, err
+}
+
+// DropChannelPayoutsTx is dropChannelPayouts for ownership changes made
+// outside a store transaction (takeover of an unverified or lapsed link).
+func (store *Store) DropChannelPayoutsTx(ctx context.Context, platform, platformUserID string) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		return dropChannelPayouts(ctx, q, platform, platformUserID)
+	})
+}
+
+// dropChannelPayouts removes the channel's overrides and revenue split (and
+// pending changes to them) when it changes hands; the next owner starts from
+// their default.
+func dropChannelPayouts(ctx context.Context, q *Queries, platform, platformUserID string) error {
+	if err := q.DeleteChannelPayoutsForChannel(ctx, DeleteChannelPayoutsForChannelParams{
+		Platform:       platform,
+		PlatformUserID: platformUserID,
+	}); err != nil {
+		return err
+	}
+	if err := q.CancelPendingPayoutChangesForChannel(ctx, CancelPendingPayoutChangesForChannelParams{
+		Platform:       sql.NullString{String: platform, Valid: true},
+		PlatformUserID: sql.NullString{String: platformUserID, Valid: true},
+	}); err != nil {
+		return err
+	}
+	for _, end := range [][2]string{{"pending", "cancelled"}, {"active", "removed"}} {
+		if _, err := q.EndChannelSplits(ctx, EndChannelSplitsParams{
+			ToStatus:       end[1],
+			Platform:       platform,
+			PlatformUserID: platformUserID,
+			FromStatus:     end[0],
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SplitRecipient is one entry of channel_splits.recipients.
+type SplitRecipient struct {
+	Address string `json:"address"`
+	Bps     int32  `json:"bps"`
+	Label   string `json:"label,omitempty"`
+}
+
+// SplitBps is the total every split table must add up to.
+const SplitBps = 10000
+
+// DecodeSplitRecipients reads channel_splits.recipients.
+func DecodeSplitRecipients(raw []byte) ([]SplitRecipient, error) {
+	var recipients []SplitRecipient
+	err := json.Unmarshal(raw, &recipients)
+	return recipients, err
+}
+
+type SetChannelSplitTxParams struct {
+	UserID         int64            `json:"user_id"`
+	Platform       string           `json:"platform"`
+	PlatformUserID string           `json:"platform_user_id"`
+	Recipients     []SplitRecipient `json:"recipients"`
+	// Cooldown delays the split while the channel's tips already go somewhere.
+	Cooldown time.Duration `json:"cooldown"`
+}
+
+// SetChannelSplitTx stores a new split table for a channel, cancelling any
+// pending one. Like a payout address change it only applies at once when the
+// channel does not resolve to an address yet (or Cooldown is 0); otherwise it
+// stays pending until Cooldown has passed.
+func (store *Store) SetChannelSplitTx(ctx context.Context, arg SetChannelSplitTxParams) (ChannelSplit, error) {
+	var result ChannelSplit
+
+	recipients, err := json.Marshal(arg.Recipients)
+	if err != nil {
+		return result, err
+	}
+
+	err = store.execTx(ctx, func(q *Queries) error {
+		if _, err := q.EndChannelSplits(ctx, EndChannelSplitsParams{
+			ToStatus:       "cancelled",
+			Platform:       arg.Platform,
+			PlatformUserID: arg.PlatformUserID,
+			FromStatus:     "pending",
+		}); err != nil {
+			return err
+		}
+
+		_, err := q.ResolvePayoutByChannelID(ctx, ResolvePayoutByChannelIDParams{
+			Platform:       arg.Platform,
+			PlatformUserID: arg.PlatformUserID,
+			Chain:          "ethereum",
+		})
+		routed := err == nil
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		create := CreateChannelSplitParams{
+			UserID:         arg.UserID,
+			Platform:       arg.Platform,
+			PlatformUserID: arg.PlatformUserID,
+			Recipients:     recipients,
+			Status:         "active",
+			EffectiveAt:    time.Now(),
+		}
+		if routed && arg.Cooldown > 0 {
+			create.Status = "pending"
+			create.EffectiveAt = create.EffectiveAt.Add(arg.Cooldown)
+		} else if _, err := q.EndChannelSplits(ctx, EndChannelSplitsParams{
+			ToStatus:       "replaced",
+			Platform:       arg.Platform,
+			PlatformUserID: arg.PlatformUserID,
+			FromStatus:     "active",
+		}); err != nil {
+			return err
+		}
+
+		result, err = q.CreateChannelSplit(ctx, create)
+		return err
+	})
+
+	return result, err
+}
+
+// ActivateChannelSplitTx makes a due pending split the channel's active one.
+// sql.ErrNoRows means it was cancelled (or is not due yet).
+func (store *Store) ActivateChannelSplitTx(ctx context.Context, split ChannelSplit) (ChannelSplit, error) {
+	var result ChannelSplit
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if _, err := q.EndChannelSplits(ctx, EndChannelSplitsParams{
+			ToStatus:       "replaced",
+			Platform:       split.Platform,
+			PlatformUserID: split.PlatformUserID,
+			FromStatus:     "active",
+		}); err != nil {
+			return err
+		}
+		var err error
+		result, err = q.ActivateChannelSplit(ctx, split.ID)
+		return err
+	})
+
+	return result, err
+}
+
+// RecordTipTx inserts a tip and, when a split was active at its block time,
+// how it divides among the split's recipients. sql.ErrNoRows means the tip
+// was already recorded.
+func (store *Store) RecordTipTx(ctx context.Context, arg InsertLedgerEventParams) (LedgerEvent, []LedgerAllocation, error) {
+	var (
+		event       LedgerEvent
+		allocations []LedgerAllocation
+	)
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		event, err = q.InsertLedgerEvent(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		split, err := q.GetChannelSplitAt(ctx, GetChannelSplitAtParams{
+			Platform:       arg.Platform,
+			PlatformUserID: arg.PlatformUserID,
+			At:             arg.BlockTime,
+		})
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		allocations, err = allocateTip(event, split)
+		if err != nil {
+			return err
+		}
+		for _, a := range allocations {
+			if err := q.InsertLedgerAllocation(ctx, InsertLedgerAllocationParams(a)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return event, allocations, err
+}
+
+// allocateTip divides the tip by basis points, rounding down; the dust goes
+// to the first recipient.
+func allocateTip(event LedgerEvent, split ChannelSplit) ([]LedgerAllocation, error) {
+	recipients, err := DecodeSplitRecipients(split.Recipients)
+	if err != nil {
+		return nil, err
+	}
+	amount, ok := new(big.Int).SetString(event.AmountRaw, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount_raw %q", event.AmountRaw)
+	}
+
+	out := make([]LedgerAllocation, len(recipients))
+	rest := new(big.Int).Set(amount)
+	for i, r := range recipients {
+		share := new(big.Int).Mul(amount, big.NewInt(int64(r.Bps)))
+		share.Quo(share, big.NewInt(SplitBps))
+		rest.Sub(rest, share)
+		out[i] = LedgerAllocation{
+			LedgerEventID: event.ID,
+			Address:       r.Address,
+			SplitID:       split.ID,
+			Bps:           r.Bps,
+			AmountRaw:     share.String(),
+		}
+	}
+	if len(out) > 0 {
+		first, _ := new(big.Int).SetString(out[0].AmountRaw, 10)
+		out[0].AmountRaw = first.Add(first, rest).String()
+	}
+	return out, nil
+}
+
+// ClaimAllocation is one entry of claim_records.allocations.
+type ClaimAllocation struct {
+	Address   string `json:"address"`
+	AmountRaw string `json:"amount_raw"`
+}
+
+type RecordClaimTxParams struct {
+	UserID        int64                   `json:"user_id"`
+	PayoutAddress string                  `json:"payout_address"`
+	Withdrawal    InsertLedgerEventParams `json:"withdrawal"` // the claim's Withdrawn log
+}
+
+type RecordClaimTxResult struct {
+	Event       LedgerEvent       `json:"event"`
+	Claim       ClaimRecord       `json:"claim"`
+	Allocations []ClaimAllocation `json:"allocations"`
+}
+
+// RecordClaimTx records an executed claim: the WITHDRAW ledger event and how
+// the amount paid out divides among split recipients. The contract pays the
+// whole balance to the payout address, and that balance is what was tipped
+// into escrow since the channel's previous withdrawal, so each recipient's
+// share is pro rata to those tips allocated to them; tips made without a
+// split (and rounding dust) stay with the payout address. A Withdrawn log
+// that is already recorded returns sql.ErrNoRows.
+func (store *Store) RecordClaimTx(ctx context.Context, arg RecordClaimTxParams) (RecordClaimTxResult, error) {
+	var result RecordClaimTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Event, err = q.InsertLedgerEvent(ctx, arg.Withdrawal)
+		if err != nil {
+			return err
+		}
+		ev := result.Event
+
+		after, err := q.GetLastWithdrawalTime(ctx, GetLastWithdrawalTimeParams{
+			Platform:       ev.Platform,
+			PlatformUserID: ev.PlatformUserID,
+			Before:         ev.BlockTime,
+		})
+		if err != nil {
+			return err
+		}
+		tipsRaw, err := q.SumEscrowTipsBetween(ctx, SumEscrowTipsBetweenParams{
+			Platform:       ev.Platform,
+			PlatformUserID: ev.PlatformUserID,
+			After:          after,
+			Until:          ev.BlockTime,
+		})
+		if err != nil {
+			return err
+		}
+		allocated, err := q.SumEscrowAllocationsBetween(ctx, SumEscrowAllocationsBetweenParams{
+			Platform:       ev.Platform,
+			PlatformUserID: ev.PlatformUserID,
+			After:          after,
+			Until:          ev.BlockTime,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Allocations, err = allocateClaim(ev.AmountRaw, tipsRaw, arg.PayoutAddress, allocated)
+		if err != nil {
+			return err
+		}
+		allocations, err := json.Marshal(result.Allocations)
+		if err != nil {
+			return err
+		}
+
+		result.Claim, err = q.CreateClaimRecord(ctx, CreateClaimRecordParams{
+			UserID:           arg.UserID,
+			Platform:         ev.Platform,
+			PlatformUserID:   ev.PlatformUserID,
+			PayoutAddress:    arg.PayoutAddress,
+			EscrowBalanceRaw: ev.AmountRaw,
+			Allocations:      allocations,
+			LedgerEventID:    ev.ID,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// allocateClaim divides balance pro rata to each address's share of tips,
+// rounding down; the remainder goes to payout.
+func allocateClaim(balanceRaw, tipsRaw, payout string, allocated []SumEscrowAllocationsBetweenRow) ([]ClaimAllocation, error) {
+	balance, ok := new(big.Int).SetString(balanceRaw, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid balance_raw %q", balanceRaw)
+	}
+	tips, ok := new(big.Int).SetString(tipsRaw, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid tips amount %q", tipsRaw)
+	}
+
+	shares := make(map[string]*big.Int, len(allocated)+1)
+	order := make([]string, 0, len(allocated)+1)
+	add := func(addr string, amount *big.Int) {
+		if _, ok := shares[addr]; !ok {
+			shares[addr] = new(big.Int)
+			order = append(order, addr)
+		}
+		shares[addr].Add(shares[addr], amount)
+	}
+
+	rest := new(big.Int).Set(balance)
+	if tips.Sign() > 0 {
+		for _, a := range allocated {
+			amount, ok := new(big.Int).SetString(a.AmountRaw, 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid allocation amount %q", a.AmountRaw)
+			}
+			share := amount.Mul(amount, balance)
+			share.Quo(share, tips)
+			rest.Sub(rest, share)
+			add(a.Address, share)
+		}
+	}
+	add(payout, rest)
+
+	out := make([]ClaimAllocation, 0, len(order))
+	for _, addr := range order {
+		if shares[addr].Sign() == 0 {
+			continue
+		}
+		out = append(out, ClaimAllocation{Address: addr, AmountRaw: shares[addr].String()})
+	}
+	return out, nil
+}
+
+type QueueClaimRelayTxParams struct {
+	CreateClaimRelayParams
+	DailyLimit int       `json:"daily_limit"` // 0 = no quota
+	Since      time.Time `json:"since"`       // start of the quota window
+}
+
+// QueueClaimRelayTx creates a queued relay unless the user already has
+// DailyLimit relays counting against the quota since Since (ErrRelayQuota).
+// The user row is locked first, so concurrent requests cannot both take the
+// last one.
+func (store *Store) QueueClaimRelayTx(ctx context.Context, arg QueueClaimRelayTxParams) (ClaimRelay, error) {
+	var result ClaimRelay
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if arg.DailyLimit > 0 {
+			if _, err := q.LockUser(ctx, arg.UserID); err != nil {
+				return err
+			}
+			n, err := q.CountClaimRelaysSince(ctx, CountClaimRelaysSinceParams{
+				UserID: arg.UserID,
+				Since:  arg.Since,
+			})
+			if err != nil {
+				return err
+			}
+			if n >= int64(arg.DailyLimit) {
+				return ErrRelayQuota
+			}
+		}
+
+		var err error
+		result, err = q.CreateClaimRelay(ctx, arg.CreateClaimRelayParams)
+		return err
+	})
+
+	return result, err
+}
+
+// TryLockClaimRelayer takes the session-level advisory lock key on a
+// connection of its own, so only one server sends from a hot wallet at a
+// time. ok is false when another session holds it; otherwise unlock releases
+// the lock and the connection.
+func (store *Store) TryLockClaimRelayer(ctx context.Context, key int64) (unlock func(), ok bool, err error) {
+	conn, err := store.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	q := New(conn)
+
+	ok, err = q.TryLockClaimRelayer(ctx, key)
+	if err != nil || !ok {
+		conn.Close()
+		return nil, false, err
+	}
+	return func() {
+		// The caller's context may be done by now; the lock must still go.
+		if err := q.UnlockClaimRelayer(context.Background(), key); err != nil {
+			// Closing the connection below would keep it pooled and locked.
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}, true, nil
+}
+
+type RecordRelayAttemptTxParams struct {
+	RelayID   int64  `json:"relay_id"`
+	TxHash    string `json:"tx_hash"`
+	TxNonce   int64  `json:"tx_nonce"`
+	GasLimit  int64  `json:"gas_limit"`
+	GasTipCap string `json:"gas_tip_cap"`
+	GasFeeCap string `json:"gas_fee_cap"`
+	Kind      string `json:"kind"` // "claim" or "cancel"
+}
+
+// RecordRelayAttemptTx makes a signed transaction (a first send, a fee
+// replacement or the cancellation of an expired claim) the relay's latest
+// attempt. It runs before the transaction is broadcast, so every transaction
+// that may get mined is known.
+func (store *Store) RecordRelayAttemptTx(ctx context.Context, arg RecordRelayAttemptTxParams) (ClaimRelay, error) {
+	var result ClaimRelay
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		_, err := q.CreateClaimRelayTx(ctx, CreateClaimRelayTxParams{
+			RelayID:   arg.RelayID,
+			TxHash:    arg.TxHash,
+			TxNonce:   arg.TxNonce,
+			GasTipCap: arg.GasTipCap,
+			GasFeeCap: arg.GasFeeCap,
+			Kind:      arg.Kind,
+		})
+		if err != nil {
+			return err
+		}
+		result, err = q.MarkClaimRelaySubmitted(ctx, MarkClaimRelaySubmittedParams{
+			ID:       arg.RelayID,
+			TxNonce:  sql.NullInt64{Int64: arg.TxNonce, Valid: true},
+			GasLimit: sql.NullInt64{Int64: arg.GasLimit, Valid: true},
+			TxHash:   sql.NullString{String: arg.TxHash, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
+
+/*
+Ownership epochs record who held a verified link to a channel and when. Tips
+and withdrawals are attributed to the owner at block time, with one rule for
+escrow:
+
+  - Tips made while nobody owned the channel (before the first verification,
+    or between owners) belong to the next owner, who is the only one able to
+    claim them.
+  - Escrow left unclaimed when ownership changes follows the channel: the
+    contract pays the whole channel balance to whoever gets a claim
+    signature. Earnings reports show it as carried out of the previous
+    owner's balance and into the new owner's (GetEarningsSummaryForUser).
+*/
+
+type VerifySocialLinkTxParams struct {
+	UserID         int64      `json:"user_id"`
+	Platform       string     `json:"platform"`
+	PlatformUserID string     `json:"platform_user_id"`
+	Method         string     `json:"method"`
+	Existing       SocialLink `json:"existing"` // zero value when the channel has no link
+}
+
+// VerifySocialLinkTx records a proof of ownership by UserID: it creates,
+// re-verifies or takes over the channel's link and starts UserID's ownership
+// epoch in the same transaction, so a verified link never lacks its epoch.
+// Taking over a live verification from another user is the caller's job
+// (a channel transfer), not this one's.
+func (store *Store) VerifySocialLinkTx(ctx context.Context, arg VerifySocialLinkTxParams) error {
+	verifiedAt := sql.NullTime{Time: time.Now(), Valid: true}
+	verifiedBy := sql.NullString{String: arg.Method, Valid: true}
+	ex := arg.Existing
+
+	return store.execTx(ctx, func(q *Queries) error {
+		var err error
+		switch {
+		case ex.ID == 0:
+			_, err = q.CreateSocialLink(ctx, CreateSocialLinkParams{
+				UserID:             arg.UserID,
+				Platform:           arg.Platform,
+				PlatformUserID:     arg.PlatformUserID,
+				VerifiedAt:         verifiedAt,
+				VerificationMethod: verifiedBy,
+			})
+		case ex.UserID == arg.UserID:
+			// Also refreshes links that are failing re-verification or have lapsed.
+			if !ex.VerifiedAt.Valid || ex.CheckFailedAt.Valid || ex.StaleAt.Valid {
+				_, err = q.UpdateSocialLinkVerifiedAt(ctx, UpdateSocialLinkVerifiedAtParams{
+					ID:                 ex.ID,
+					VerifiedAt:         verifiedAt,
+					VerificationMethod: verifiedBy,
+				})
+			}
+		default:
+			_, err = q.TransferSocialLinkToUser(ctx, TransferSocialLinkToUserParams{
+				ID:                 ex.ID,
+				UserID:             arg.UserID,
+				VerifiedAt:         verifiedAt,
+				VerificationMethod: verifiedBy,
+			})
+		}
+		if err != nil {
+			return err
+		}
+		return startOwnershipEpoch(ctx, q, arg.Platform, arg.PlatformUserID, arg.UserID)
+	})
+}
+
+// startOwnershipEpoch closes the open epoch unless it already belongs to
+// userID, opens one for userID (none when userID is 0) and re-derives ledger
+// attribution for the channel.
+func startOwnershipEpoch(ctx context.Context, q *Queries, platform, platformUserID string, userID int64) error {
+	key := GetOpenOwnershipEpochParams{Platform: platform, PlatformUserID: platformUserID}
+
+	open, err := q.GetOpenOwnershipEpoch(ctx, key)
+	switch {
+	case err == nil && open.UserID == userID:
+		return nil
+	case err == nil:
+		if err := q.CloseOwnershipEpoch(ctx, open.ID); err != nil {
+			return err
+		}
+	case err != sql.ErrNoRows:
+		return err
+	}
+
+	if userID != 0 {
+		if _, err := q.CreateOwnershipEpoch(ctx, CreateOwnershipEpochParams{
+			Platform:       platform,
+			PlatformUserID: platformUserID,
+			UserID:         userID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	_, err = q.AttributeLedgerEventsForChannel(ctx, AttributeLedgerEventsForChannelParams(key))
+	return err
+}
@@ -26,7 +26,7 @@ func main() {
 	}
 	defer conn.Close()
 
-	store := db.New(conn)
+	store := db.NewStore(conn)
 
 	server := api.NewServer(store)
 	port := os.Getenv("PORT")
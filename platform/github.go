@@ -0,0 +1,118 @@
+package platform
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const DefaultGitHubBaseURL = "https://api.github.com"
+
+// GitHub logins: alphanumerics and single hyphens, max 39 chars, case-insensitive.
+// They can be renamed and then claimed by someone else, so links are keyed by
+// the numeric account id.
+var githubLoginRe = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9]|-[a-z0-9]){0,38}$`)
+
+type GitHub struct {
+	baseURL string
+	token   string
+}
+
+func NewGitHub(baseURL, token string) *GitHub {
+	return &GitHub{
+		baseURL: baseURLOr(baseURL, DefaultGitHubBaseURL),
+		token:   strings.TrimSpace(token),
+	}
+}
+
+func (g *GitHub) Name() string { return "github" }
+
+func (g *GitHub) NormalizeID(raw string) (string, error) {
+	return normalizeNumericID(raw)
+}
+
+func (g *GitHub) ParseHandle(raw string) (string, error) {
+	login := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(raw), "@"))
+	if !githubLoginRe.MatchString(login) {
+		return "", ErrInvalidID
+	}
+	return login, nil
+}
+
+type githubUser struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url"`
+	HTMLURL   string `json:"html_url"`
+	Bio       string `json:"bio"`
+}
+
+// Calls GitHub REST: GET /user
+func (g *GitHub) VerifyOwnership(ctx context.Context, accessToken, id string) (bool, error) {
+	var out githubUser
+	if _, err := getJSON(ctx, "github", g.baseURL+"/user", bearer(accessToken), &out); err != nil {
+		return false, err
+	}
+	return strconv.FormatInt(out.ID, 10) == id, nil
+}
+
+// Calls GitHub REST: GET /user/:id or /users/:login (public; token only
+// raises rate limits)
+func (g *GitHub) get(ctx context.Context, path string) (*githubUser, error) {
+	var headers map[string]string
+	if g.token != "" {
+		headers = bearer(g.token)
+	}
+	var out githubUser
+	status, err := getJSON(ctx, "github", g.baseURL+path, headers, &out)
+	if status == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (g *GitHub) user(ctx context.Context, id string) (*githubUser, error) {
+	return g.get(ctx, "/user/"+url.PathEscape(id))
+}
+
+func (g *GitHub) Profile(ctx context.Context, id string) (*Profile, error) {
+	out, err := g.user(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	name := out.Name
+	if name == "" {
+		name = out.Login
+	}
+	return &Profile{
+		Platform:       g.Name(),
+		PlatformUserID: strconv.FormatInt(out.ID, 10),
+		DisplayName:    name,
+		AvatarURL:      out.AvatarURL,
+		URL:            out.HTMLURL,
+		Handle:         strings.ToLower(out.Login),
+	}, nil
+}
+
+func (g *GitHub) ResolveHandle(ctx context.Context, login string) (string, error) {
+	out, err := g.get(ctx, "/users/"+url.PathEscape(login))
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(out.ID, 10), nil
+}
+
+func (g *GitHub) Description(ctx context.Context, id string) (string, error) {
+	out, err := g.user(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return out.Bio, nil
+}
@@ -0,0 +1,115 @@
+package platform
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const DefaultKickBaseURL = "https://api.kick.com/public/v1"
+
+// Kick channel slugs: lowercase letters, digits, underscores and hyphens. A
+// slug follows the username, so links are keyed by the broadcaster's user id.
+var kickSlugRe = regexp.MustCompile(`^[a-z0-9_-]{3,25}$`)
+
+type Kick struct {
+	baseURL  string
+	appToken string
+}
+
+func NewKick(baseURL, appToken string) *Kick {
+	return &Kick{
+		baseURL:  baseURLOr(baseURL, DefaultKickBaseURL),
+		appToken: strings.TrimSpace(appToken),
+	}
+}
+
+func (k *Kick) Name() string { return "kick" }
+
+func (k *Kick) NormalizeID(raw string) (string, error) {
+	return normalizeNumericID(raw)
+}
+
+func (k *Kick) ParseHandle(raw string) (string, error) {
+	slug := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(raw), "@"))
+	if !kickSlugRe.MatchString(slug) {
+		return "", ErrInvalidID
+	}
+	return slug, nil
+}
+
+type kickChannels struct {
+	Data []struct {
+		BroadcasterUserID  int64  `json:"broadcaster_user_id"`
+		Slug               string `json:"slug"`
+		ChannelDescription string `json:"channel_description"`
+	} `json:"data"`
+}
+
+// Calls Kick public API: GET /channels (returns the token's own channel)
+func (k *Kick) VerifyOwnership(ctx context.Context, accessToken, id string) (bool, error) {
+	var out kickChannels
+	if _, err := getJSON(ctx, "kick", k.baseURL+"/channels", bearer(accessToken), &out); err != nil {
+		return false, err
+	}
+	for _, ch := range out.Data {
+		if strconv.FormatInt(ch.BroadcasterUserID, 10) == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Calls Kick public API: GET /channels?broadcaster_user_id=... or ?slug=... (app token)
+func (k *Kick) lookup(ctx context.Context, q url.Values) (*kickChannels, error) {
+	if k.appToken == "" {
+		return nil, ErrProfileUnavailable
+	}
+	var out kickChannels
+	if _, err := getJSON(ctx, "kick", k.baseURL+"/channels?"+q.Encode(), bearer(k.appToken), &out); err != nil {
+		return nil, err
+	}
+	if len(out.Data) == 0 {
+		return nil, ErrNotFound
+	}
+	return &out, nil
+}
+
+func (k *Kick) channel(ctx context.Context, q url.Values) (*Profile, error) {
+	out, err := k.lookup(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	ch := out.Data[0]
+	slug := strings.ToLower(ch.Slug)
+	return &Profile{
+		Platform:       k.Name(),
+		PlatformUserID: strconv.FormatInt(ch.BroadcasterUserID, 10),
+		DisplayName:    ch.Slug,
+		URL:            "https://kick.com/" + slug,
+		Handle:         slug,
+	}, nil
+}
+
+func (k *Kick) Profile(ctx context.Context, id string) (*Profile, error) {
+	return k.channel(ctx, url.Values{"broadcaster_user_id": {id}})
+}
+
+func (k *Kick) ResolveHandle(ctx context.Context, slug string) (string, error) {
+	prof, err := k.channel(ctx, url.Values{"slug": {slug}})
+	if err != nil {
+		return "", err
+	}
+	return prof.PlatformUserID, nil
+}
+
+// Description is the channel's bio.
+func (k *Kick) Description(ctx context.Context, id string) (string, error) {
+	out, err := k.lookup(ctx, url.Values{"broadcaster_user_id": {id}})
+	if err != nil {
+		return "", err
+	}
+	return out.Data[0].ChannelDescription, nil
+}
@@ -0,0 +1,187 @@
+// Package platform abstracts the creator platforms (YouTube, Twitch, ...) that
+// a social link can point at.
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidID          = errors.New("invalid platform user id")
+	ErrProfileUnavailable = errors.New("profile lookup not configured for this platform")
+	ErrNotFound           = errors.New("platform user not found")
+)
+
+// Profile is public metadata about a creator account.
+type Profile struct {
+	Platform       string `json:"platform"`
+	PlatformUserID string `json:"platform_user_id"`
+	DisplayName    string `json:"display_name"`
+	AvatarURL      string `json:"avatar_url,omitempty"`
+	URL            string `json:"url,omitempty"`
+	// Handle and SubscriberCount are only filled in where the platform has them.
+	Handle          string `json:"handle,omitempty"`
+	SubscriberCount *int64 `json:"subscriber_count,omitempty"`
+}
+
+// Provider is everything the API needs to know about one platform.
+type Provider interface {
+	// Name is the value stored in social_links.platform and used in routes.
+	Name() string
+	// NormalizeID validates raw and returns the canonical id stored in the DB.
+	NormalizeID(raw string) (string, error)
+	// VerifyOwnership reports whether the OAuth access token belongs to id.
+	VerifyOwnership(ctx context.Context, accessToken, id string) (bool, error)
+	// Profile looks up public metadata for id.
+	Profile(ctx context.Context, id string) (*Profile, error)
+}
+
+// DescriptionReader is implemented by providers that can read a creator's
+// public bio/description, which enables verification by one-time code.
+type DescriptionReader interface {
+	Description(ctx context.Context, id string) (string, error)
+}
+
+// HandleResolver is implemented by providers whose public name (login,
+// username, slug) can be changed or re-registered by someone else. Links on
+// those platforms are keyed by the stable numeric user id; a handle is only
+// turned into that id at lookup time.
+type HandleResolver interface {
+	// ParseHandle validates raw as a handle and returns it lowercased, without "@".
+	ParseHandle(raw string) (string, error)
+	// ResolveHandle returns the id of the account that uses handle right now.
+	ResolveHandle(ctx context.Context, handle string) (string, error)
+}
+
+// numericIDRe matches the stable user ids of Twitch, X, GitHub and Kick.
+var numericIDRe = regexp.MustCompile(`^[0-9]{1,20}$`)
+
+func normalizeNumericID(raw string) (string, error) {
+	id := strings.TrimSpace(raw)
+	if !numericIDRe.MatchString(id) {
+		return "", ErrInvalidID
+	}
+	return id, nil
+}
+
+// ResolveID accepts what a user knows about an account and returns the id to
+// store. A value starting with "@" is always a handle (handles may be all
+// digits); anything else is tried as an id first.
+func ResolveID(ctx context.Context, p Provider, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, "@") {
+		if id, err := p.NormalizeID(raw); err == nil {
+			return id, nil
+		}
+	}
+	hr, ok := p.(HandleResolver)
+	if !ok {
+		return "", ErrInvalidID
+	}
+	handle, err := hr.ParseHandle(raw)
+	if err != nil {
+		return "", err
+	}
+	return hr.ResolveHandle(ctx, handle)
+}
+
+type Registry struct {
+	providers map[string]Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+func (r *Registry) Register(p Provider) {
+	r.providers[p.Name()] = p
+}
+
+func (r *Registry) Get(name string) (Provider, bool) {
+	p, ok := r.providers[strings.ToLower(strings.TrimSpace(name))]
+	return p, ok
+}
+
+func (r *Registry) Names() []string {
+	out := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// NewRegistryFromEnv registers every built-in provider. Base URLs and app
+// credentials come from the environment so tests can point them at fakes.
+func NewRegistryFromEnv() *Registry {
+	return NewRegistry(
+		NewYouTube(os.Getenv("YOUTUBE_API_BASE_URL"), os.Getenv("YOUTUBE_API_KEY")),
+		NewTwitch(os.Getenv("TWITCH_API_BASE_URL"), os.Getenv("TWITCH_CLIENT_ID"), os.Getenv("TWITCH_APP_TOKEN")),
+		NewX(os.Getenv("X_API_BASE_URL"), os.Getenv("X_BEARER_TOKEN")),
+		NewGitHub(os.Getenv("GITHUB_API_BASE_URL"), os.Getenv("GITHUB_TOKEN")),
+		NewKick(os.Getenv("KICK_API_BASE_URL"), os.Getenv("KICK_APP_TOKEN")),
+	)
+}
+
+func baseURLOr(raw, def string) string {
+	raw = strings.TrimRight(strings.TrimSpace(raw), "/")
+	if raw == "" {
+		return def
+	}
+	return raw
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// getJSON performs an authenticated GET and decodes the body into out. Error
+// bodies are trimmed and surfaced so callers can pass them on.
+func getJSON(ctx context.Context, name, url string, headers map[string]string, out any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%s api request failed: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		msg := strings.TrimSpace(string(body))
+		if len(msg) > 400 {
+			msg = msg[:400] + "..."
+		}
+		if msg == "" {
+			msg = resp.Status
+		}
+		return resp.StatusCode, fmt.Errorf("%s api error: %s", name, msg)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to parse %s response: %w", name, err)
+	}
+	return resp.StatusCode, nil
+}
+
+func bearer(token string) map[string]string {
+	return map[string]string{"Authorization": "Bearer " + token}
+}
@@ -0,0 +1,128 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const DefaultTwitchBaseURL = "https://api.twitch.tv/helix"
+
+// Twitch logins are 4-25 chars of [a-z0-9_] and can be renamed, so links are
+// keyed by the numeric user id and logins are resolved on lookup.
+var twitchLoginRe = regexp.MustCompile(`^[a-z0-9_]{4,25}$`)
+
+type Twitch struct {
+	baseURL  string
+	clientID string
+	appToken string
+}
+
+func NewTwitch(baseURL, clientID, appToken string) *Twitch {
+	return &Twitch{
+		baseURL:  baseURLOr(baseURL, DefaultTwitchBaseURL),
+		clientID: strings.TrimSpace(clientID),
+		appToken: strings.TrimSpace(appToken),
+	}
+}
+
+func (t *Twitch) Name() string { return "twitch" }
+
+func (t *Twitch) NormalizeID(raw string) (string, error) {
+	return normalizeNumericID(raw)
+}
+
+func (t *Twitch) ParseHandle(raw string) (string, error) {
+	login := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(raw), "@"))
+	if !twitchLoginRe.MatchString(login) {
+		return "", ErrInvalidID
+	}
+	return login, nil
+}
+
+type twitchUsers struct {
+	Data []struct {
+		ID              string `json:"id"`
+		Login           string `json:"login"`
+		DisplayName     string `json:"display_name"`
+		ProfileImageURL string `json:"profile_image_url"`
+		Description     string `json:"description"`
+	} `json:"data"`
+}
+
+func (t *Twitch) headers(token string) map[string]string {
+	h := bearer(token)
+	h["Client-Id"] = t.clientID
+	return h
+}
+
+// Calls Helix: GET /users (returns the token's own user)
+func (t *Twitch) VerifyOwnership(ctx context.Context, accessToken, id string) (bool, error) {
+	if t.clientID == "" {
+		return false, errors.New("twitch verification not configured (TWITCH_CLIENT_ID)")
+	}
+	var out twitchUsers
+	if _, err := getJSON(ctx, "twitch", t.baseURL+"/users", t.headers(accessToken), &out); err != nil {
+		return false, err
+	}
+	for _, u := range out.Data {
+		if u.ID == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Calls Helix: GET /users?id=... or ?login=... (app token)
+func (t *Twitch) lookup(ctx context.Context, q url.Values) (*twitchUsers, error) {
+	if t.clientID == "" || t.appToken == "" {
+		return nil, ErrProfileUnavailable
+	}
+	var out twitchUsers
+	if _, err := getJSON(ctx, "twitch", t.baseURL+"/users?"+q.Encode(), t.headers(t.appToken), &out); err != nil {
+		return nil, err
+	}
+	if len(out.Data) == 0 {
+		return nil, ErrNotFound
+	}
+	return &out, nil
+}
+
+func (t *Twitch) user(ctx context.Context, q url.Values) (*Profile, error) {
+	out, err := t.lookup(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	u := out.Data[0]
+	return &Profile{
+		Platform:       t.Name(),
+		PlatformUserID: u.ID,
+		DisplayName:    u.DisplayName,
+		AvatarURL:      u.ProfileImageURL,
+		URL:            "https://www.twitch.tv/" + strings.ToLower(u.Login),
+		Handle:         strings.ToLower(u.Login),
+	}, nil
+}
+
+func (t *Twitch) Profile(ctx context.Context, id string) (*Profile, error) {
+	return t.user(ctx, url.Values{"id": {id}})
+}
+
+func (t *Twitch) ResolveHandle(ctx context.Context, login string) (string, error) {
+	prof, err := t.user(ctx, url.Values{"login": {login}})
+	if err != nil {
+		return "", err
+	}
+	return t.NormalizeID(prof.PlatformUserID)
+}
+
+// Description is the channel bio ("About" panel text).
+func (t *Twitch) Description(ctx context.Context, id string) (string, error) {
+	out, err := t.lookup(ctx, url.Values{"id": {id}})
+	if err != nil {
+		return "", err
+	}
+	return out.Data[0].Description, nil
+}
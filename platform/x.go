@@ -0,0 +1,110 @@
+package platform
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const DefaultXBaseURL = "https://api.twitter.com/2"
+
+// X handles are 1-15 chars of [A-Za-z0-9_] and case-insensitive. They can be
+// changed and re-registered, so links are keyed by the numeric user id.
+var xHandleRe = regexp.MustCompile(`^[a-z0-9_]{1,15}$`)
+
+type X struct {
+	baseURL     string
+	bearerToken string
+}
+
+func NewX(baseURL, bearerToken string) *X {
+	return &X{
+		baseURL:     baseURLOr(baseURL, DefaultXBaseURL),
+		bearerToken: strings.TrimSpace(bearerToken),
+	}
+}
+
+func (x *X) Name() string { return "x" }
+
+func (x *X) NormalizeID(raw string) (string, error) {
+	return normalizeNumericID(raw)
+}
+
+func (x *X) ParseHandle(raw string) (string, error) {
+	handle := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(raw), "@"))
+	if !xHandleRe.MatchString(handle) {
+		return "", ErrInvalidID
+	}
+	return handle, nil
+}
+
+type xUser struct {
+	Data struct {
+		ID              string `json:"id"`
+		Name            string `json:"name"`
+		Username        string `json:"username"`
+		ProfileImageURL string `json:"profile_image_url"`
+		Description     string `json:"description"`
+	} `json:"data"`
+}
+
+// Calls X API v2: GET /users/me (user-context token)
+func (x *X) VerifyOwnership(ctx context.Context, accessToken, id string) (bool, error) {
+	var out xUser
+	if _, err := getJSON(ctx, "x", x.baseURL+"/users/me", bearer(accessToken), &out); err != nil {
+		return false, err
+	}
+	return out.Data.ID == id, nil
+}
+
+// Calls X API v2: GET /users/:id or /users/by/username/:username (app bearer token)
+func (x *X) lookup(ctx context.Context, path string) (*xUser, error) {
+	if x.bearerToken == "" {
+		return nil, ErrProfileUnavailable
+	}
+	var out xUser
+	if _, err := getJSON(ctx, "x", x.baseURL+path+"?user.fields=profile_image_url,description", bearer(x.bearerToken), &out); err != nil {
+		return nil, err
+	}
+	if out.Data.ID == "" {
+		return nil, ErrNotFound
+	}
+	return &out, nil
+}
+
+func (x *X) user(ctx context.Context, path string) (*Profile, error) {
+	out, err := x.lookup(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return &Profile{
+		Platform:       x.Name(),
+		PlatformUserID: out.Data.ID,
+		DisplayName:    out.Data.Name,
+		AvatarURL:      out.Data.ProfileImageURL,
+		URL:            "https://x.com/" + out.Data.Username,
+		Handle:         strings.ToLower(out.Data.Username),
+	}, nil
+}
+
+func (x *X) Profile(ctx context.Context, id string) (*Profile, error) {
+	return x.user(ctx, "/users/"+url.PathEscape(id))
+}
+
+func (x *X) ResolveHandle(ctx context.Context, handle string) (string, error) {
+	prof, err := x.user(ctx, "/users/by/username/"+url.PathEscape(handle))
+	if err != nil {
+		return "", err
+	}
+	return x.NormalizeID(prof.PlatformUserID)
+}
+
+// Description is the profile bio.
+func (x *X) Description(ctx context.Context, id string) (string, error) {
+	out, err := x.lookup(ctx, "/users/"+url.PathEscape(id))
+	if err != nil {
+		return "", err
+	}
+	return out.Data.Description, nil
+}
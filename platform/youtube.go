@@ -0,0 +1,233 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const DefaultYouTubeBaseURL = "https://www.googleapis.com/youtube/v3"
+
+var youtubeChannelIDRe = regexp.MustCompile(`^UC[0-9A-Za-z_-]{22}$`)
+
+type YouTube struct {
+	baseURL string
+	apiKey  string
+}
+
+func NewYouTube(baseURL, apiKey string) *YouTube {
+	return &YouTube{
+		baseURL: baseURLOr(baseURL, DefaultYouTubeBaseURL),
+		apiKey:  strings.TrimSpace(apiKey),
+	}
+}
+
+func (y *YouTube) Name() string { return "youtube" }
+
+// Channel IDs are case-sensitive, so only surrounding whitespace is trimmed.
+func (y *YouTube) NormalizeID(raw string) (string, error) {
+	id := strings.TrimSpace(raw)
+	if !youtubeChannelIDRe.MatchString(id) {
+		return "", ErrInvalidID
+	}
+	return id, nil
+}
+
+// Calls YouTube Data API: GET /youtube/v3/channels?part=id&mine=true
+func (y *YouTube) VerifyOwnership(ctx context.Context, accessToken, id string) (bool, error) {
+	var out struct {
+		Items []struct {
+			ID string `json:"id"`
+		} `json:"items"`
+	}
+	if _, err := getJSON(ctx, "youtube", y.baseURL+"/channels?part=id&mine=true", bearer(accessToken), &out); err != nil {
+		return false, err
+	}
+	// No items: the Google account has no channel (or lost this one).
+	for _, it := range out.Items {
+		if strings.TrimSpace(it.ID) == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type youtubeChannelSnippet struct {
+	ID      string `json:"id"`
+	Snippet struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		CustomURL   string `json:"customUrl"`
+		Thumbnails  map[string]struct {
+			URL string `json:"url"`
+		} `json:"thumbnails"`
+	} `json:"snippet"`
+	Statistics struct {
+		SubscriberCount       string `json:"subscriberCount"`
+		HiddenSubscriberCount bool   `json:"hiddenSubscriberCount"`
+	} `json:"statistics"`
+}
+
+// Calls YouTube Data API: GET /youtube/v3/channels?part=snippet,statistics&... (API key)
+func (y *YouTube) channels(ctx context.Context, q url.Values) (*youtubeChannelSnippet, error) {
+	if y.apiKey == "" {
+		return nil, ErrProfileUnavailable
+	}
+
+	q.Set("part", "snippet,statistics")
+	q.Set("key", y.apiKey)
+
+	var out struct {
+		Items []youtubeChannelSnippet `json:"items"`
+	}
+	if _, err := getJSON(ctx, "youtube", y.baseURL+"/channels?"+q.Encode(), nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Items) == 0 {
+		return nil, ErrNotFound
+	}
+	return &out.Items[0], nil
+}
+
+func (y *YouTube) channelSnippet(ctx context.Context, id string) (*youtubeChannelSnippet, error) {
+	return y.channels(ctx, url.Values{"id": {id}})
+}
+
+func (y *YouTube) Profile(ctx context.Context, id string) (*Profile, error) {
+	it, err := y.channelSnippet(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return it.profile(), nil
+}
+
+func (it *youtubeChannelSnippet) profile() *Profile {
+	p := &Profile{
+		Platform:       "youtube",
+		PlatformUserID: it.ID,
+		DisplayName:    it.Snippet.Title,
+		AvatarURL:      it.Snippet.Thumbnails["default"].URL,
+		URL:            "https://www.youtube.com/channel/" + it.ID,
+		Handle:         strings.ToLower(strings.TrimPrefix(it.Snippet.CustomURL, "@")),
+	}
+	if !it.Statistics.HiddenSubscriberCount {
+		if n, err := strconv.ParseInt(it.Statistics.SubscriberCount, 10, 64); err == nil {
+			p.SubscriberCount = &n
+		}
+	}
+	return p
+}
+
+func (y *YouTube) Description(ctx context.Context, id string) (string, error) {
+	it, err := y.channelSnippet(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return it.Snippet.Description, nil
+}
+
+var youtubeHandleRe = regexp.MustCompile(`^[0-9A-Za-z_.-]{1,100}$`)
+
+// ParseChannelRef splits what a user or the extension knows about a channel
+// into either a channel id or a handle/custom name (lowercased, no "@").
+// Accepted: "UC...", "@handle", a bare handle not starting with "UC", and
+// youtube.com URLs of the form /channel/UC...,
+// /@handle, /c/name and /user/name.
+func (y *YouTube) ParseChannelRef(raw string) (id, handle string, err error) {
+	ref := strings.TrimSpace(raw)
+	if id, err := y.NormalizeID(ref); err == nil {
+		return id, "", nil
+	}
+	// A bare "UC..." is meant as a channel id; a malformed one is rejected
+	// instead of being looked up as a handle.
+	if strings.HasPrefix(ref, "UC") {
+		return "", "", ErrInvalidID
+	}
+
+	if strings.Contains(strings.ToLower(ref), "youtube.com") {
+		if !strings.Contains(ref, "://") {
+			ref = "https://" + ref
+		}
+		u, err := url.Parse(ref)
+		if err != nil {
+			return "", "", ErrInvalidID
+		}
+		host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+		if host != "youtube.com" && host != "m.youtube.com" {
+			return "", "", ErrInvalidID
+		}
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		switch {
+		case len(parts) >= 2 && parts[0] == "channel":
+			if id, err := y.NormalizeID(parts[1]); err == nil {
+				return id, "", nil
+			}
+			return "", "", ErrInvalidID
+		case len(parts) >= 2 && (parts[0] == "c" || parts[0] == "user"):
+			ref = parts[1]
+		case len(parts) >= 1 && strings.HasPrefix(parts[0], "@"):
+			ref = parts[0]
+		default:
+			return "", "", ErrInvalidID
+		}
+	}
+
+	handle = strings.ToLower(strings.TrimPrefix(ref, "@"))
+	if !youtubeHandleRe.MatchString(handle) {
+		return "", "", ErrInvalidID
+	}
+	return "", handle, nil
+}
+
+// ProfileByHandle looks a channel up by @handle, falling back to the legacy
+// username for old /user/ and /c/ URLs.
+func (y *YouTube) ProfileByHandle(ctx context.Context, handle string) (*Profile, error) {
+	it, err := y.channels(ctx, url.Values{"forHandle": {"@" + handle}})
+	if errors.Is(err, ErrNotFound) {
+		it, err = y.channels(ctx, url.Values{"forUsername": {handle}})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return it.profile(), nil
+}
+
+var youtubeVideoIDRe = regexp.MustCompile(`^[0-9A-Za-z_-]{11}$`)
+
+func (y *YouTube) NormalizeVideoID(raw string) (string, error) {
+	id := strings.TrimSpace(raw)
+	if !youtubeVideoIDRe.MatchString(id) {
+		return "", ErrInvalidID
+	}
+	return id, nil
+}
+
+// Calls YouTube Data API: GET /youtube/v3/videos?part=snippet&id=... (API key)
+func (y *YouTube) VideoChannelID(ctx context.Context, videoID string) (string, error) {
+	if y.apiKey == "" {
+		return "", ErrProfileUnavailable
+	}
+
+	q := url.Values{}
+	q.Set("part", "snippet")
+	q.Set("id", videoID)
+	q.Set("key", y.apiKey)
+
+	var out struct {
+		Items []struct {
+			Snippet struct {
+				ChannelID string `json:"channelId"`
+			} `json:"snippet"`
+		} `json:"items"`
+	}
+	if _, err := getJSON(ctx, "youtube", y.baseURL+"/videos?"+q.Encode(), nil, &out); err != nil {
+		return "", err
+	}
+	if len(out.Items) == 0 {
+		return "", ErrNotFound
+	}
+	return y.NormalizeID(out.Items[0].Snippet.ChannelID)
+}
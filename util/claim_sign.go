@@ -3,6 +3,7 @@ package util
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -25,6 +26,16 @@ func ChannelHash(channelID string) common.Hash {
 	return gethCrypto.Keccak256Hash([]byte(channelID))
 }
 
+// PlatformChannelHash is the escrow key for a creator on any platform.
+// YouTube keeps the bare channel ID so existing deposits stay claimable;
+// other platforms are namespaced ("twitch:alice") so ids can't collide.
+func PlatformChannelHash(platform, id string) common.Hash {
+	if platform == "" || platform == "youtube" {
+		return ChannelHash(id)
+	}
+	return ChannelHash(platform + ":" + id)
+}
+
 func randBytes32Hex() (common.Hash, error) {
 	b := make([]byte, 32)
 	if _, err := rand.Read(b); err != nil {
@@ -38,12 +49,13 @@ func BuildClaimPayload(
 	verifierPrivHex string,
 	chainID int64,
 	escrow common.Address,
+	platform string,
 	channelID string,
 	payout common.Address,
 	ttl time.Duration,
 ) (*ClaimSigResult, error) {
 
-	channelHash := ChannelHash(channelID)
+	channelHash := PlatformChannelHash(platform, channelID)
 
 	nonce, err := randBytes32Hex()
 	if err != nil {
@@ -80,6 +92,33 @@ func SignClaimEIP712(
 		return "", err
 	}
 
+	digest, err := ClaimDigest(chainID, verifyingContract, channelIDHash, payout, expiry, nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sig, err := gethCrypto.Sign(digest.Bytes(), priv)
+	if err != nil {
+		return "", err
+	}
+
+	// go-ethereum returns v as 0/1; Solidity ECDSA.recover expects 27/28
+	sig[64] += 27
+
+	return "0x" + hex.EncodeToString(sig), nil
+}
+
+// ClaimDigest is the EIP-712 digest of Claim(channelIdHash, payoutAddress, expiry, nonce)
+// that the verifier signs and the escrow checks.
+func ClaimDigest(
+	chainID int64,
+	verifyingContract common.Address,
+	channelIDHash common.Hash,
+	payout common.Address,
+	expiry int64,
+	nonce common.Hash,
+) (common.Hash, error) {
+
 	// EIP-712 typed data (must match Solidity domain + type)
 	td := apitypes.TypedData{
 		Types: apitypes.Types{
@@ -100,7 +139,7 @@ func SignClaimEIP712(
 		Domain: apitypes.TypedDataDomain{
 			Name:              "TipMNEE",
 			Version:           "1",
-			ChainId: math.NewHexOrDecimal256(chainID),
+			ChainId:           math.NewHexOrDecimal256(chainID),
 			VerifyingContract: verifyingContract.Hex(),
 		},
 		Message: apitypes.TypedDataMessage{
@@ -113,27 +152,37 @@ func SignClaimEIP712(
 
 	structHash, err := td.HashStruct(td.PrimaryType, td.Message)
 	if err != nil {
-		return "", err
+		return common.Hash{}, err
 	}
 	domainSep, err := td.HashStruct("EIP712Domain", td.Domain.Map())
 	if err != nil {
-		return "", err
+		return common.Hash{}, err
 	}
 
 	// digest = keccak256("\x19\x01" || domainSep || structHash)
-	digest := gethCrypto.Keccak256Hash(
+	return gethCrypto.Keccak256Hash(
 		[]byte{0x19, 0x01},
 		domainSep,
 		structHash,
-	)
+	), nil
+}
 
-	sig, err := gethCrypto.Sign(digest.Bytes(), priv)
+// RecoverClaimSigner returns the address that produced a claim signature
+// (v = 27/28, as SignClaimEIP712 returns it) over digest.
+func RecoverClaimSigner(digest common.Hash, signatureHex string) (common.Address, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signatureHex, "0x"))
 	if err != nil {
-		return "", err
+		return common.Address{}, err
 	}
-
-	// go-ethereum returns v as 0/1; Solidity ECDSA.recover expects 27/28
-	sig[64] += 27
-
-	return "0x" + hex.EncodeToString(sig), nil
+	if len(sig) != 65 {
+		return common.Address{}, errors.New("invalid signature length")
+	}
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := gethCrypto.SigToPub(digest.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return gethCrypto.PubkeyToAddress(*pub), nil
 }
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const eip1271ABIJSON = `[{"type":"function","name":"isValidSignature","stateMutability":"view",
+"inputs":[{"name":"hash","type":"bytes32"},{"name":"signature","type":"bytes"}],
+"outputs":[{"name":"magicValue","type":"bytes4"}]}]`
+
+// eip1271MagicValue is bytes4(keccak256("isValidSignature(bytes32,bytes)")).
+var eip1271MagicValue = []byte{0x16, 0x26, 0xba, 0x7e}
+
+var eip1271ABI = func() abi.ABI {
+	a, err := abi.JSON(strings.NewReader(eip1271ABIJSON))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}()
+
+// PersonalSignHash is the digest a wallet signs for personal_sign(message).
+func PersonalSignHash(message string) common.Hash {
+	prefixed := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)
+	return crypto.Keccak256Hash([]byte(prefixed))
+}
+
+// ChainReader is the part of ethclient.Client needed for contract wallets.
+type ChainReader interface {
+	ethereum.ContractCaller
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// IsContract reports whether addr has code deployed.
+func IsContract(ctx context.Context, client ChainReader, addr common.Address) (bool, error) {
+	code, err := client.CodeAt(ctx, addr, nil)
+	if err != nil {
+		return false, err
+	}
+	return len(code) > 0, nil
+}
+
+// ErrInvalidSignature is returned for a signature that isn't hex-encoded
+// bytes, before any chain call is made.
+var ErrInvalidSignature = errors.New("invalid signature encoding")
+
+// DecodeSignature decodes a hex signature, with or without 0x.
+func DecodeSignature(signatureHex string) ([]byte, error) {
+	raw := strings.TrimPrefix(strings.TrimSpace(signatureHex), "0x")
+	sig, err := hex.DecodeString(raw)
+	if err != nil || len(sig) == 0 {
+		return nil, ErrInvalidSignature
+	}
+	return sig, nil
+}
+
+// VerifyEIP1271 asks a contract wallet whether signature is valid for the
+// personal_sign digest of message. A malformed signature fails with
+// ErrInvalidSignature; any other error is the chain call's.
+func VerifyEIP1271(ctx context.Context, client ChainReader, wallet common.Address, message, signatureHex string) (bool, error) {
+	sig, err := DecodeSignature(signatureHex)
+	if err != nil {
+		return false, err
+	}
+
+	data, err := eip1271ABI.Pack("isValidSignature", PersonalSignHash(message), sig)
+	if err != nil {
+		return false, err
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &wallet, Data: data}, nil)
+	if err != nil {
+		// Reverting is how many wallets say "invalid".
+		if strings.Contains(err.Error(), "revert") {
+			return false, nil
+		}
+		return false, err
+	}
+	return len(out) >= 4 && bytes.Equal(out[:4], eip1271MagicValue), nil
+}
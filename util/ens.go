@@ -0,0 +1,182 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ENSRegistryAddress is the ENS registry on mainnet and the public testnets.
+var ENSRegistryAddress = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+
+// ErrENSNotFound means the name has no resolver or no address record.
+var ErrENSNotFound = errors.New("ens name does not resolve to an address")
+
+const ensABIJSON = `[
+  {"type":"function","name":"resolver","stateMutability":"view",
+   "inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]},
+  {"type":"function","name":"addr","stateMutability":"view",
+   "inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]},
+  {"type":"function","name":"name","stateMutability":"view",
+   "inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"string"}]}
+]`
+
+var ensABI = func() abi.ABI {
+	a, err := abi.JSON(strings.NewReader(ensABIJSON))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}()
+
+// ENSResolver looks up ENS names; ENS implements it on chain, tests can stub it.
+type ENSResolver interface {
+	// ResolveName returns the address record of name, or ErrENSNotFound.
+	ResolveName(ctx context.Context, name string) (common.Address, error)
+	// LookupAddress returns the primary name of addr, or "" when it has none
+	// or the name does not resolve back to addr.
+	LookupAddress(ctx context.Context, addr common.Address) (string, error)
+}
+
+// ENS resolves names through an ENS registry: ENSRegistryAddress, or a mock
+// registry deployed on a dev chain.
+type ENS struct {
+	client   ethereum.ContractCaller
+	registry common.Address
+}
+
+func NewENS(client ethereum.ContractCaller, registry common.Address) *ENS {
+	return &ENS{client: client, registry: registry}
+}
+
+var ensLabel = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// NormalizeENSName lowercases name and checks it is a dotted ASCII name such
+// as alice.eth. Other Unicode names are refused rather than normalized.
+func NormalizeENSName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return "", fmt.Errorf("invalid ens name %q", name)
+	}
+	for _, l := range labels {
+		if !ensLabel.MatchString(l) {
+			return "", fmt.Errorf("invalid ens name %q", name)
+		}
+	}
+	return name, nil
+}
+
+// IsENSName reports whether s looks like a name rather than a 0x address.
+func IsENSName(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.Contains(s, ".") && !common.IsHexAddress(s)
+}
+
+// ENSNamehash is the EIP-137 namehash of an already normalized name.
+func ENSNamehash(name string) common.Hash {
+	node := common.Hash{}
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		label := crypto.Keccak256([]byte(labels[i]))
+		node = crypto.Keccak256Hash(node.Bytes(), label)
+	}
+	return node
+}
+
+func (e *ENS) call(ctx context.Context, to common.Address, method string, node common.Hash) ([]interface{}, error) {
+	data, err := ensABI.Pack(method, node)
+	if err != nil {
+		return nil, err
+	}
+	out, err := e.client.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		// No contract (or no such function) at to.
+		return nil, ErrENSNotFound
+	}
+	return ensABI.Unpack(method, out)
+}
+
+func (e *ENS) resolver(ctx context.Context, node common.Hash) (common.Address, error) {
+	out, err := e.call(ctx, e.registry, "resolver", node)
+	if err != nil {
+		return common.Address{}, err
+	}
+	resolver := out[0].(common.Address)
+	if resolver == (common.Address{}) {
+		return common.Address{}, ErrENSNotFound
+	}
+	return resolver, nil
+}
+
+func (e *ENS) ResolveName(ctx context.Context, name string) (common.Address, error) {
+	name, err := NormalizeENSName(name)
+	if err != nil {
+		return common.Address{}, err
+	}
+	node := ENSNamehash(name)
+
+	resolver, err := e.resolver(ctx, node)
+	if err != nil {
+		return common.Address{}, err
+	}
+	out, err := e.call(ctx, resolver, "addr", node)
+	if err != nil {
+		return common.Address{}, err
+	}
+	addr := out[0].(common.Address)
+	if addr == (common.Address{}) {
+		return common.Address{}, ErrENSNotFound
+	}
+	return addr, nil
+}
+
+func (e *ENS) LookupAddress(ctx context.Context, addr common.Address) (string, error) {
+	node := ENSNamehash(strings.ToLower(addr.Hex()[2:]) + ".addr.reverse")
+
+	resolver, err := e.resolver(ctx, node)
+	if errors.Is(err, ErrENSNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	out, err := e.call(ctx, resolver, "name", node)
+	if errors.Is(err, ErrENSNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Anyone can claim any name in their reverse record; only trust it when
+	// the name points back at addr.
+	name, err := NormalizeENSName(out[0].(string))
+	if err != nil {
+		return "", nil
+	}
+	forward, err := e.ResolveName(ctx, name)
+	if errors.Is(err, ErrENSNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if forward != addr {
+		return "", nil
+	}
+	return name, nil
+}
@@ -0,0 +1,307 @@
+package util
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const DefaultGoogleIssuer = "https://accounts.google.com"
+
+// DefaultGoogleTokenInfoURL reports which client an access token was issued to.
+const DefaultGoogleTokenInfoURL = "https://oauth2.googleapis.com/tokeninfo"
+
+// jwksMinRefresh is how long an unknown kid waits before it can trigger
+// another JWKS fetch, so tokens with made-up kids cannot hammer the provider.
+const jwksMinRefresh = time.Minute
+
+// OIDCIdentity is the subset of an ID token / userinfo response we care about.
+type OIDCIdentity struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified,omitempty"`
+	Name          string `json:"name,omitempty"`
+	// IssuedAt is the ID token's iat; zero for access tokens.
+	IssuedAt time.Time `json:"-"`
+}
+
+// GoogleVerifier turns a Google credential into a stable subject. The default
+// implementation talks to Google; tests can point it at a local OIDC stand-in
+// or swap in their own.
+type GoogleVerifier interface {
+	// VerifyIDToken checks signature, issuer, expiry and audience.
+	VerifyIDToken(ctx context.Context, raw string) (*OIDCIdentity, error)
+	// VerifyAccessToken checks that an OAuth access token was issued to one
+	// of our client ids and resolves it through the userinfo endpoint.
+	VerifyAccessToken(ctx context.Context, accessToken string) (*OIDCIdentity, error)
+}
+
+// OIDCVerifier verifies ID tokens against the provider's discovery document
+// and JWKS. Keys are cached and refreshed when an unknown kid shows up, at
+// most once per jwksMinRefresh.
+type OIDCVerifier struct {
+	issuer       string
+	audiences    []string
+	tokenInfoURL string
+	client       *http.Client
+
+	mu          sync.Mutex
+	jwksURI     string
+	userinfoURI string
+	keys        map[string]*rsa.PublicKey
+	fetchedAt   time.Time
+}
+
+// NewOIDCVerifier checks access tokens against Google's tokeninfo endpoint,
+// or issuer+"/tokeninfo" for any other issuer (a local stand-in).
+func NewOIDCVerifier(issuer string, audiences []string) *OIDCVerifier {
+	issuer = strings.TrimRight(strings.TrimSpace(issuer), "/")
+	if issuer == "" {
+		issuer = DefaultGoogleIssuer
+	}
+	tokenInfoURL := DefaultGoogleTokenInfoURL
+	if issuer != DefaultGoogleIssuer {
+		tokenInfoURL = issuer + "/tokeninfo"
+	}
+	return &OIDCVerifier{
+		issuer:       issuer,
+		audiences:    audiences,
+		tokenInfoURL: tokenInfoURL,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		keys:         map[string]*rsa.PublicKey{},
+	}
+}
+
+type oidcDiscovery struct {
+	Issuer           string `json:"issuer"`
+	JWKSURI          string `json:"jwks_uri"`
+	UserinfoEndpoint string `json:"userinfo_endpoint"`
+}
+
+type jwkSet struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+func (v *OIDCVerifier) getJSON(ctx context.Context, url, bearer string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %d", url, resp.StatusCode)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// discover loads the discovery document once. Caller must hold v.mu.
+func (v *OIDCVerifier) discover(ctx context.Context) error {
+	if v.jwksURI != "" {
+		return nil
+	}
+	var d oidcDiscovery
+	if err := v.getJSON(ctx, v.issuer+"/.well-known/openid-configuration", "", &d); err != nil {
+		return fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	if d.JWKSURI == "" {
+		return errors.New("oidc discovery missing jwks_uri")
+	}
+	v.jwksURI = d.JWKSURI
+	v.userinfoURI = d.UserinfoEndpoint
+	return nil
+}
+
+// refreshKeys reloads the JWKS. Caller must hold v.mu.
+func (v *OIDCVerifier) refreshKeys(ctx context.Context) error {
+	var set jwkSet
+	if err := v.getJSON(ctx, v.jwksURI, "", &set); err != nil {
+		return fmt.Errorf("jwks fetch failed: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		nb, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		eb, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(nb),
+			E: int(new(big.Int).SetBytes(eb).Int64()),
+		}
+	}
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	return nil
+}
+
+func (v *OIDCVerifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := v.discover(ctx); err != nil {
+		return nil, err
+	}
+	k, ok := v.keys[kid]
+	if ok && time.Since(v.fetchedAt) < time.Hour {
+		return k, nil
+	}
+	if !ok && time.Since(v.fetchedAt) < jwksMinRefresh {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if err := v.refreshKeys(ctx); err != nil {
+		return nil, err
+	}
+	k, ok = v.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return k, nil
+}
+
+// issuerOK accepts Google's bare-host issuer alongside the https form.
+func (v *OIDCVerifier) issuerOK(iss string) bool {
+	if iss == v.issuer {
+		return true
+	}
+	return v.issuer == DefaultGoogleIssuer && iss == "accounts.google.com"
+}
+
+func (v *OIDCVerifier) VerifyIDToken(ctx context.Context, raw string) (*OIDCIdentity, error) {
+	// If you have multiple client IDs (web + extension), any of them is accepted.
+	if len(v.audiences) == 0 {
+		return nil, errors.New("missing GOOGLE_CLIENT_ID(S)")
+	}
+
+	type idClaims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+		jwt.RegisteredClaims
+	}
+
+	var claims idClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if !v.issuerOK(claims.Issuer) {
+		return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+
+	if !v.audienceOK(claims.Audience...) {
+		return nil, errors.New("token audience not accepted")
+	}
+
+	ident := &OIDCIdentity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}
+	if claims.IssuedAt != nil {
+		ident.IssuedAt = claims.IssuedAt.Time
+	}
+	return ident, nil
+}
+
+func (v *OIDCVerifier) audienceOK(got ...string) bool {
+	for _, want := range v.audiences {
+		for _, g := range got {
+			if g == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type tokenInfo struct {
+	Aud string `json:"aud"`
+	Azp string `json:"azp"`
+	Sub string `json:"sub"`
+}
+
+// VerifyAccessToken refuses access tokens issued to other clients: userinfo
+// alone would accept a token any third-party app obtained for the user.
+func (v *OIDCVerifier) VerifyAccessToken(ctx context.Context, accessToken string) (*OIDCIdentity, error) {
+	if len(v.audiences) == 0 {
+		return nil, errors.New("missing GOOGLE_CLIENT_ID(S)")
+	}
+
+	var info tokenInfo
+	if err := v.getJSON(ctx, v.tokenInfoURL+"?access_token="+url.QueryEscape(accessToken), "", &info); err != nil {
+		return nil, fmt.Errorf("tokeninfo failed: %w", err)
+	}
+	if !v.audienceOK(info.Aud) {
+		return nil, errors.New("token audience not accepted")
+	}
+
+	ident, err := v.userInfo(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if info.Sub != "" && info.Sub != ident.Subject {
+		return nil, errors.New("tokeninfo and userinfo subjects differ")
+	}
+	return ident, nil
+}
+
+func (v *OIDCVerifier) userInfo(ctx context.Context, accessToken string) (*OIDCIdentity, error) {
+	v.mu.Lock()
+	err := v.discover(ctx)
+	userinfoURI := v.userinfoURI
+	v.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if userinfoURI == "" {
+		return nil, errors.New("oidc discovery missing userinfo_endpoint")
+	}
+
+	var out OIDCIdentity
+	if err := v.getJSON(ctx, userinfoURI, accessToken, &out); err != nil {
+		return nil, fmt.Errorf("userinfo failed: %w", err)
+	}
+	return &out, nil
+}
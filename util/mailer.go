@@ -0,0 +1,63 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Mailer delivers security notices outside the app, to people who may not be
+// able to sign in (for example because their wallet is lost or stolen).
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// SMTPMailer sends plain-text mail through one SMTP relay, using STARTTLS
+// and PLAIN auth when the relay offers them.
+type SMTPMailer struct {
+	addr     string
+	from     string
+	username string
+	password string
+}
+
+func NewSMTPMailer(addr, from, username, password string) *SMTPMailer {
+	return &SMTPMailer{addr: addr, from: from, username: username, password: password}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, to, subject, body string) error {
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return fmt.Errorf("mail header contains a line break")
+	}
+
+	host, _, err := net.SplitHostPort(m.addr)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, host)
+	}
+
+	msg := "From: " + m.from + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Date: " + time.Now().UTC().Format(time.RFC1123Z) + "\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" +
+		strings.ReplaceAll(body, "\n", "\r\n") + "\r\n"
+
+	// smtp.SendMail takes no context; run it aside so ctx still bounds the wait.
+	done := make(chan error, 1)
+	go func() { done <- smtp.SendMail(m.addr, auth, m.from, []string{to}, []byte(msg)) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,151 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultGoogleAuthURL  = "https://accounts.google.com/o/oauth2/v2/auth"
+	DefaultGoogleTokenURL = "https://oauth2.googleapis.com/token"
+)
+
+// OAuthClient runs the authorization-code flow with PKCE against one provider.
+// AuthURL and TokenURL are configurable so tests can use a local stand-in.
+type OAuthClient struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+	RedirectURL  string
+	Scopes       []string
+
+	client *http.Client
+}
+
+func NewOAuthClient(clientID, clientSecret, authURL, tokenURL, redirectURL string, scopes []string) *OAuthClient {
+	if strings.TrimSpace(authURL) == "" {
+		authURL = DefaultGoogleAuthURL
+	}
+	if strings.TrimSpace(tokenURL) == "" {
+		tokenURL = DefaultGoogleTokenURL
+	}
+	return &OAuthClient{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AuthURL:      authURL,
+		TokenURL:     tokenURL,
+		RedirectURL:  redirectURL,
+		Scopes:       scopes,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Scope        string `json:"scope"`
+	TokenType    string `json:"token_type"`
+}
+
+// NewPKCEVerifier returns a random code_verifier (RFC 7636, 43 chars).
+func NewPKCEVerifier() (string, error) {
+	b, err := randBytes32Hex()
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b.Bytes()), nil
+}
+
+// PKCEChallenge is the S256 code_challenge for verifier.
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL builds the consent URL. access_type=offline + prompt=consent
+// make Google return a refresh token every time.
+func (o *OAuthClient) AuthCodeURL(state, verifier string) string {
+	q := url.Values{}
+	q.Set("client_id", o.ClientID)
+	q.Set("redirect_uri", o.RedirectURL)
+	q.Set("response_type", "code")
+	q.Set("scope", strings.Join(o.Scopes, " "))
+	q.Set("state", state)
+	q.Set("code_challenge", PKCEChallenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	q.Set("access_type", "offline")
+	q.Set("prompt", "consent")
+
+	sep := "?"
+	if strings.Contains(o.AuthURL, "?") {
+		sep = "&"
+	}
+	return o.AuthURL + sep + q.Encode()
+}
+
+// Exchange trades an authorization code for tokens.
+func (o *OAuthClient) Exchange(ctx context.Context, code, verifier string) (*OAuthToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("code_verifier", verifier)
+	form.Set("redirect_uri", o.RedirectURL)
+	return o.token(ctx, form)
+}
+
+// Refresh gets a new access token from a stored refresh token.
+func (o *OAuthClient) Refresh(ctx context.Context, refreshToken string) (*OAuthToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	return o.token(ctx, form)
+}
+
+func (o *OAuthClient) token(ctx context.Context, form url.Values) (*OAuthToken, error) {
+	form.Set("client_id", o.ClientID)
+	form.Set("client_secret", o.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		_ = json.Unmarshal(body, &e)
+		if e.Error == "" {
+			e.Error = resp.Status
+		}
+		return nil, fmt.Errorf("token endpoint error: %s %s", e.Error, e.Description)
+	}
+
+	var tok OAuthToken
+	if err := json.Unmarshal(body, &tok); err != nil {
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return nil, fmt.Errorf("token response missing access_token")
+	}
+	return &tok, nil
+}
@@ -0,0 +1,242 @@
+package util
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RelayFeeBumpPercent is how much a replacement raises both fee caps; nodes
+// refuse replacements that bump by less than 10%.
+const RelayFeeBumpPercent = 25
+
+// ErrRelayFeeCap means sending (or replacing) would need a fee cap above the
+// relayer's maximum; try again when gas is cheaper.
+var ErrRelayFeeCap = errors.New("relay fee cap reached")
+
+// RelayChain is what Relayer needs from a node. *ethclient.Client implements
+// it, and so does the client of go-ethereum's simulated backend.
+type RelayChain interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Relayer signs and sends EIP-1559 transactions from a hot wallet. It holds
+// no state between calls: callers persist nonces and attempts, so a restart
+// can pick up where it left off.
+type Relayer struct {
+	chain     RelayChain
+	key       *ecdsa.PrivateKey
+	Address   common.Address
+	chainID   *big.Int
+	signer    types.Signer
+	maxFeeCap *big.Int
+}
+
+// NewRelayer never pays more than maxFeeCap wei per gas.
+func NewRelayer(chain RelayChain, privHex string, chainID int64, maxFeeCap *big.Int) (*Relayer, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(privHex, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	id := big.NewInt(chainID)
+	return &Relayer{
+		chain:     chain,
+		key:       key,
+		Address:   crypto.PubkeyToAddress(key.PublicKey),
+		chainID:   id,
+		signer:    types.LatestSignerForChainID(id),
+		maxFeeCap: maxFeeCap,
+	}, nil
+}
+
+// IsRevert reports whether err is the node refusing a call that reverts, as
+// opposed to the node being unreachable.
+func IsRevert(err error) bool {
+	if err == nil {
+		return false
+	}
+	var de rpc.DataError
+	return errors.As(err, &de) || strings.Contains(err.Error(), "execution reverted")
+}
+
+// IsKnownTx reports whether err is the node refusing a transaction it
+// already has, which counts as accepted.
+func IsKnownTx(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "already known")
+}
+
+// NextNonce is the first nonce after both the node's pending transactions and
+// floor (the caller's own record of transactions in flight).
+func (r *Relayer) NextNonce(ctx context.Context, floor uint64) (uint64, error) {
+	pending, err := r.chain.PendingNonceAt(ctx, r.Address)
+	if err != nil {
+		return 0, err
+	}
+	if floor > pending {
+		return floor, nil
+	}
+	return pending, nil
+}
+
+// Estimate is the gas the relayer needs to call to with data; a call that
+// would revert fails here (see IsRevert).
+func (r *Relayer) Estimate(ctx context.Context, to common.Address, data []byte) (uint64, error) {
+	return r.chain.EstimateGas(ctx, ethereum.CallMsg{From: r.Address, To: &to, Data: data})
+}
+
+func (r *Relayer) baseFee(ctx context.Context) (*big.Int, error) {
+	head, err := r.chain.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if head.BaseFee == nil {
+		return new(big.Int), nil
+	}
+	return head.BaseFee, nil
+}
+
+// fees prices a transaction at twice the current base fee plus the suggested
+// tip. When it replaces one the node accepted at (tipCap, feeCap), both caps
+// are also raised by at least RelayFeeBumpPercent; nil caps mean there is
+// nothing to outbid.
+func (r *Relayer) fees(ctx context.Context, tipCap, feeCap *big.Int) (*big.Int, *big.Int, error) {
+	tip, err := r.chain.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	base, err := r.baseFee(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var minFee *big.Int
+	if feeCap == nil {
+		if base.Cmp(r.maxFeeCap) > 0 {
+			return nil, nil, ErrRelayFeeCap
+		}
+	} else {
+		if bumped := bumpFee(tipCap); bumped.Cmp(tip) > 0 {
+			tip = bumped
+		}
+		// The smallest accepted replacement may already be too expensive.
+		minFee = bumpFee(feeCap)
+		if minFee.Cmp(r.maxFeeCap) > 0 {
+			return nil, nil, ErrRelayFeeCap
+		}
+	}
+
+	fee := new(big.Int).Add(new(big.Int).Mul(base, big.NewInt(2)), tip)
+	if minFee != nil && minFee.Cmp(fee) > 0 {
+		fee = minFee
+	}
+	if fee.Cmp(r.maxFeeCap) > 0 {
+		fee = new(big.Int).Set(r.maxFeeCap)
+	}
+	if tip.Cmp(fee) > 0 {
+		tip = new(big.Int).Set(fee)
+	}
+	return tip, fee, nil
+}
+
+// NewTx signs a call to to with data at nonce, priced at the current market.
+// It is not sent.
+func (r *Relayer) NewTx(ctx context.Context, nonce uint64, to common.Address, data []byte) (*types.Transaction, error) {
+	gas, err := r.Estimate(ctx, to, data)
+	if err != nil {
+		return nil, err
+	}
+	tip, fee, err := r.fees(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.sign(nonce, gas, to, data, tip, fee)
+}
+
+// Replacement signs the same call at the same nonce, outbidding the accepted
+// transaction priced at (tipCap, feeCap) so it takes that one's place. With
+// nil caps (no attempt was accepted) it is priced at the current market.
+func (r *Relayer) Replacement(ctx context.Context, nonce, gas uint64, to common.Address, data []byte, tipCap, feeCap *big.Int) (*types.Transaction, error) {
+	tip, fee, err := r.fees(ctx, tipCap, feeCap)
+	if err != nil {
+		return nil, err
+	}
+	return r.sign(nonce, gas, to, data, tip, fee)
+}
+
+// Cancellation signs a 0-value transfer from the relayer to itself at nonce,
+// priced like Replacement, so a transaction that must no longer be mined is
+// dropped and the nonce still gets used.
+func (r *Relayer) Cancellation(ctx context.Context, nonce uint64, tipCap, feeCap *big.Int) (*types.Transaction, error) {
+	tip, fee, err := r.fees(ctx, tipCap, feeCap)
+	if err != nil {
+		return nil, err
+	}
+	return r.sign(nonce, params.TxGas, r.Address, nil, tip, fee)
+}
+
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+RelayFeeBumpPercent))
+	bumped.Add(bumped, big.NewInt(99)) // round up
+	return bumped.Quo(bumped, big.NewInt(100))
+}
+
+func (r *Relayer) sign(nonce, gas uint64, to common.Address, data []byte, tip, feeCap *big.Int) (*types.Transaction, error) {
+	return types.SignNewTx(r.key, r.signer, &types.DynamicFeeTx{
+		ChainID:   r.chainID,
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       gas,
+		To:        &to,
+		Data:      data,
+	})
+}
+
+func (r *Relayer) Send(ctx context.Context, tx *types.Transaction) error {
+	return r.chain.SendTransaction(ctx, tx)
+}
+
+// Receipt returns the receipt of hash and how many blocks deep it is (1 in
+// the latest block), or a nil receipt while it is not mined.
+func (r *Relayer) Receipt(ctx context.Context, hash common.Hash) (*types.Receipt, uint64, error) {
+	rc, err := r.chain.TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	head, err := r.chain.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if head.Number.Cmp(rc.BlockNumber) < 0 {
+		return rc, 0, nil
+	}
+	return rc, new(big.Int).Sub(head.Number, rc.BlockNumber).Uint64() + 1, nil
+}
+
+// NonceUsed reports whether a transaction from the relayer with nonce has
+// been mined.
+func (r *Relayer) NonceUsed(ctx context.Context, nonce uint64) (bool, error) {
+	mined, err := r.chain.NonceAt(ctx, r.Address, nil)
+	if err != nil {
+		return false, err
+	}
+	return mined > nonce, nil
+}
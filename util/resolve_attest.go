@@ -0,0 +1,174 @@
+package util
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	gethCrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// ResolveAttestation lets the extension check a resolve answer offline: the
+// resolver key signs EIP-712 PayoutAttestation(channelIdHash, payoutAddress,
+// chainId, expiry). A zero payoutAddress means "unclaimed, use escrow".
+type ResolveAttestation struct {
+	Signer        string `json:"signer"`
+	ChainID       int64  `json:"chain_id"`
+	ChannelIDHash string `json:"channel_id_hash"`
+	PayoutAddress string `json:"payout_address"`
+	Expiry        int64  `json:"expiry"`
+	Signature     string `json:"signature"`
+}
+
+type ResolveAttestor struct {
+	key     *ecdsa.PrivateKey
+	Address common.Address
+	ChainID int64
+	TTL     time.Duration
+}
+
+func NewResolveAttestor(privHex string, chainID int64, ttl time.Duration) (*ResolveAttestor, error) {
+	key, err := gethCrypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(privHex), "0x"))
+	if err != nil {
+		return nil, err
+	}
+	return &ResolveAttestor{
+		key:     key,
+		Address: gethCrypto.PubkeyToAddress(key.PublicKey),
+		ChainID: chainID,
+		TTL:     ttl,
+	}, nil
+}
+
+// Attest signs the mapping. Signatures are deterministic (RFC 6979), so with
+// the rounded expiry repeated answers stay identical.
+func (a *ResolveAttestor) Attest(channelIDHash common.Hash, payout common.Address) (*ResolveAttestation, error) {
+	expiry := a.expiry()
+	sig, err := a.sign("PayoutAttestation", apitypes.Types{
+		"PayoutAttestation": {
+			{Name: "channelIdHash", Type: "bytes32"},
+			{Name: "payoutAddress", Type: "address"},
+			{Name: "chainId", Type: "uint256"},
+			{Name: "expiry", Type: "uint256"},
+		},
+	}, apitypes.TypedDataMessage{
+		"channelIdHash": channelIDHash.Hex(),
+		"payoutAddress": payout.Hex(),
+		"chainId":       fmt.Sprintf("%d", a.ChainID),
+		"expiry":        fmt.Sprintf("%d", expiry),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResolveAttestation{
+		Signer:        a.Address.Hex(),
+		ChainID:       a.ChainID,
+		ChannelIDHash: channelIDHash.Hex(),
+		PayoutAddress: payout.Hex(),
+		Expiry:        expiry,
+		Signature:     sig,
+	}, nil
+}
+
+// SplitShare is one recipient of a split answer.
+type SplitShare struct {
+	Account common.Address `json:"account"`
+	Bps     int64          `json:"bps"`
+}
+
+// SplitAttestation signs a split answer: EIP-712
+// SplitAttestation(channelIdHash, SplitRecipient[] recipients, chainId, expiry)
+// with SplitRecipient(address account, uint256 bps), in the same domain.
+type SplitAttestation struct {
+	Signer        string       `json:"signer"`
+	ChainID       int64        `json:"chain_id"`
+	ChannelIDHash string       `json:"channel_id_hash"`
+	Recipients    []SplitShare `json:"recipients"`
+	Expiry        int64        `json:"expiry"`
+	Signature     string       `json:"signature"`
+}
+
+func (a *ResolveAttestor) AttestSplit(channelIDHash common.Hash, shares []SplitShare) (*SplitAttestation, error) {
+	expiry := a.expiry()
+	recipients := make([]interface{}, len(shares))
+	for i, sh := range shares {
+		recipients[i] = map[string]interface{}{
+			"account": sh.Account.Hex(),
+			"bps":     fmt.Sprintf("%d", sh.Bps),
+		}
+	}
+	sig, err := a.sign("SplitAttestation", apitypes.Types{
+		"SplitAttestation": {
+			{Name: "channelIdHash", Type: "bytes32"},
+			{Name: "recipients", Type: "SplitRecipient[]"},
+			{Name: "chainId", Type: "uint256"},
+			{Name: "expiry", Type: "uint256"},
+		},
+		"SplitRecipient": {
+			{Name: "account", Type: "address"},
+			{Name: "bps", Type: "uint256"},
+		},
+	}, apitypes.TypedDataMessage{
+		"channelIdHash": channelIDHash.Hex(),
+		"recipients":    recipients,
+		"chainId":       fmt.Sprintf("%d", a.ChainID),
+		"expiry":        fmt.Sprintf("%d", expiry),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SplitAttestation{
+		Signer:        a.Address.Hex(),
+		ChainID:       a.ChainID,
+		ChannelIDHash: channelIDHash.Hex(),
+		Recipients:    shares,
+		Expiry:        expiry,
+		Signature:     sig,
+	}, nil
+}
+
+// expiry is rounded down to a quarter of the TTL so repeated answers (and
+// their ETags) stay identical for a while.
+func (a *ResolveAttestor) expiry() int64 {
+	window := a.TTL / 4
+	if window <= 0 {
+		window = time.Second
+	}
+	return time.Now().Truncate(window).Add(a.TTL).Unix()
+}
+
+func (a *ResolveAttestor) sign(primary string, types apitypes.Types, msg apitypes.TypedDataMessage) (string, error) {
+	types["EIP712Domain"] = []apitypes.Type{
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+	}
+	td := apitypes.TypedData{
+		Types:       types,
+		PrimaryType: primary,
+		Domain: apitypes.TypedDataDomain{
+			Name:    "TipMNEE Resolver",
+			Version: "1",
+			ChainId: math.NewHexOrDecimal256(a.ChainID),
+		},
+		Message: msg,
+	}
+
+	digest, _, err := apitypes.TypedDataAndHash(td)
+	if err != nil {
+		return "", err
+	}
+	sig, err := gethCrypto.Sign(digest, a.key)
+	if err != nil {
+		return "", err
+	}
+	sig[64] += 27
+	return "0x" + hex.EncodeToString(sig), nil
+}
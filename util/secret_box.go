@@ -0,0 +1,58 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// SecretBox encrypts small secrets (OAuth refresh tokens) at rest with
+// AES-256-GCM. Ciphertexts are nonce || sealed. The caller passes the key of
+// the row the secret is stored in as additional data, so a ciphertext copied
+// into another row no longer opens.
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBox takes a 32-byte key as 64 hex chars.
+func NewSecretBox(keyHex string) (*SecretBox, error) {
+	key, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(keyHex), "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, errors.New("secret key must be 32 bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretBox{aead: aead}, nil
+}
+
+func (b *SecretBox) Seal(plaintext string, rowKey []byte) ([]byte, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return b.aead.Seal(nonce, nonce, []byte(plaintext), rowKey), nil
+}
+
+func (b *SecretBox) Open(ciphertext, rowKey []byte) (string, error) {
+	n := b.aead.NonceSize()
+	if len(ciphertext) < n {
+		return "", errors.New("ciphertext too short")
+	}
+	pt, err := b.aead.Open(nil, ciphertext[:n], ciphertext[n:], rowKey)
+	if err != nil {
+		return "", err
+	}
+	return string(pt), nil
+}